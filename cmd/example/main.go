@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/handlers"
 	"github.com/pavel-one/day-of-the-bot/internal/templates"
 )
 
@@ -27,7 +28,8 @@ func main() {
 	fmt.Println()
 
 	fmt.Println("1. Справка:")
-	fmt.Println(service.HelpText())
+	commandHandler := handlers.NewCommandHandler(nil, nil, nil, nil, nil, nil, nil, nil, service, nil, nil, nil)
+	fmt.Println(service.HelpText(commandHandler.HelpCommands()))
 	fmt.Println()
 
 	fmt.Println("2. Пидор дня выбран:")
@@ -56,7 +58,7 @@ func main() {
 	fmt.Println()
 
 	fmt.Println("7. Ошибка:")
-	fmt.Println(service.ErrorOccurred("при подключении к базе данных"))
+	fmt.Println(service.ErrorOccurred(templates.ErrorDatabase))
 	fmt.Println()
 
 	fmt.Println("8. Неизвестная команда:")
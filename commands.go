@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/backup"
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/config"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/health"
+	"github.com/pavel-one/day-of-the-bot/internal/history"
+	"github.com/pavel-one/day-of-the-bot/internal/logging"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+)
+
+// loadStorageConfig загружает конфигурацию для команд обслуживания базы.
+// Проверяются только настройки хранилища: токен бота этим командам не нужен.
+func loadStorageConfig(path string) (*config.Config, *slog.Logger, error) {
+	cfg, err := config.Read(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка загрузки конфигурации: %w", err)
+	}
+	if err := cfg.Storage.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("ошибки конфигурации хранилища:\n%w", err)
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.LogLevel(), cfg.Log.Format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ошибка создания логгера: %w", err)
+	}
+
+	return cfg, logger, nil
+}
+
+// requireSQLite проверяет, что команда вызвана для хранилища SQLite
+func requireSQLite(cfg *config.Config) error {
+	if !repository.IsSQLite(cfg.Storage.Driver) {
+		return fmt.Errorf("команда поддерживается только для SQLite, для %s используйте pg_dump", cfg.Storage.Driver)
+	}
+	return nil
+}
+
+// openStorage открывает базу данных из конфигурации
+func openStorage(cfg *config.Config, logger *slog.Logger) (*repository.Database, error) {
+	db, err := repository.Open(cfg.Storage.Driver, cfg.Storage.DataSource(), cfg.Storage.Options(), logger)
+	if err != nil {
+		return nil, fmt.Errorf("ошибка открытия базы данных: %w", err)
+	}
+	return db, nil
+}
+
+// closeStorage закрывает базу данных, ошибка только логируется
+func closeStorage(db *repository.Database, logger *slog.Logger) {
+	if err := db.Close(); err != nil {
+		logger.Error("Ошибка закрытия базы данных", "error", err)
+	}
+}
+
+// runBackup создает резервную копию базы в каталоге из аргумента или из backup.dir
+// и удаляет копии сверх backup.retention
+func runBackup(configPath string, args []string) int {
+	if len(args) > 1 {
+		fmt.Fprintln(os.Stderr, "Использование: bot backup [каталог]")
+		return 2
+	}
+
+	cfg, logger, err := loadStorageConfig(configPath)
+	if err == nil {
+		err = requireSQLite(cfg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dir := cfg.Backup.Dir
+	if len(args) == 1 {
+		dir = args[0]
+	}
+
+	db, err := openStorage(cfg, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeStorage(db, logger)
+
+	path, err := backup.NewManager(db, dir, cfg.Backup.Retention, clock.System(), logger).Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка резервного копирования: %v\n", err)
+		return 1
+	}
+
+	fmt.Println(path)
+	return 0
+}
+
+// runRestore заменяет базу данных резервной копией после проверки ее схемы.
+// Бот должен быть остановлен на время восстановления.
+func runRestore(configPath string, args []string) int {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Использование: bot restore <файл резервной копии>")
+		return 2
+	}
+
+	cfg, logger, err := loadStorageConfig(configPath)
+	if err == nil {
+		err = requireSQLite(cfg)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	dbPath := cfg.Storage.SQLiteFile()
+	version, saved, err := repository.Restore(cfg.Storage.Driver, args[0], dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка восстановления: %v\n", err)
+		return 1
+	}
+
+	logger.Info("База данных восстановлена", "backup", args[0], "path", dbPath, "saved", saved, "schema_version", version)
+	return 0
+}
+
+// runExport выгружает историю выборов чата или всех чатов в CSV или JSON.
+// Результат пишется в файл из -o или в stdout.
+func runExport(configPath string, args []string) int {
+	flags := flag.NewFlagSet("export", flag.ContinueOnError)
+	chatID := flags.Int64("chat", 0, "ID чата, по умолчанию все чаты")
+	formatName := flags.String("format", string(history.FormatCSV), "формат: csv или json")
+	output := flags.String("o", "", "файл для записи, по умолчанию stdout")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Использование: bot export [-chat ID] [-format csv|json] [-o файл]")
+		return 2
+	}
+
+	format, ok := history.ParseFormat(*formatName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Неизвестный формат %q, доступны: csv, json\n", *formatName)
+		return 2
+	}
+
+	cfg, logger, err := loadStorageConfig(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	db, err := openStorage(cfg, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeStorage(db, logger)
+
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	var records []domain.PersonOfTheDayRecord
+	if *chatID != 0 {
+		records, err = personRepo.GetHistory(*chatID)
+	} else {
+		records, err = personRepo.GetAllHistory()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка получения истории: %v\n", err)
+		return 1
+	}
+
+	if *output == "" {
+		if err := history.Write(os.Stdout, format, records); err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка выгрузки: %v\n", err)
+			return 1
+		}
+		return 0
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка создания файла: %v\n", err)
+		return 1
+	}
+	if err := history.Write(file, format, records); err != nil {
+		_ = file.Close()
+		fmt.Fprintf(os.Stderr, "Ошибка выгрузки: %v\n", err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка записи файла: %v\n", err)
+		return 1
+	}
+
+	logger.Info("История выгружена", "path", *output, "records", len(records))
+	return 0
+}
+
+// runImport импортирует историю выборов чата из файла другого бота и печатает отчет
+func runImport(configPath string, args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	chatID := flags.Int64("chat", 0, "ID чата, в который импортируется история")
+	formatName := flags.String("format", "", "формат: csv или json, по умолчанию по расширению файла")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *chatID == 0 {
+		fmt.Fprintln(os.Stderr, "Использование: bot import -chat ID [-format csv|json] <файл>")
+		return 2
+	}
+	path := flags.Arg(0)
+
+	format, ok := history.FormatFromFileName(path)
+	if *formatName != "" {
+		format, ok = history.ParseFormat(*formatName)
+	}
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Не удалось определить формат файла, укажите -format csv или -format json")
+		return 2
+	}
+
+	cfg, logger, err := loadStorageConfig(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка открытия файла: %v\n", err)
+		return 1
+	}
+	defer func() { _ = file.Close() }()
+
+	rows, err := history.Parse(file, format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка чтения файла: %v\n", err)
+		return 1
+	}
+
+	db, err := openStorage(cfg, logger)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer closeStorage(db, logger)
+
+	importer := history.NewImporter(
+		repository.NewUserRepository(db),
+		repository.NewPersonOfTheDayRepository(db),
+		repository.NewNominationRepository(db),
+	)
+	result, err := importer.Import(*chatID, rows)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка импорта: %v\n", err)
+		return 1
+	}
+
+	messageService, err := templates.NewMessageService()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка создания сервиса сообщений: %v\n", err)
+		return 1
+	}
+	fmt.Println(messageService.WithDefaultLocale(cfg.Locale()).BuildImportReport(*result))
+
+	if len(result.Unmatched) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runHealthcheck проверяет готовность запущенного бота через /readyz (с -live — живость
+// через /healthz). Код возврата 0, если бот отвечает 200; используется в HEALTHCHECK Dockerfile.
+func runHealthcheck(configPath string, args []string) int {
+	flags := flag.NewFlagSet("healthcheck", flag.ContinueOnError)
+	live := flags.Bool("live", false, "проверять только, что процесс жив (/healthz)")
+	timeout := flags.Duration("timeout", 3*time.Second, "таймаут запроса")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "Использование: bot healthcheck [-live] [-timeout 3s]")
+		return 2
+	}
+
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфигурации: %v\n", err)
+		return 1
+	}
+	if !cfg.HTTP.Enabled {
+		fmt.Fprintln(os.Stderr, "HTTP сервер выключен (http.enabled), проверка состояния недоступна")
+		return 1
+	}
+
+	path := health.ReadyPath
+	if *live {
+		path = health.LivePath
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	if err := health.Probe(ctx, cfg.HTTP.HealthURL(path)); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,450 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// BasePath префикс всех путей API
+const BasePath = "/api/v1"
+
+// dateLayout формат дат в параметрах и ответах API
+const dateLayout = "2006-01-02"
+
+// Ограничения пагинации
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+)
+
+// chatIDKey ключ контекста запроса с ID чата, прошедшего проверку токена
+type chatIDKey struct{}
+
+// errBadRequest ошибка параметров запроса, сообщение возвращается клиенту
+type errBadRequest struct {
+	message string
+}
+
+func (e errBadRequest) Error() string {
+	return e.message
+}
+
+// badRequest создает ошибку параметров запроса
+func badRequest(format string, args ...any) error {
+	return errBadRequest{message: fmt.Sprintf(format, args...)}
+}
+
+// errNotFound ошибка отсутствующего ресурса, сообщение возвращается клиенту
+type errNotFound struct {
+	message string
+}
+
+func (e errNotFound) Error() string {
+	return e.message
+}
+
+// User участник чата в ответах API
+type User struct {
+	ID          int64  `json:"id"`
+	Username    string `json:"username"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	DisplayName string `json:"display_name"`
+}
+
+// StatsEntry строка таблицы лидеров
+type StatsEntry struct {
+	Rank int  `json:"rank"`
+	User User `json:"user"`
+	Wins int  `json:"wins"`
+}
+
+// HistoryEntry выбор в номинации на дату
+type HistoryEntry struct {
+	Date       string `json:"date"`
+	Nomination string `json:"nomination"`
+	User       User   `json:"user"`
+}
+
+// Page страница списка с параметрами пагинации
+type Page[T any] struct {
+	ChatID int64  `json:"chat_id"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Total  int    `json:"total"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+	Items  []T    `json:"items"`
+}
+
+// Today выбор сегодняшнего дня; User nil, если никто еще не выбран
+type Today struct {
+	ChatID     int64  `json:"chat_id"`
+	Date       string `json:"date"`
+	Nomination string `json:"nomination"`
+	User       *User  `json:"user"`
+}
+
+// Handler отдает статистику чатов по HTTP только для чтения.
+// Каждый запрос проверяется токеном чата из заголовка Authorization: Bearer.
+type Handler struct {
+	persons     repository.PersonOfTheDayRepository
+	nominations repository.NominationRepository
+	tokens      repository.APITokenRepository
+	clock       clock.Clock
+	logger      *slog.Logger
+	mux         *http.ServeMux
+}
+
+// NewHandler создает обработчик HTTP API
+func NewHandler(
+	persons repository.PersonOfTheDayRepository,
+	nominations repository.NominationRepository,
+	tokens repository.APITokenRepository,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	h := &Handler{
+		persons:     persons,
+		nominations: nominations,
+		tokens:      tokens,
+		clock:       clock.OrSystem(clk),
+		logger:      logger.With("component", "api"),
+		mux:         http.NewServeMux(),
+	}
+
+	h.mux.Handle("GET "+BasePath+"/chats/{chat}/stats", h.authorized(h.handleStats))
+	h.mux.Handle("GET "+BasePath+"/chats/{chat}/history", h.authorized(h.handleHistory))
+	h.mux.Handle("GET "+BasePath+"/chats/{chat}/today", h.authorized(h.handleToday))
+
+	return h
+}
+
+// ServeHTTP направляет запрос обработчику пути
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(rw, r)
+}
+
+// authorized проверяет токен чата и вызывает next с ID чата из пути
+func (h *Handler) authorized(next func(rw http.ResponseWriter, r *http.Request, chatID int64) error) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		chatID, err := strconv.ParseInt(r.PathValue("chat"), 10, 64)
+		if err != nil {
+			writeError(rw, http.StatusNotFound, "chat not found")
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			rw.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+			writeError(rw, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		stored, err := h.tokens.GetByHash(HashToken(strings.TrimSpace(token)))
+		if err != nil {
+			h.logger.Error("Ошибка проверки токена API", "error", err)
+			writeError(rw, http.StatusInternalServerError, "internal error")
+			return
+		}
+		if stored == nil {
+			rw.Header().Set("WWW-Authenticate", `Bearer realm="api", error="invalid_token"`)
+			writeError(rw, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		if stored.ChatID != chatID {
+			writeError(rw, http.StatusForbidden, "token is not valid for this chat")
+			return
+		}
+
+		if err := next(rw, r, chatID); err != nil {
+			var bad errBadRequest
+			var notFound errNotFound
+			switch {
+			case errors.As(err, &bad):
+				writeError(rw, http.StatusBadRequest, bad.message)
+			case errors.As(err, &notFound):
+				writeError(rw, http.StatusNotFound, notFound.message)
+			default:
+				h.logger.Error("Ошибка запроса API", "path", r.URL.Path, "chat_id", chatID, "error", err)
+				writeError(rw, http.StatusInternalServerError, "internal error")
+			}
+		}
+	})
+}
+
+// handleStats отдает таблицу лидеров номинации, за период from–to, если он задан
+func (h *Handler) handleStats(rw http.ResponseWriter, r *http.Request, chatID int64) error {
+	query := r.URL.Query()
+	nomination, err := h.nomination(chatID, query.Get("nomination"))
+	if err != nil {
+		return err
+	}
+	period, err := parsePeriod(query)
+	if err != nil {
+		return err
+	}
+	page, err := parsePage(query)
+	if err != nil {
+		return err
+	}
+
+	stats, err := h.persons.GetUserStats(chatID, nomination.ID)
+	if err != nil {
+		return err
+	}
+
+	// За период победы считаются по истории, участники берутся из статистики,
+	// чтобы в таблице остались и участники без побед
+	if !period.empty() {
+		history, err := h.persons.GetHistory(chatID)
+		if err != nil {
+			return err
+		}
+		wins := make(map[int64]int)
+		for _, record := range history {
+			if record.NominationID == nomination.ID && period.contains(record.Date) {
+				wins[record.UserID]++
+			}
+		}
+		for i := range stats {
+			stats[i].Count = wins[stats[i].User.ID]
+		}
+		sort.SliceStable(stats, func(i, j int) bool {
+			return stats[i].Count > stats[j].Count
+		})
+	}
+
+	entries := make([]StatsEntry, 0, len(stats))
+	for i, stat := range stats {
+		entries = append(entries, StatsEntry{Rank: i + 1, User: newUser(stat.User), Wins: stat.Count})
+	}
+
+	writeJSON(rw, http.StatusOK, paginate(chatID, period, page, entries))
+	return nil
+}
+
+// handleHistory отдает выборы от новых к старым; без параметра nomination — во всех номинациях
+func (h *Handler) handleHistory(rw http.ResponseWriter, r *http.Request, chatID int64) error {
+	query := r.URL.Query()
+	var nominationID *int64
+	if query.Has("nomination") {
+		nomination, err := h.nomination(chatID, query.Get("nomination"))
+		if err != nil {
+			return err
+		}
+		nominationID = &nomination.ID
+	}
+	period, err := parsePeriod(query)
+	if err != nil {
+		return err
+	}
+	page, err := parsePage(query)
+	if err != nil {
+		return err
+	}
+
+	history, err := h.persons.GetHistory(chatID)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]HistoryEntry, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		record := history[i]
+		if nominationID != nil && record.NominationID != *nominationID {
+			continue
+		}
+		if !period.contains(record.Date) {
+			continue
+		}
+		entries = append(entries, HistoryEntry{
+			Date:       record.Date.Format(dateLayout),
+			Nomination: nominationCommand(record.Nomination),
+			User:       newUser(record.User),
+		})
+	}
+
+	writeJSON(rw, http.StatusOK, paginate(chatID, period, page, entries))
+	return nil
+}
+
+// handleToday отдает сегодняшний выбор в номинации
+func (h *Handler) handleToday(rw http.ResponseWriter, r *http.Request, chatID int64) error {
+	nomination, err := h.nomination(chatID, r.URL.Query().Get("nomination"))
+	if err != nil {
+		return err
+	}
+
+	today := h.clock.Now()
+	person, err := h.persons.GetByDate(chatID, nomination.ID, today)
+	if err != nil {
+		return err
+	}
+
+	response := Today{
+		ChatID:     chatID,
+		Date:       today.Format(dateLayout),
+		Nomination: nominationCommand(nomination.Command),
+	}
+	if person != nil {
+		user := newUser(*person)
+		response.User = &user
+	}
+
+	writeJSON(rw, http.StatusOK, response)
+	return nil
+}
+
+// nomination находит номинацию чата по команде; пустая команда и pidor означают основную
+func (h *Handler) nomination(chatID int64, command string) (*domain.Nomination, error) {
+	command = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(command), "/"))
+	if command == "" || command == domain.DefaultNominationCommand {
+		return &domain.Nomination{ID: domain.DefaultNominationID, ChatID: chatID}, nil
+	}
+
+	nomination, err := h.nominations.GetByCommand(chatID, command)
+	if err != nil {
+		return nil, err
+	}
+	if nomination == nil {
+		return nil, errNotFound{message: fmt.Sprintf("nomination %q not found", command)}
+	}
+	return nomination, nil
+}
+
+// nominationCommand возвращает команду номинации для ответа
+func nominationCommand(command string) string {
+	if command == "" {
+		return domain.DefaultNominationCommand
+	}
+	return command
+}
+
+// newUser преобразует участника для ответа
+func newUser(user domain.User) User {
+	return User{
+		ID:          user.ID,
+		Username:    user.Username,
+		FirstName:   user.FirstName,
+		LastName:    user.LastName,
+		DisplayName: user.DisplayName(),
+	}
+}
+
+// period диапазон дат включительно; нулевая граница означает отсутствие ограничения
+type period struct {
+	from, to time.Time
+}
+
+// parsePeriod разбирает параметры from и to в формате 2006-01-02
+func parsePeriod(query map[string][]string) (period, error) {
+	var p period
+	for name, target := range map[string]*time.Time{"from": &p.from, "to": &p.to} {
+		values := query[name]
+		if len(values) == 0 || values[0] == "" {
+			continue
+		}
+		parsed, err := time.Parse(dateLayout, values[0])
+		if err != nil {
+			return p, badRequest("%s must be a date in YYYY-MM-DD format", name)
+		}
+		*target = parsed
+	}
+	if !p.from.IsZero() && !p.to.IsZero() && p.to.Before(p.from) {
+		return p, badRequest("to must not be before from")
+	}
+	return p, nil
+}
+
+// empty проверяет, что период не ограничен
+func (p period) empty() bool {
+	return p.from.IsZero() && p.to.IsZero()
+}
+
+// contains проверяет, что дата выбора входит в период. Сравниваются только
+// календарные даты, поэтому часовой пояс хранения не влияет на результат.
+func (p period) contains(date time.Time) bool {
+	day := date.Format(dateLayout)
+	if !p.from.IsZero() && day < p.from.Format(dateLayout) {
+		return false
+	}
+	if !p.to.IsZero() && day > p.to.Format(dateLayout) {
+		return false
+	}
+	return true
+}
+
+// page параметры пагинации
+type page struct {
+	limit, offset int
+}
+
+// parsePage разбирает параметры limit и offset
+func parsePage(query map[string][]string) (page, error) {
+	p := page{limit: DefaultLimit}
+	if values := query["limit"]; len(values) > 0 && values[0] != "" {
+		limit, err := strconv.Atoi(values[0])
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return p, badRequest("limit must be between 1 and %d", MaxLimit)
+		}
+		p.limit = limit
+	}
+	if values := query["offset"]; len(values) > 0 && values[0] != "" {
+		offset, err := strconv.Atoi(values[0])
+		if err != nil || offset < 0 {
+			return p, badRequest("offset must be a non-negative number")
+		}
+		p.offset = offset
+	}
+	return p, nil
+}
+
+// paginate возвращает страницу списка
+func paginate[T any](chatID int64, period period, p page, items []T) Page[T] {
+	result := Page[T]{
+		ChatID: chatID,
+		Total:  len(items),
+		Limit:  p.limit,
+		Offset: p.offset,
+		Items:  []T{},
+	}
+	if !period.from.IsZero() {
+		result.From = period.from.Format(dateLayout)
+	}
+	if !period.to.IsZero() {
+		result.To = period.to.Format(dateLayout)
+	}
+	if p.offset < len(items) {
+		result.Items = items[p.offset:min(p.offset+p.limit, len(items))]
+	}
+	return result
+}
+
+// writeJSON записывает ответ в JSON
+func writeJSON(rw http.ResponseWriter, code int, value any) {
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.WriteHeader(code)
+	_ = json.NewEncoder(rw).Encode(value)
+}
+
+// writeError записывает ошибку в JSON вида {"error": "..."}
+func writeError(rw http.ResponseWriter, code int, message string) {
+	writeJSON(rw, code, map[string]string{"error": message})
+}
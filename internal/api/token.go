@@ -0,0 +1,38 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenPrefix помогает узнать токен бота, например при поиске утечек в логах и репозиториях
+const tokenPrefix = "dotb_"
+
+// tokenBytes длина случайной части токена
+const tokenBytes = 32
+
+// GenerateToken создает случайный токен API и его хеш для хранения в базе
+func GenerateToken() (token, hash string, err error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate api token: %w", err)
+	}
+
+	token = tokenPrefix + base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken возвращает SHA-256 хеш токена в hex. Токены случайные и длинные,
+// поэтому медленный хеш для паролей не нужен.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ChatPath возвращает путь API чата, например /api/v1/chats/-100123
+func ChatPath(chatID int64) string {
+	return fmt.Sprintf("%s/chats/%d", BasePath, chatID)
+}
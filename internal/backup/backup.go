@@ -0,0 +1,143 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+)
+
+// filePrefix и fileExt образуют имена резервных копий: bot-20060102-150405.db
+const (
+	filePrefix = "bot-"
+	fileExt    = ".db"
+	timeLayout = "20060102-150405"
+)
+
+// Source создает согласованную копию базы данных в указанный файл
+type Source interface {
+	Backup(path string) error
+}
+
+// Manager создает резервные копии в каталоге и удаляет старые сверх лимита
+type Manager struct {
+	source    Source
+	dir       string
+	retention int
+	clock     clock.Clock
+	logger    *slog.Logger
+}
+
+// NewManager создает менеджер резервных копий.
+// retention — сколько последних копий хранить, 0 — хранить все.
+// Имя копии содержит время clk; nil — системные часы.
+func NewManager(source Source, dir string, retention int, clk clock.Clock, logger *slog.Logger) *Manager {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Manager{
+		source:    source,
+		dir:       dir,
+		retention: retention,
+		clock:     clock.OrSystem(clk),
+		logger:    logger.With("component", "backup"),
+	}
+}
+
+// Run создает резервную копию, удаляет устаревшие и возвращает путь к новой копии
+func (m *Manager) Run() (string, error) {
+	if err := os.MkdirAll(m.dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create backup dir %s: %w", m.dir, err)
+	}
+
+	path := filepath.Join(m.dir, filePrefix+m.clock.Now().Format(timeLayout)+fileExt)
+	if err := m.source.Backup(path); err != nil {
+		return "", err
+	}
+
+	if err := m.Prune(); err != nil {
+		return path, err
+	}
+
+	return path, nil
+}
+
+// Prune удаляет копии сверх лимита, начиная с самых старых.
+// Файлы с другими именами в каталоге не затрагиваются.
+func (m *Manager) Prune() error {
+	if m.retention <= 0 {
+		return nil
+	}
+
+	backups, err := m.List()
+	if err != nil {
+		return err
+	}
+	if len(backups) <= m.retention {
+		return nil
+	}
+
+	for _, path := range backups[:len(backups)-m.retention] {
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("failed to remove old backup %s: %w", path, err)
+		}
+		m.logger.Info("Удалена старая резервная копия", "path", path)
+	}
+
+	return nil
+}
+
+// List возвращает пути резервных копий в каталоге от старых к новым
+func (m *Manager) List() ([]string, error) {
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read backup dir %s: %w", m.dir, err)
+	}
+
+	var backups []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileExt) {
+			continue
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, filePrefix), fileExt)
+		if _, err := time.Parse(timeLayout, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(m.dir, name))
+	}
+
+	// Время в имени позволяет сортировать копии как строки
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// Start создает копии с заданным интервалом до отмены ctx.
+// Ошибки только логируются, чтобы сбой копирования не останавливал бота.
+func (m *Manager) Start(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.logger.Info("Периодическое резервное копирование запущено", "dir", m.dir, "interval", interval, "retention", m.retention)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.Run(); err != nil {
+				m.logger.Error("Ошибка резервного копирования", "error", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"log/slog"
+	"math/rand"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/core"
+	"github.com/pavel-one/day-of-the-bot/internal/handlers"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// rng общий генератор случайных чисел бота
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// GetRNG возвращает общий генератор случайных чисел. Генератор не потокобезопасен:
+// он передается в core.Service, который обращается к нему под мьютексом.
+func GetRNG() *rand.Rand {
+	return rng
+}
+
+// Metrics учитывает полученные обновления и события их обработки
+type Metrics interface {
+	handlers.Metrics
+	ObserveUpdate(update *telebot.Update) bool
+}
+
+// Bot связывает Telegram API с обработчиками команд и сообщений
+type Bot struct {
+	api            *telebot.Bot
+	webhook        *Webhook
+	commandHandler *handlers.CommandHandler
+	messageHandler *handlers.MessageHandler
+	inlineHandler  *handlers.InlineHandler
+	metrics        Metrics
+	logger         *slog.Logger
+}
+
+// NewBot создает нового бота со всеми зависимостями.
+// clk задает текущий день для выбора и статистики; nil — системные часы.
+func NewBot(
+	api *telebot.Bot,
+	userRepo repository.UserRepository,
+	personOfTheDayRepo repository.PersonOfTheDayRepository,
+	chatSettingsRepo repository.ChatSettingsRepository,
+	nominationRepo repository.NominationRepository,
+	aliasRepo repository.CommandAliasRepository,
+	apiTokenRepo repository.APITokenRepository,
+	webLinks handlers.WebLinks,
+	messageService *templates.MessageService,
+	clk clock.Clock,
+	metrics Metrics,
+	logger *slog.Logger,
+) *Bot {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	coreService := core.NewService(userRepo, personOfTheDayRepo, chatSettingsRepo, GetRNG(), clk)
+
+	commandHandler := handlers.NewCommandHandler(
+		api,
+		userRepo,
+		personOfTheDayRepo,
+		chatSettingsRepo,
+		nominationRepo,
+		aliasRepo,
+		apiTokenRepo,
+		webLinks,
+		messageService,
+		coreService,
+		clk,
+		logger,
+	)
+
+	messageHandler := handlers.NewMessageHandler(
+		api,
+		chatSettingsRepo,
+		messageService,
+		coreService,
+		commandHandler,
+		logger,
+	)
+
+	inlineHandler := handlers.NewInlineHandler(
+		userRepo,
+		personOfTheDayRepo,
+		chatSettingsRepo,
+		messageService,
+		clk,
+		logger,
+	)
+
+	// Вебхук запоминается до того, как Start обернет источник обновлений для метрик
+	webhook, _ := api.Poller.(*Webhook)
+
+	return &Bot{
+		api:            api,
+		webhook:        webhook,
+		commandHandler: commandHandler,
+		messageHandler: messageHandler,
+		inlineHandler:  inlineHandler,
+		metrics:        metrics,
+		logger:         logger.With("component", "bot"),
+	}
+}
+
+// Start регистрирует обработчики и запускает получение обновлений.
+// В режиме вебхука вебхук регистрируется в Telegram, в режиме long polling
+// ранее установленный вебхук удаляется, иначе Telegram отклоняет getUpdates.
+// Блокируется до вызова Stop.
+func (b *Bot) Start() error {
+	// Метрики учитывают все обновления, в том числе те, для которых нет обработчика
+	if b.metrics != nil {
+		b.api.Poller = &telebot.MiddlewarePoller{Poller: b.api.Poller, Filter: b.metrics.ObserveUpdate}
+		b.api.Use(handlers.MetricsMiddleware(b.metrics))
+	}
+
+	b.messageHandler.RegisterHandlers(b.api)
+	b.inlineHandler.RegisterHandlers(b.api)
+
+	if err := b.commandHandler.PublishCommands(b.api); err != nil {
+		b.logger.Error("Ошибка публикации меню команд", "error", err)
+	}
+
+	if b.webhook != nil {
+		if err := b.webhook.Register(b.api); err != nil {
+			return err
+		}
+	} else if err := b.api.RemoveWebhook(); err != nil {
+		b.logger.Warn("Ошибка удаления вебхука перед long polling", "error", err)
+	}
+
+	b.logger.Info("Бот запущен", "username", b.api.Me.Username)
+	b.api.Start()
+	return nil
+}
+
+// Stop останавливает получение обновлений и удаляет вебхук, если он использовался
+func (b *Bot) Stop() {
+	b.api.Stop()
+
+	if b.webhook != nil {
+		if err := b.webhook.Unregister(b.api); err != nil {
+			b.logger.Error("Ошибка удаления вебхука", "error", err)
+		}
+	}
+
+	b.logger.Info("Бот остановлен")
+}
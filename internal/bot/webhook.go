@@ -0,0 +1,214 @@
+package bot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/config"
+	"gopkg.in/telebot.v3"
+)
+
+// secretTokenHeader заголовок, в котором Telegram передает секрет вебхука
+const secretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// shutdownTimeout время на завершение обработки запросов при остановке
+const shutdownTimeout = 5 * time.Second
+
+// Webhook получает обновления через HTTP вебхук и реализует telebot.Poller.
+// Регистрация вебхука в Telegram выполняется в Bot.Start, удаление — в Bot.Stop.
+type Webhook struct {
+	cfg    config.WebhookConfig
+	path   string
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	listener net.Listener
+	dest     chan<- telebot.Update
+	stopped  chan struct{}
+}
+
+// NewWebhook создает вебхук по настройкам; путь запросов берется из публичного URL
+func NewWebhook(cfg config.WebhookConfig, logger *slog.Logger) (*Webhook, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	publicURL, err := url.Parse(cfg.PublicURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook public url: %w", err)
+	}
+
+	path := publicURL.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return &Webhook{
+		cfg:     cfg,
+		path:    path,
+		logger:  logger.With("component", "webhook"),
+		stopped: make(chan struct{}),
+	}, nil
+}
+
+// Listen открывает порт для входящих запросов. Вызывается до регистрации вебхука,
+// чтобы ошибка занятого порта обнаруживалась при запуске.
+func (w *Webhook) Listen() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.listener != nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", w.cfg.Listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", w.cfg.Listen, err)
+	}
+	w.listener = listener
+
+	return nil
+}
+
+// Addr возвращает фактический адрес, на котором принимаются запросы
+func (w *Webhook) Addr() net.Addr {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.listener == nil {
+		return nil
+	}
+	return w.listener.Addr()
+}
+
+// Register открывает порт и сообщает Telegram адрес вебхука и секрет
+func (w *Webhook) Register(api *telebot.Bot) error {
+	if err := w.Listen(); err != nil {
+		return err
+	}
+
+	webhook := &telebot.Webhook{
+		SecretToken: w.cfg.SecretToken,
+		Endpoint: &telebot.WebhookEndpoint{
+			PublicURL: w.cfg.PublicURL,
+			// Сертификат загружается в Telegram, чтобы работали самоподписанные сертификаты
+			Cert: w.cfg.TLSCert,
+		},
+	}
+	if err := api.SetWebhook(webhook); err != nil {
+		return fmt.Errorf("failed to set webhook: %w", err)
+	}
+
+	w.logger.Info("Вебхук зарегистрирован", "url", w.cfg.PublicURL, "listen", w.Addr().String())
+	return nil
+}
+
+// Unregister удаляет вебхук в Telegram
+func (w *Webhook) Unregister(api *telebot.Bot) error {
+	if err := api.RemoveWebhook(); err != nil {
+		return fmt.Errorf("failed to remove webhook: %w", err)
+	}
+
+	w.logger.Info("Вебхук удален")
+	return nil
+}
+
+// Poll принимает обновления по HTTP до закрытия stop
+func (w *Webhook) Poll(b *telebot.Bot, dest chan telebot.Update, stop chan struct{}) {
+	if err := w.Listen(); err != nil {
+		w.logger.Error("Ошибка запуска вебхука", "error", err)
+		<-stop
+		return
+	}
+
+	w.mu.Lock()
+	w.dest = dest
+	listener := w.listener
+	w.mu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.Handle(w.path, w)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		if w.cfg.TLSCert != "" {
+			served <- server.ServeTLS(listener, w.cfg.TLSCert, w.cfg.TLSKey)
+		} else {
+			served <- server.Serve(listener)
+		}
+	}()
+
+	select {
+	case <-stop:
+	case err := <-served:
+		// Сервер завершился сам — ждем остановки бота, чтобы не нарушать контракт Poller
+		if !errors.Is(err, http.ErrServerClosed) {
+			w.logger.Error("Ошибка HTTP сервера вебхука", "error", err)
+		}
+		<-stop
+	}
+
+	close(w.stopped)
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		w.logger.Error("Ошибка остановки HTTP сервера вебхука", "error", err)
+	}
+}
+
+// ServeHTTP принимает обновление от Telegram, проверив секрет
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	secret := r.Header.Get(secretTokenHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(w.cfg.SecretToken)) != 1 {
+		w.logger.Warn("Запрос к вебхуку с неверным секретом", "remote_addr", r.RemoteAddr)
+		http.Error(rw, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var update telebot.Update
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		w.logger.Warn("Не удалось разобрать обновление", "error", err)
+		http.Error(rw, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	w.mu.Lock()
+	dest := w.dest
+	w.mu.Unlock()
+
+	select {
+	case dest <- update:
+		rw.WriteHeader(http.StatusOK)
+	case <-w.stopped:
+		http.Error(rw, "shutting down", http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+	}
+}
+
+// NewPoller создает источник обновлений по режиму из настроек: long polling или вебхук
+func NewPoller(cfg config.TelegramConfig, logger *slog.Logger) (telebot.Poller, error) {
+	if cfg.Mode == config.ModeWebhook {
+		return NewWebhook(cfg.Webhook, logger)
+	}
+	return &telebot.LongPoller{Timeout: cfg.PollerTimeout}, nil
+}
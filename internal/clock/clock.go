@@ -0,0 +1,77 @@
+// Package clock отделяет текущее время от системных часов. Все даты выбора
+// считаются по дню в часовом поясе времени, которое возвращают часы, поэтому
+// тесты с Fake проверяют смену дня в полночь, смену часового пояса и переход
+// на летнее время без ожидания.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock возвращает текущее время
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock системные часы в часовом поясе time.Local
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// System возвращает системные часы. Часовой пояс — time.Local, который main
+// устанавливает из time_zone конфигурации.
+func System() Clock {
+	return systemClock{}
+}
+
+// OrSystem возвращает clk или системные часы, если clk не задан
+func OrSystem(clk Clock) Clock {
+	if clk == nil {
+		return System()
+	}
+	return clk
+}
+
+// Fake часы, которые идут только по команде. Безопасны для одновременного использования.
+type Fake struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFake создает часы, показывающие now. Часовой пояс now определяет день выбора.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now}
+}
+
+// Now возвращает время часов
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+// Set переставляет часы на now
+func (f *Fake) Set(now time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = now
+}
+
+// Advance переводит часы вперед на d. Время течет в абсолютных единицах:
+// в день перехода на летнее время сутки короче или длиннее 24 часов.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// SetLocation меняет часовой пояс часов, не меняя момент времени,
+// как смена time_zone в конфигурации
+func (f *Fake) SetLocation(loc *time.Location) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.In(loc)
+}
@@ -1,42 +1,646 @@
 package config
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/yaml.v3"
+)
+
+// redactedToken заменяет токен бота, секреты вебхука и веб-страницы и пароль базы данных при выводе конфигурации
+const redactedToken = "<redacted>"
+
+// dsnPasswordRx находит пароль в строке подключения вида "key=value"
+var dsnPasswordRx = regexp.MustCompile(`(password=)('[^']*'|\S+)`)
+
+// Режимы получения обновлений
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
 )
 
+// secretTokenRx допустимые символы секрета вебхука по документации Telegram
+var secretTokenRx = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// minWebSecretLength минимальная длина ключа подписи ссылок на веб-страницу
+const minWebSecretLength = 16
+
 // Config содержит конфигурацию приложения
 type Config struct {
-	BotToken string
-	DBPath   string
-	Debug    bool
+	BotToken string         `yaml:"bot_token"`
+	Debug    bool           `yaml:"debug"`
+	TimeZone string         `yaml:"time_zone"`
+	Language string         `yaml:"language"`
+	Telegram TelegramConfig `yaml:"telegram"`
+	Log      LogConfig      `yaml:"log"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Backup   BackupConfig   `yaml:"backup"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	API      APIConfig      `yaml:"api"`
+	Web      WebConfig      `yaml:"web"`
+}
+
+// TelegramConfig содержит настройки подключения к Telegram
+type TelegramConfig struct {
+	Mode          string        `yaml:"mode"`
+	APIURL        string        `yaml:"api_url"`
+	PollerTimeout time.Duration `yaml:"poller_timeout"`
+	Webhook       WebhookConfig `yaml:"webhook"`
+}
+
+// WebhookConfig содержит настройки получения обновлений через вебхук
+type WebhookConfig struct {
+	Listen      string `yaml:"listen"`
+	PublicURL   string `yaml:"public_url"`
+	SecretToken string `yaml:"secret_token"`
+	TLSCert     string `yaml:"tls_cert"`
+	TLSKey      string `yaml:"tls_key"`
 }
 
-// Load загружает конфигурацию из переменных окружения
-func Load() (*Config, error) {
-	botToken := os.Getenv("BOT_TOKEN")
-	if botToken == "" {
-		return nil, fmt.Errorf("BOT_TOKEN environment variable is required")
+// LogConfig содержит настройки логирования
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+}
+
+// StorageConfig содержит настройки хранилища: путь к файлу для SQLite
+// или строку подключения для PostgreSQL, параметры SQLite и пула соединений
+type StorageConfig struct {
+	Driver          string        `yaml:"driver"`
+	Path            string        `yaml:"path"`
+	DSN             string        `yaml:"dsn"`
+	JournalMode     string        `yaml:"journal_mode"`
+	BusyTimeout     time.Duration `yaml:"busy_timeout"`
+	ForeignKeys     bool          `yaml:"foreign_keys"`
+	IntegrityCheck  bool          `yaml:"integrity_check"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+// DataSource возвращает строку подключения для выбранного драйвера
+func (s StorageConfig) DataSource() string {
+	if repository.IsSQLite(s.Driver) {
+		return s.Path
 	}
+	return s.DSN
+}
+
+// BackupConfig содержит настройки резервного копирования SQLite
+type BackupConfig struct {
+	Enabled   bool          `yaml:"enabled"`
+	Dir       string        `yaml:"dir"`
+	Interval  time.Duration `yaml:"interval"`
+	Retention int           `yaml:"retention"`
+}
+
+// MetricsConfig содержит настройки HTTP сервера метрик Prometheus
+type MetricsConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"`
+	Path    string `yaml:"path"`
+}
+
+// HTTPConfig содержит настройки HTTP сервера бота с проверками состояния /healthz и /readyz
+type HTTPConfig struct {
+	Enabled bool   `yaml:"enabled"`
+	Listen  string `yaml:"listen"`
+	// UpdatesThreshold через сколько после последнего успешного getUpdates бот
+	// считается неготовым; в режиме вебхука не проверяется
+	UpdatesThreshold time.Duration `yaml:"updates_threshold"`
+}
 
-	dbPath := os.Getenv("DB_PATH")
-	if dbPath == "" {
-		dbPath = "bot.db"
+// APIConfig содержит настройки HTTP API статистики чатов.
+// API работает на HTTP сервере бота, доступ к чату дает токен из /pidorapi.
+type APIConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// WebConfig содержит настройки веб-страницы статистики чата.
+// Страница работает на HTTP сервере бота, ссылку с подписью и сроком действия
+// присылает /pidorstats web.
+type WebConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// PublicURL внешний адрес HTTP сервера бота, от которого строятся ссылки
+	PublicURL string `yaml:"public_url"`
+	// Secret ключ подписи ссылок; если не задан, создается при запуске
+	// и ссылки перестают действовать после перезапуска
+	Secret  string        `yaml:"secret"`
+	LinkTTL time.Duration `yaml:"link_ttl"`
+}
+
+// SQLiteFile возвращает путь к файлу SQLite без параметров подключения
+func (s StorageConfig) SQLiteFile() string {
+	path, _, _ := strings.Cut(s.Path, "?")
+	return strings.TrimPrefix(path, "file:")
+}
+
+// Options возвращает настройки подключения к хранилищу
+func (s StorageConfig) Options() repository.Options {
+	return repository.Options{
+		JournalMode:     s.JournalMode,
+		BusyTimeout:     s.BusyTimeout,
+		ForeignKeys:     s.ForeignKeys,
+		IntegrityCheck:  s.IntegrityCheck,
+		MaxOpenConns:    s.MaxOpenConns,
+		MaxIdleConns:    s.MaxIdleConns,
+		ConnMaxLifetime: s.ConnMaxLifetime,
 	}
+}
 
-	debug := false
-	if debugStr := os.Getenv("DEBUG"); debugStr != "" {
-		var err error
-		debug, err = strconv.ParseBool(debugStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid DEBUG value: %w", err)
-		}
+// storageDefaults возвращает настройки хранилища по умолчанию
+func storageDefaults() StorageConfig {
+	options := repository.DefaultOptions()
+	return StorageConfig{
+		Driver:          repository.DefaultSQLiteDriver,
+		Path:            "bot.db",
+		JournalMode:     options.JournalMode,
+		BusyTimeout:     options.BusyTimeout,
+		ForeignKeys:     options.ForeignKeys,
+		IntegrityCheck:  options.IntegrityCheck,
+		MaxOpenConns:    options.MaxOpenConns,
+		MaxIdleConns:    options.MaxIdleConns,
+		ConnMaxLifetime: options.ConnMaxLifetime,
 	}
+}
 
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() *Config {
 	return &Config{
-		BotToken: botToken,
-		DBPath:   dbPath,
-		Debug:    debug,
-	}, nil
+		TimeZone: "Local",
+		Language: string(templates.DefaultLocale),
+		Telegram: TelegramConfig{
+			Mode:          ModePolling,
+			PollerTimeout: 10 * time.Second,
+			Webhook: WebhookConfig{
+				Listen: ":8443",
+			},
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
+		Storage: storageDefaults(),
+		Backup: BackupConfig{
+			Enabled:   false,
+			Dir:       "backups",
+			Interval:  24 * time.Hour,
+			Retention: 7,
+		},
+		Metrics: MetricsConfig{
+			Enabled: false,
+			Listen:  ":9090",
+			Path:    "/metrics",
+		},
+		HTTP: HTTPConfig{
+			Enabled:          true,
+			Listen:           ":8080",
+			UpdatesThreshold: 2 * time.Minute,
+		},
+		Web: WebConfig{
+			LinkTTL: 24 * time.Hour,
+		},
+	}
+}
+
+// Load загружает и проверяет конфигурацию
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Read загружает конфигурацию слоями без проверки: значения по умолчанию,
+// затем YAML файл (если path не пуст), затем переменные окружения
+func Read(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile читает YAML файл; неизвестные поля считаются ошибкой
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// loadEnv переопределяет значения из переменных окружения.
+// Ошибки разбора всех переменных собираются вместе.
+func (c *Config) loadEnv() error {
+	var errs []error
+
+	setString := func(name string, target *string) {
+		if value := os.Getenv(name); value != "" {
+			*target = value
+		}
+	}
+	setBool := func(name string, target *bool) {
+		if value := os.Getenv(name); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s value: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+	setInt := func(name string, target *int) {
+		if value := os.Getenv(name); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s value: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+	setDuration := func(name string, target *time.Duration) {
+		if value := os.Getenv(name); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("invalid %s value: %w", name, err))
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	setString("BOT_TOKEN", &c.BotToken)
+	setBool("DEBUG", &c.Debug)
+	setString("TIME_ZONE", &c.TimeZone)
+	setString("LANGUAGE", &c.Language)
+	setString("TELEGRAM_MODE", &c.Telegram.Mode)
+	setString("TELEGRAM_API_URL", &c.Telegram.APIURL)
+	setDuration("POLLER_TIMEOUT", &c.Telegram.PollerTimeout)
+	setString("WEBHOOK_LISTEN", &c.Telegram.Webhook.Listen)
+	setString("WEBHOOK_URL", &c.Telegram.Webhook.PublicURL)
+	setString("WEBHOOK_SECRET", &c.Telegram.Webhook.SecretToken)
+	setString("WEBHOOK_TLS_CERT", &c.Telegram.Webhook.TLSCert)
+	setString("WEBHOOK_TLS_KEY", &c.Telegram.Webhook.TLSKey)
+	setString("LOG_LEVEL", &c.Log.Level)
+	setString("LOG_FORMAT", &c.Log.Format)
+	setString("DB_DRIVER", &c.Storage.Driver)
+	setString("DB_PATH", &c.Storage.Path)
+	setString("DB_DSN", &c.Storage.DSN)
+	setString("DB_JOURNAL_MODE", &c.Storage.JournalMode)
+	setDuration("DB_BUSY_TIMEOUT", &c.Storage.BusyTimeout)
+	setBool("DB_FOREIGN_KEYS", &c.Storage.ForeignKeys)
+	setBool("DB_INTEGRITY_CHECK", &c.Storage.IntegrityCheck)
+	setInt("DB_MAX_OPEN_CONNS", &c.Storage.MaxOpenConns)
+	setInt("DB_MAX_IDLE_CONNS", &c.Storage.MaxIdleConns)
+	setDuration("DB_CONN_MAX_LIFETIME", &c.Storage.ConnMaxLifetime)
+	setBool("BACKUP_ENABLED", &c.Backup.Enabled)
+	setString("BACKUP_DIR", &c.Backup.Dir)
+	setDuration("BACKUP_INTERVAL", &c.Backup.Interval)
+	setInt("BACKUP_RETENTION", &c.Backup.Retention)
+	setBool("METRICS_ENABLED", &c.Metrics.Enabled)
+	setString("METRICS_LISTEN", &c.Metrics.Listen)
+	setString("METRICS_PATH", &c.Metrics.Path)
+	setBool("HTTP_ENABLED", &c.HTTP.Enabled)
+	setString("HTTP_LISTEN", &c.HTTP.Listen)
+	setDuration("HTTP_UPDATES_THRESHOLD", &c.HTTP.UpdatesThreshold)
+	setBool("API_ENABLED", &c.API.Enabled)
+	setBool("WEB_ENABLED", &c.Web.Enabled)
+	setString("WEB_PUBLIC_URL", &c.Web.PublicURL)
+	setString("WEB_SECRET", &c.Web.Secret)
+	setDuration("WEB_LINK_TTL", &c.Web.LinkTTL)
+
+	return errors.Join(errs...)
+}
+
+// Validate проверяет конфигурацию и возвращает все найденные ошибки сразу
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.BotToken == "" {
+		errs = append(errs, fmt.Errorf("bot_token (BOT_TOKEN) is required"))
+	}
+
+	if _, err := time.LoadLocation(c.TimeZone); err != nil {
+		errs = append(errs, fmt.Errorf("invalid time_zone %q: %w", c.TimeZone, err))
+	}
+
+	if _, ok := templates.ParseLocale(c.Language); !ok {
+		errs = append(errs, fmt.Errorf("unsupported language %q", c.Language))
+	}
+
+	switch c.Telegram.Mode {
+	case ModePolling:
+		if c.Telegram.PollerTimeout <= 0 {
+			errs = append(errs, fmt.Errorf("telegram.poller_timeout must be positive, got %s", c.Telegram.PollerTimeout))
+		}
+	case ModeWebhook:
+		errs = append(errs, c.Telegram.Webhook.validate()...)
+	default:
+		errs = append(errs, fmt.Errorf("telegram.mode must be one of %s, %s, got %q", ModePolling, ModeWebhook, c.Telegram.Mode))
+	}
+
+	if c.Telegram.APIURL != "" {
+		if parsed, err := url.Parse(c.Telegram.APIURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("telegram.api_url must be an absolute URL, got %q", c.Telegram.APIURL))
+		}
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level must be one of debug, info, warn, error, got %q", c.Log.Level))
+	}
+
+	switch strings.ToLower(c.Log.Format) {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log.format must be one of text, json, got %q", c.Log.Format))
+	}
+
+	if err := c.Storage.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	errs = append(errs, c.Backup.validate(c.Storage.Driver)...)
+	errs = append(errs, c.Metrics.validate(c.Telegram)...)
+	errs = append(errs, c.HTTP.validate(c.Telegram, c.Metrics)...)
+	if c.API.Enabled && !c.HTTP.Enabled {
+		errs = append(errs, fmt.Errorf("api.enabled requires http.enabled"))
+	}
+	errs = append(errs, c.Web.validate(c.HTTP)...)
+
+	return errors.Join(errs...)
+}
+
+// Validate проверяет настройки хранилища. Используется отдельно командами,
+// которым нужна только база данных, без токена бота.
+func (s StorageConfig) Validate() error {
+	var errs []error
+
+	switch {
+	case !slices.Contains(repository.Drivers(), s.Driver):
+		errs = append(errs, fmt.Errorf("storage.driver must be one of %s (drivers available in this build), got %q",
+			strings.Join(repository.Drivers(), ", "), s.Driver))
+	case repository.IsSQLite(s.Driver) && s.Path == "":
+		errs = append(errs, fmt.Errorf("storage.path is required for %s", s.Driver))
+	case s.Driver == repository.DriverPostgres && s.DSN == "":
+		errs = append(errs, fmt.Errorf("storage.dsn is required for %s", s.Driver))
+	}
+
+	if s.JournalMode != "" && !slices.Contains(repository.JournalModes(), strings.ToUpper(s.JournalMode)) {
+		errs = append(errs, fmt.Errorf("storage.journal_mode must be one of %s, got %q",
+			strings.Join(repository.JournalModes(), ", "), s.JournalMode))
+	}
+	if s.BusyTimeout < 0 {
+		errs = append(errs, fmt.Errorf("storage.busy_timeout must not be negative, got %s", s.BusyTimeout))
+	}
+	if s.MaxOpenConns < 0 {
+		errs = append(errs, fmt.Errorf("storage.max_open_conns must not be negative, got %d", s.MaxOpenConns))
+	}
+	if s.MaxIdleConns < 0 {
+		errs = append(errs, fmt.Errorf("storage.max_idle_conns must not be negative, got %d", s.MaxIdleConns))
+	}
+	if s.MaxOpenConns > 0 && s.MaxIdleConns > s.MaxOpenConns {
+		errs = append(errs, fmt.Errorf("storage.max_idle_conns (%d) must not exceed storage.max_open_conns (%d)",
+			s.MaxIdleConns, s.MaxOpenConns))
+	}
+	if s.ConnMaxLifetime < 0 {
+		errs = append(errs, fmt.Errorf("storage.conn_max_lifetime must not be negative, got %s", s.ConnMaxLifetime))
+	}
+
+	return errors.Join(errs...)
+}
+
+// validate проверяет настройки резервного копирования
+func (b BackupConfig) validate(driver string) []error {
+	var errs []error
+
+	if b.Retention < 0 {
+		errs = append(errs, fmt.Errorf("backup.retention must not be negative, got %d", b.Retention))
+	}
+	if !b.Enabled {
+		return errs
+	}
+
+	if !repository.IsSQLite(driver) {
+		errs = append(errs, fmt.Errorf("backup is supported only for SQLite storage, got %s", driver))
+	}
+	if b.Dir == "" {
+		errs = append(errs, fmt.Errorf("backup.dir is required when backup is enabled"))
+	}
+	if b.Interval < time.Minute {
+		errs = append(errs, fmt.Errorf("backup.interval must be at least 1m, got %s", b.Interval))
+	}
+
+	return errs
+}
+
+// validate проверяет настройки сервера метрик
+func (m MetricsConfig) validate(telegram TelegramConfig) []error {
+	if !m.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if m.Listen == "" {
+		errs = append(errs, fmt.Errorf("metrics.listen is required when metrics are enabled"))
+	} else if telegram.Mode == ModeWebhook && m.Listen == telegram.Webhook.Listen {
+		errs = append(errs, fmt.Errorf("metrics.listen must differ from telegram.webhook.listen, got %s", m.Listen))
+	}
+	if !strings.HasPrefix(m.Path, "/") {
+		errs = append(errs, fmt.Errorf("metrics.path must start with /, got %q", m.Path))
+	}
+
+	return errs
+}
+
+// validate проверяет настройки HTTP сервера
+func (h HTTPConfig) validate(telegram TelegramConfig, metrics MetricsConfig) []error {
+	if !h.Enabled {
+		return nil
+	}
+
+	var errs []error
+	switch {
+	case h.Listen == "":
+		errs = append(errs, fmt.Errorf("http.listen is required when http is enabled"))
+	case telegram.Mode == ModeWebhook && h.Listen == telegram.Webhook.Listen:
+		errs = append(errs, fmt.Errorf("http.listen must differ from telegram.webhook.listen, got %s", h.Listen))
+	case metrics.Enabled && h.Listen == metrics.Listen:
+		errs = append(errs, fmt.Errorf("http.listen must differ from metrics.listen, got %s", h.Listen))
+	}
+	// Долгий опрос длится до poller_timeout, порог должен быть больше
+	if telegram.Mode == ModePolling && h.UpdatesThreshold <= telegram.PollerTimeout {
+		errs = append(errs, fmt.Errorf("http.updates_threshold must exceed telegram.poller_timeout (%s), got %s",
+			telegram.PollerTimeout, h.UpdatesThreshold))
+	}
+
+	return errs
+}
+
+// validate проверяет настройки веб-страницы статистики
+func (w WebConfig) validate(http HTTPConfig) []error {
+	if !w.Enabled {
+		return nil
+	}
+
+	var errs []error
+	if !http.Enabled {
+		errs = append(errs, fmt.Errorf("web.enabled requires http.enabled"))
+	}
+	parsed, err := url.Parse(w.PublicURL)
+	if w.PublicURL == "" || err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		errs = append(errs, fmt.Errorf("web.public_url must be an absolute http(s) URL, got %q", w.PublicURL))
+	}
+	if w.Secret != "" && len(w.Secret) < minWebSecretLength {
+		errs = append(errs, fmt.Errorf("web.secret must be at least %d characters", minWebSecretLength))
+	}
+	if w.LinkTTL < time.Minute {
+		errs = append(errs, fmt.Errorf("web.link_ttl must be at least 1m, got %s", w.LinkTTL))
+	}
+
+	return errs
+}
+
+// HealthURL возвращает адрес проверки готовности для команды healthcheck.
+// Пустой хост в http.listen заменяется на 127.0.0.1.
+func (h HTTPConfig) HealthURL(path string) string {
+	host, port, err := net.SplitHostPort(h.Listen)
+	if err != nil {
+		return "http://" + h.Listen + path
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return "http://" + net.JoinHostPort(host, port) + path
+}
+
+// validate проверяет настройки вебхука
+func (w WebhookConfig) validate() []error {
+	var errs []error
+
+	if w.Listen == "" {
+		errs = append(errs, fmt.Errorf("telegram.webhook.listen is required in webhook mode"))
+	}
+
+	parsed, err := url.Parse(w.PublicURL)
+	if w.PublicURL == "" || err != nil || parsed.Host == "" {
+		errs = append(errs, fmt.Errorf("telegram.webhook.public_url must be an absolute URL, got %q", w.PublicURL))
+	} else if parsed.Scheme != "https" {
+		errs = append(errs, fmt.Errorf("telegram.webhook.public_url must use https, got %q", w.PublicURL))
+	}
+
+	if !secretTokenRx.MatchString(w.SecretToken) {
+		errs = append(errs, fmt.Errorf("telegram.webhook.secret_token must be 1-256 characters A-Z, a-z, 0-9, _ or -"))
+	}
+
+	if (w.TLSCert == "") != (w.TLSKey == "") {
+		errs = append(errs, fmt.Errorf("telegram.webhook.tls_cert and telegram.webhook.tls_key must be set together"))
+	}
+	for _, path := range []string{w.TLSCert, w.TLSKey} {
+		if path == "" {
+			continue
+		}
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("telegram.webhook TLS file: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// Location возвращает часовой пояс по умолчанию
+func (c *Config) Location() *time.Location {
+	location, err := time.LoadLocation(c.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
+
+// Locale возвращает язык по умолчанию
+func (c *Config) Locale() templates.Locale {
+	locale, ok := templates.ParseLocale(c.Language)
+	if !ok {
+		return templates.DefaultLocale
+	}
+	return locale
+}
+
+// LogLevel возвращает уровень логирования; в режиме отладки всегда debug
+func (c *Config) LogLevel() string {
+	if c.Debug {
+		return "debug"
+	}
+	return c.Log.Level
+}
+
+// Redacted возвращает YAML представление конфигурации со скрытым токеном
+func (c *Config) Redacted() (string, error) {
+	redacted := *c
+	if redacted.BotToken != "" {
+		redacted.BotToken = redactedToken
+	}
+	if redacted.Telegram.Webhook.SecretToken != "" {
+		redacted.Telegram.Webhook.SecretToken = redactedToken
+	}
+	if redacted.Web.Secret != "" {
+		redacted.Web.Secret = redactedToken
+	}
+	redacted.Storage.DSN = redactDSN(redacted.Storage.DSN)
+
+	data, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// redactDSN скрывает пароль в строке подключения (URL или "key=value")
+func redactDSN(dsn string) string {
+	if parsed, err := url.Parse(dsn); err == nil && parsed.Scheme != "" && parsed.User != nil {
+		if _, ok := parsed.User.Password(); ok {
+			parsed.User = url.UserPassword(parsed.User.Username(), redactedToken)
+			// URL экранирует угловые скобки, возвращаем маркер в читаемом виде
+			return strings.Replace(parsed.String(), url.PathEscape(redactedToken), redactedToken, 1)
+		}
+		return dsn
+	}
+	return dsnPasswordRx.ReplaceAllString(dsn, "${1}"+redactedToken)
 }
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"math/rand"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// selectUser выбирает участника по стратегии номинации.
+// stats должна содержать всех участников чата с количеством их побед.
+func selectUser(strategy domain.SelectionStrategy, stats []domain.UserStats, rng *rand.Rand) domain.User {
+	switch strategy {
+	case domain.StrategyFair:
+		// Вес участника обратно пропорционален числу его побед
+		weights := make([]float64, len(stats))
+		total := 0.0
+		for i, stat := range stats {
+			weights[i] = 1 / float64(stat.Count+1)
+			total += weights[i]
+		}
+
+		point := rng.Float64() * total
+		for i, weight := range weights {
+			if point < weight {
+				return stats[i].User
+			}
+			point -= weight
+		}
+		return stats[len(stats)-1].User
+	case domain.StrategyRotation:
+		// Выбираем только среди участников с наименьшим числом побед
+		minCount := stats[0].Count
+		for _, stat := range stats {
+			if stat.Count < minCount {
+				minCount = stat.Count
+			}
+		}
+
+		var candidates []domain.User
+		for _, stat := range stats {
+			if stat.Count == minCount {
+				candidates = append(candidates, stat.User)
+			}
+		}
+		return candidates[rng.Intn(len(candidates))]
+	default:
+		return stats[rng.Intn(len(stats))].User
+	}
+}
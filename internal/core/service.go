@@ -0,0 +1,225 @@
+// Package core содержит логику бота без привязки к мессенджеру: выбор участника дня,
+// статистику, информацию о чате и настройки чата. Методы принимают и возвращают
+// обычные значения Go, а тексты ответов и работу с Telegram берут на себя обработчики.
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+)
+
+// TitleReset аргумент смены названия роли, возвращающий название по умолчанию
+const TitleReset = "reset"
+
+var (
+	// ErrNoActiveUsers в чате нет участников, из которых можно выбрать
+	ErrNoActiveUsers = errors.New("no active users")
+	// ErrInvalidLanguage язык не поддерживается ботом
+	ErrInvalidLanguage = errors.New("invalid language")
+	// ErrInvalidTitle название роли пустое или длиннее templates.MaxTitleLength
+	ErrInvalidTitle = errors.New("invalid title")
+)
+
+// DrawResult результат выбора участника дня
+type DrawResult struct {
+	User domain.User
+	// AlreadySelected участник был выбран раньше, новый выбор не сохранялся
+	AlreadySelected bool
+}
+
+// ChatInfo сводка по чату в номинации
+type ChatInfo struct {
+	// Users сколько участников бот видел в чате
+	Users int
+	// Participants сколько участников в статистике номинации
+	Participants int
+	// Today выбранный сегодня участник; nil, если выбора еще не было
+	Today *domain.User
+}
+
+// Service логика бота над репозиториями
+type Service struct {
+	userRepo           repository.UserRepository
+	personOfTheDayRepo repository.PersonOfTheDayRepository
+	chatSettingsRepo   repository.ChatSettingsRepository
+	// rngMu защищает rng: *rand.Rand нельзя использовать из нескольких горутин
+	rngMu sync.Mutex
+	rng   *rand.Rand
+	clock clock.Clock
+}
+
+// NewService создает сервис логики бота. Сегодняшний день берется из clk
+// в его часовом поясе; nil — системные часы.
+func NewService(
+	userRepo repository.UserRepository,
+	personOfTheDayRepo repository.PersonOfTheDayRepository,
+	chatSettingsRepo repository.ChatSettingsRepository,
+	rng *rand.Rand,
+	clk clock.Clock,
+) *Service {
+	return &Service{
+		userRepo:           userRepo,
+		personOfTheDayRepo: personOfTheDayRepo,
+		chatSettingsRepo:   chatSettingsRepo,
+		rng:                rng,
+		clock:              clock.OrSystem(clk),
+	}
+}
+
+// TrackUser запоминает участника чата, написавшего сообщение
+func (s *Service) TrackUser(user domain.User) error {
+	if err := s.userRepo.Add(user); err != nil {
+		return fmt.Errorf("failed to add user: %w", err)
+	}
+	return nil
+}
+
+// Draw выбирает участника дня в номинации по ее стратегии. Если сегодня
+// участник уже выбран, возвращает его с AlreadySelected.
+func (s *Service) Draw(nomination domain.Nomination) (DrawResult, error) {
+	// Одно время на проверку и сохранение: выбор около полуночи не попадет в соседний день
+	now := s.clock.Now()
+	today, err := s.personOfTheDayRepo.GetByDate(nomination.ChatID, nomination.ID, now)
+	if err != nil {
+		return DrawResult{}, fmt.Errorf("failed to get today's pick: %w", err)
+	}
+	if today != nil {
+		return DrawResult{User: *today, AlreadySelected: true}, nil
+	}
+
+	// Статистика содержит всех активных участников вместе с их победами в номинации
+	stats, err := s.personOfTheDayRepo.GetUserStats(nomination.ChatID, nomination.ID)
+	if err != nil {
+		return DrawResult{}, fmt.Errorf("failed to get stats: %w", err)
+	}
+	if len(stats) == 0 {
+		return DrawResult{}, ErrNoActiveUsers
+	}
+
+	s.rngMu.Lock()
+	selected := selectUser(nomination.Strategy, stats, s.rng)
+	s.rngMu.Unlock()
+
+	// Insert не перезаписывает выбор: если параллельный выбор успел сохраниться раньше,
+	// возвращается он, и в чате объявляется только один участник дня
+	inserted, err := s.personOfTheDayRepo.Insert(selected.ID, nomination.ChatID, nomination.ID, now)
+	if err != nil {
+		return DrawResult{}, fmt.Errorf("failed to save pick: %w", err)
+	}
+	if !inserted {
+		winner, err := s.personOfTheDayRepo.GetByDate(nomination.ChatID, nomination.ID, now)
+		if err != nil {
+			return DrawResult{}, fmt.Errorf("failed to get today's pick: %w", err)
+		}
+		if winner != nil {
+			return DrawResult{User: *winner, AlreadySelected: true}, nil
+		}
+
+		// Сегодняшний выбор принадлежит участнику, который уже пишет в другом чате:
+		// такой выбор не виден в чате и заменяется новым. Замена условная, поэтому
+		// из параллельных выборов ее выполняет только один, остальные объявляют его
+		replaced, err := s.personOfTheDayRepo.ReplaceStale(selected.ID, nomination.ChatID, nomination.ID, now)
+		if err != nil {
+			return DrawResult{}, fmt.Errorf("failed to save pick: %w", err)
+		}
+		if !replaced {
+			winner, err := s.personOfTheDayRepo.GetByDate(nomination.ChatID, nomination.ID, now)
+			if err != nil {
+				return DrawResult{}, fmt.Errorf("failed to get today's pick: %w", err)
+			}
+			if winner == nil {
+				return DrawResult{}, fmt.Errorf("failed to save pick: today's pick in chat %d changed concurrently", nomination.ChatID)
+			}
+			return DrawResult{User: *winner, AlreadySelected: true}, nil
+		}
+	}
+
+	return DrawResult{User: selected}, nil
+}
+
+// Stats возвращает участников номинации с победами по убыванию
+func (s *Service) Stats(nomination domain.Nomination) ([]domain.UserStats, error) {
+	stats, err := s.personOfTheDayRepo.GetUserStats(nomination.ChatID, nomination.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+	return stats, nil
+}
+
+// Info возвращает сводку по чату и сегодняшний выбор в номинации
+func (s *Service) Info(nomination domain.Nomination) (ChatInfo, error) {
+	stats, err := s.personOfTheDayRepo.GetUserStats(nomination.ChatID, nomination.ID)
+	if err != nil {
+		return ChatInfo{}, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	users, err := s.userRepo.GetByChatID(nomination.ChatID)
+	if err != nil {
+		return ChatInfo{}, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	today, err := s.personOfTheDayRepo.GetByDate(nomination.ChatID, nomination.ID, s.clock.Now())
+	if err != nil {
+		return ChatInfo{}, fmt.Errorf("failed to get today's pick: %w", err)
+	}
+
+	return ChatInfo{Users: len(users), Participants: len(stats), Today: today}, nil
+}
+
+// SetLanguage меняет язык чата по коду языка (ru, en, uk)
+func (s *Service) SetLanguage(chatID int64, code string) (templates.Locale, error) {
+	locale, ok := templates.ParseLocale(code)
+	if !ok {
+		return "", ErrInvalidLanguage
+	}
+
+	if err := s.chatSettingsRepo.SetLanguage(chatID, string(locale)); err != nil {
+		return "", fmt.Errorf("failed to set language: %w", err)
+	}
+	return locale, nil
+}
+
+// SetTitle меняет название и эмодзи роли чата по аргументу "[эмодзи] название".
+// TitleReset возвращает название по умолчанию: title и emoji пустые.
+func (s *Service) SetTitle(chatID int64, payload string) (title, emoji string, err error) {
+	payload = strings.TrimSpace(payload)
+	if payload != TitleReset {
+		title, emoji = ParseTitle(payload)
+		if !ValidTitle(title) {
+			return "", "", ErrInvalidTitle
+		}
+	}
+
+	if err := s.chatSettingsRepo.SetTitle(chatID, title, emoji); err != nil {
+		return "", "", fmt.Errorf("failed to set title: %w", err)
+	}
+	return title, emoji, nil
+}
+
+// ParseTitle разбирает аргумент "[эмодзи] название": первое слово считается эмодзи,
+// если в нем нет букв и цифр
+func ParseTitle(payload string) (title, emoji string) {
+	first, rest, found := strings.Cut(payload, " ")
+	if !found || strings.IndexFunc(first, func(r rune) bool {
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	}) >= 0 {
+		return payload, ""
+	}
+
+	return strings.TrimSpace(rest), first
+}
+
+// ValidTitle проверяет, что название роли не пустое и не длиннее templates.MaxTitleLength
+func ValidTitle(title string) bool {
+	return title != "" && utf8.RuneCountInString(title) <= templates.MaxTitleLength
+}
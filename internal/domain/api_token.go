@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// APIToken токен доступа к HTTP API статистики чата.
+// Хранится только SHA-256 хеш токена, сам токен показывается один раз при создании.
+type APIToken struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	TokenHash string    `json:"-" db:"token_hash"`
+	CreatedBy int64     `json:"created_by" db:"created_by"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+// ChatSettings представляет настройки чата
+type ChatSettings struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Language  string    `json:"language" db:"language"`
+	Title     string    `json:"title" db:"title"`
+	Emoji     string    `json:"emoji" db:"emoji"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+// CommandAlias представляет алиас команды в чате, например /hero → /pidor
+type CommandAlias struct {
+	ChatID    int64     `json:"chat_id" db:"chat_id"`
+	Alias     string    `json:"alias" db:"alias"`
+	Command   string    `json:"command" db:"command"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,28 @@
+package domain
+
+// ImportReason причина, по которой строка импорта истории не добавлена
+type ImportReason string
+
+const (
+	// ReasonInvalidRow строку не удалось разобрать
+	ReasonInvalidRow ImportReason = "invalid"
+	// ReasonUserNotFound участник не найден среди пользователей чата
+	ReasonUserNotFound ImportReason = "user"
+	// ReasonNominationNotFound в чате нет такой номинации
+	ReasonNominationNotFound ImportReason = "nomination"
+)
+
+// ImportUnmatched описывает строку файла, которую не удалось импортировать
+type ImportUnmatched struct {
+	Line   int          `json:"line"`
+	Value  string       `json:"value"`
+	Reason ImportReason `json:"reason"`
+}
+
+// ImportResult представляет отчет об импорте истории выборов
+type ImportResult struct {
+	Total     int               `json:"total"`
+	Imported  int               `json:"imported"`
+	Skipped   int               `json:"skipped"`
+	Unmatched []ImportUnmatched `json:"unmatched"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import "time"
+
+// DefaultNominationID обозначает основную номинацию чата ("пидор дня")
+const DefaultNominationID int64 = 0
+
+// DefaultNominationCommand команда основной номинации чата
+const DefaultNominationCommand = "pidor"
+
+// SelectionStrategy определяет способ выбора участника в номинации
+type SelectionStrategy string
+
+const (
+	// StrategyRandom выбирает любого участника с равной вероятностью
+	StrategyRandom SelectionStrategy = "random"
+	// StrategyFair уменьшает шансы участников, которые уже побеждали
+	StrategyFair SelectionStrategy = "fair"
+	// StrategyRotation выбирает только среди участников с наименьшим числом побед
+	StrategyRotation SelectionStrategy = "rotation"
+)
+
+// SelectionStrategies возвращает список поддерживаемых стратегий
+func SelectionStrategies() []SelectionStrategy {
+	return []SelectionStrategy{StrategyRandom, StrategyFair, StrategyRotation}
+}
+
+// IsValid проверяет, поддерживается ли стратегия
+func (s SelectionStrategy) IsValid() bool {
+	for _, strategy := range SelectionStrategies() {
+		if s == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+// Nomination представляет независимый ежедневный выбор в чате
+type Nomination struct {
+	ID        int64             `json:"id" db:"id"`
+	ChatID    int64             `json:"chat_id" db:"chat_id"`
+	Command   string            `json:"command" db:"command"`
+	Title     string            `json:"title" db:"title"`
+	Emoji     string            `json:"emoji" db:"emoji"`
+	Strategy  SelectionStrategy `json:"strategy" db:"strategy"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+// IsDefault проверяет, является ли номинация основной номинацией чата
+func (n Nomination) IsDefault() bool {
+	return n.ID == DefaultNominationID
+}
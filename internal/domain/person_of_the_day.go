@@ -4,11 +4,20 @@ import "time"
 
 // PersonOfTheDay представляет запись о человеке дня
 type PersonOfTheDay struct {
-	ID        int       `json:"id" db:"id"`
-	UserID    int64     `json:"user_id" db:"user_id"`
-	ChatID    int64     `json:"chat_id" db:"chat_id"`
-	Date      time.Time `json:"date" db:"date"`
-	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	ID           int       `json:"id" db:"id"`
+	UserID       int64     `json:"user_id" db:"user_id"`
+	ChatID       int64     `json:"chat_id" db:"chat_id"`
+	NominationID int64     `json:"nomination_id" db:"nomination_id"`
+	Date         time.Time `json:"date" db:"date"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// PersonOfTheDayRecord представляет запись истории выборов вместе с участником
+// и командой номинации. Если участник удален из базы, в User заполнен только ID.
+type PersonOfTheDayRecord struct {
+	PersonOfTheDay
+	User       User   `json:"user"`
+	Nomination string `json:"nomination"`
 }
 
 // UserStats представляет статистику пользователя
@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// handleAliases показывает и изменяет алиасы команд чата
+func (h *CommandHandler) handleAliases(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidoralias")
+	messages := h.messages(c)
+
+	args := strings.Fields(c.Message().Payload)
+	if len(args) == 0 {
+		aliases, err := h.aliasRepo.GetByChatID(c.Chat().ID)
+		if err != nil {
+			h.log(c).Error("Ошибка при получении алиасов", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAliases))
+			return nil
+		}
+
+		SafeSendMessage(c, messages.BuildAliasesMessage(aliases))
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		return h.addAlias(c, messages, args[1:])
+	case "remove":
+		return h.removeAlias(c, messages, args[1:])
+	default:
+		SafeSendMessage(c, messages.AliasUsage())
+		return nil
+	}
+}
+
+// addAlias добавляет алиас: add алиас команда
+func (h *CommandHandler) addAlias(c telebot.Context, messages *templates.MessageService, args []string) error {
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	if len(args) != 2 {
+		SafeSendMessage(c, messages.AliasUsage())
+		return nil
+	}
+
+	alias := normalizeCommand(args[0])
+	command := normalizeCommand(args[1])
+	if !isValidNominationCommand(alias) {
+		SafeSendMessage(c, messages.AliasUsage())
+		return nil
+	}
+
+	// Алиас не может перекрывать встроенные команды и команды номинаций
+	nomination, _, err := h.resolveNominationCommand(c.Chat().ID, alias)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении номинаций", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominations))
+		return nil
+	}
+	if h.router.Has(alias) || nomination != nil {
+		SafeSendMessage(c, messages.AliasExists(alias))
+		return nil
+	}
+
+	// Алиас должен указывать на существующую команду
+	if !h.router.Has(command) {
+		nomination, _, err := h.resolveNominationCommand(c.Chat().ID, command)
+		if err != nil {
+			h.log(c).Error("Ошибка при получении номинаций", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominations))
+			return nil
+		}
+		if nomination == nil {
+			SafeSendMessage(c, messages.AliasTargetNotFound(command))
+			return nil
+		}
+	}
+
+	if err := h.aliasRepo.Set(c.Chat().ID, alias, command); err != nil {
+		h.log(c).Error("Ошибка при сохранении алиаса", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAliasSave))
+		return nil
+	}
+
+	SafeSendMessage(c, messages.AliasAdded(alias, command))
+	return nil
+}
+
+// removeAlias удаляет алиас: remove алиас
+func (h *CommandHandler) removeAlias(c telebot.Context, messages *templates.MessageService, args []string) error {
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	if len(args) != 1 {
+		SafeSendMessage(c, messages.AliasUsage())
+		return nil
+	}
+
+	alias := normalizeCommand(args[0])
+	existing, err := h.aliasRepo.Get(c.Chat().ID, alias)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении алиаса", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAlias))
+		return nil
+	}
+
+	if existing == nil {
+		SafeSendMessage(c, messages.AliasNotFound(alias))
+		return nil
+	}
+
+	if err := h.aliasRepo.Delete(c.Chat().ID, alias); err != nil {
+		h.log(c).Error("Ошибка при удалении алиаса", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAliasDelete))
+		return nil
+	}
+
+	SafeSendMessage(c, messages.AliasRemoved(alias))
+	return nil
+}
+
+// normalizeCommand приводит команду к виду без "/" в нижнем регистре
+func normalizeCommand(command string) string {
+	return strings.ToLower(strings.TrimPrefix(command, "/"))
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/api"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// handleAPIToken показывает, создает и отзывает токен HTTP API чата:
+// /pidorapi [new|revoke]. Новый токен отправляется администратору в личные сообщения,
+// в базе хранится только его хеш.
+func (h *CommandHandler) handleAPIToken(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorapi")
+	messages := h.messages(c)
+
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	if h.apiTokenRepo == nil {
+		SafeSendMessage(c, messages.APIDisabled())
+		return nil
+	}
+
+	chatID := c.Chat().ID
+	switch strings.ToLower(strings.TrimSpace(c.Message().Payload)) {
+	case "":
+		token, err := h.apiTokenRepo.GetByChatID(chatID)
+		if err != nil {
+			h.log(c).Error("Ошибка при получении токена API", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorTokenGet))
+			return nil
+		}
+
+		SafeSendMessage(c, messages.BuildAPITokenStatus(token))
+	case "new":
+		token, hash, err := api.GenerateToken()
+		if err != nil {
+			h.log(c).Error("Ошибка при создании токена API", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorTokenCreate))
+			return nil
+		}
+
+		// Токен сохраняется только после доставки, чтобы старый не пропал впустую
+		text := messages.APITokenCreated(c.Chat().Title, token, api.ChatPath(chatID))
+		if _, err := c.Bot().Send(c.Sender(), text); err != nil {
+			h.log(c).Warn("Не удалось отправить токен API в личные сообщения", "error", err)
+			UpdateMetrics(c).SendError(err)
+			SafeSendMessage(c, messages.APITokenPrivateFailed())
+			return nil
+		}
+
+		if err := h.apiTokenRepo.Set(chatID, hash, c.Sender().ID); err != nil {
+			h.log(c).Error("Ошибка при сохранении токена API", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorTokenSave))
+			return nil
+		}
+
+		SafeSendMessage(c, messages.APITokenSent())
+	case "revoke":
+		if err := h.apiTokenRepo.Delete(chatID); err != nil {
+			h.log(c).Error("Ошибка при удалении токена API", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorTokenDelete))
+			return nil
+		}
+
+		SafeSendMessage(c, messages.APITokenRevoked())
+	default:
+		SafeSendMessage(c, messages.APIUsage())
+	}
+
+	return nil
+}
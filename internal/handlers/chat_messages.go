@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// ChatMessages возвращает сервис сообщений с языком и названием роли чата.
+// В группах используются настройки чата, в личных чатах — язык отправителя.
+func ChatMessages(
+	c telebot.Context,
+	messageService *templates.MessageService,
+	chatSettingsRepo repository.ChatSettingsRepository,
+) *templates.MessageService {
+	if c.Chat() == nil {
+		return messageService.WithLocale(messageService.DefaultLocale())
+	}
+
+	if c.Chat().Type == telebot.ChatPrivate {
+		if c.Sender() != nil {
+			if locale, ok := templates.ParseLocale(c.Sender().LanguageCode); ok {
+				return messageService.WithLocale(locale)
+			}
+		}
+		return messageService.WithLocale(messageService.DefaultLocale())
+	}
+
+	settings, err := chatSettingsRepo.Get(c.Chat().ID)
+	if err != nil {
+		UpdateLogger(c, nil).Error("Ошибка получения настроек чата", "error", err)
+		return messageService.WithLocale(messageService.DefaultLocale())
+	}
+
+	if settings == nil {
+		return messageService.WithLocale(messageService.DefaultLocale())
+	}
+
+	locale, ok := templates.ParseLocale(settings.Language)
+	if !ok {
+		locale = messageService.DefaultLocale()
+	}
+
+	return messageService.WithLocale(locale).WithTitle(settings.Title, settings.Emoji)
+}
@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"math/rand"
-	"time"
+	"log/slog"
+	"strings"
 
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/core"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
 	"github.com/pavel-one/day-of-the-bot/internal/repository"
 	"github.com/pavel-one/day-of-the-bot/internal/templates"
 	"gopkg.in/telebot.v3"
@@ -16,8 +19,16 @@ type CommandHandler struct {
 	api                *telebot.Bot
 	userRepo           repository.UserRepository
 	personOfTheDayRepo repository.PersonOfTheDayRepository
+	chatSettingsRepo   repository.ChatSettingsRepository
+	nominationRepo     repository.NominationRepository
+	aliasRepo          repository.CommandAliasRepository
+	apiTokenRepo       repository.APITokenRepository
+	webLinks           WebLinks
 	messageService     *templates.MessageService
-	rng                *rand.Rand
+	core               *core.Service
+	clock              clock.Clock
+	logger             *slog.Logger
+	router             *CommandRouter
 }
 
 // NewCommandHandler создает новый обработчик команд
@@ -25,132 +36,321 @@ func NewCommandHandler(
 	api *telebot.Bot,
 	userRepo repository.UserRepository,
 	personOfTheDayRepo repository.PersonOfTheDayRepository,
+	chatSettingsRepo repository.ChatSettingsRepository,
+	nominationRepo repository.NominationRepository,
+	aliasRepo repository.CommandAliasRepository,
+	apiTokenRepo repository.APITokenRepository,
+	webLinks WebLinks,
 	messageService *templates.MessageService,
-	rng *rand.Rand,
+	coreService *core.Service,
+	clk clock.Clock,
+	logger *slog.Logger,
 ) *CommandHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &CommandHandler{
 		api:                api,
 		userRepo:           userRepo,
 		personOfTheDayRepo: personOfTheDayRepo,
+		chatSettingsRepo:   chatSettingsRepo,
+		nominationRepo:     nominationRepo,
+		aliasRepo:          aliasRepo,
+		apiTokenRepo:       apiTokenRepo,
+		webLinks:           webLinks,
 		messageService:     messageService,
-		rng:                rng,
+		core:               coreService,
+		clock:              clock.OrSystem(clk),
+		logger:             logger.With("component", "commands"),
+	}
+}
+
+// CommandScope определяет, кому команда показывается в меню Telegram
+type CommandScope int
+
+const (
+	// ScopeAllGroups показывает команду всем участникам групп
+	ScopeAllGroups CommandScope = iota
+	// ScopeAdmins показывает команду только администраторам групп
+	ScopeAdmins
+)
+
+// CommandInfo описывает встроенную команду бота.
+// Описание команды для каждого языка берется из шаблонов по имени.
+type CommandInfo struct {
+	Name    string
+	Scope   CommandScope
+	Hidden  bool
+	Handler telebot.HandlerFunc
+}
+
+// Commands возвращает реестр встроенных команд бота.
+// По нему регистрируются обработчики, строится справка и меню команд Telegram.
+func (h *CommandHandler) Commands() []CommandInfo {
+	return []CommandInfo{
+		{Name: "start", Hidden: true, Handler: h.handleStart},
+		{Name: "pidor", Handler: h.handlePersonOfTheDay},
+		{Name: "pidorstats", Handler: h.handleStats},
+		{Name: "pidorinfo", Handler: h.handleInfo},
+		{Name: "pidorlang", Handler: h.handleLanguage},
+		{Name: "pidortitle", Scope: ScopeAdmins, Handler: h.handleTitle},
+		{Name: "pidornom", Scope: ScopeAdmins, Handler: h.handleNominations},
+		{Name: "pidoralias", Scope: ScopeAdmins, Handler: h.handleAliases},
+		{Name: "pidorexport", Scope: ScopeAdmins, Handler: h.handleExport},
+		{Name: "pidorimport", Scope: ScopeAdmins, Handler: h.handleImport},
+		{Name: "pidorapi", Scope: ScopeAdmins, Handler: h.handleAPIToken},
+		{Name: "help", Handler: h.handleStart},
 	}
 }
 
-// RegisterHandlers регистрирует обработчики команд
+// HelpCommands возвращает команды для текста справки
+func (h *CommandHandler) HelpCommands() []templates.CommandHelp {
+	var commands []templates.CommandHelp
+	for _, command := range h.Commands() {
+		if command.Hidden {
+			continue
+		}
+		commands = append(commands, templates.CommandHelp{
+			Name:      command.Name,
+			AdminOnly: command.Scope == ScopeAdmins,
+		})
+	}
+	return commands
+}
+
+// RegisterHandlers регистрирует обработчики команд в маршрутизаторе.
+// Команды не регистрируются в telebot напрямую: их разбирает Route,
+// чтобы учитывать алиасы и номинации чатов.
 func (h *CommandHandler) RegisterHandlers(bot *telebot.Bot) {
-	bot.Handle("/start", h.handleStart)
-	bot.Handle("/help", h.handleStart)
-	bot.Handle("/pidor", h.handlePersonOfTheDay)
-	bot.Handle("/pidorstats", h.handleStats)
-	bot.Handle("/pidorinfo", h.handleInfo)
+	h.router = NewCommandRouter(bot.Me.Username, h.aliasRepo, h.logger)
+
+	for _, command := range h.Commands() {
+		h.router.Add(command.Name, command.Handler)
+	}
+
+	h.router.SetFallback(h.handleNominationCommand)
+	h.router.SetUnknown(h.handleUnknown)
+}
+
+// PublishCommands публикует меню команд в Telegram для каждого языка:
+// участникам групп — общие команды, администраторам — все команды
+func (h *CommandHandler) PublishCommands(bot *telebot.Bot) error {
+	groupScope := telebot.CommandScope{Type: telebot.CommandScopeAllGroupChats}
+	adminScope := telebot.CommandScope{Type: telebot.CommandScopeAllChatAdmin}
+
+	for _, locale := range templates.SupportedLocales() {
+		messages := h.messageService.WithLocale(locale)
+
+		var groupCommands, adminCommands []telebot.Command
+		for _, command := range h.Commands() {
+			if command.Hidden {
+				continue
+			}
+
+			description := messages.CommandDescription(command.Name)
+			if description == "" {
+				return fmt.Errorf("command %s has no description for locale %s", command.Name, locale)
+			}
+
+			botCommand := telebot.Command{Text: command.Name, Description: description}
+			adminCommands = append(adminCommands, botCommand)
+			if command.Scope == ScopeAllGroups {
+				groupCommands = append(groupCommands, botCommand)
+			}
+		}
+
+		// Для языка по умолчанию дополнительно публикуем меню без кода языка,
+		// оно показывается пользователям с неподдерживаемым языком
+		languageCodes := []string{string(locale)}
+		if locale == h.messageService.DefaultLocale() {
+			languageCodes = append(languageCodes, "")
+		}
+
+		for _, languageCode := range languageCodes {
+			if err := bot.SetCommands(groupCommands, groupScope, languageCode); err != nil {
+				return fmt.Errorf("failed to set group commands for %q: %w", languageCode, err)
+			}
+			if err := bot.SetCommands(adminCommands, adminScope, languageCode); err != nil {
+				return fmt.Errorf("failed to set admin commands for %q: %w", languageCode, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Route обрабатывает сообщение, если оно является командой бота
+func (h *CommandHandler) Route(c telebot.Context) (bool, error) {
+	return h.router.Route(c)
+}
+
+// log возвращает логгер текущего обновления
+func (h *CommandHandler) log(c telebot.Context) *slog.Logger {
+	return UpdateLogger(c, h.logger)
+}
+
+// messages возвращает сервис сообщений с настройками чата
+func (h *CommandHandler) messages(c telebot.Context) *templates.MessageService {
+	return ChatMessages(c, h.messageService, h.chatSettingsRepo)
 }
 
 func (h *CommandHandler) handleStart(c telebot.Context) error {
-	log.Printf("Команда /start вызвана в чате %d пользователем %d", c.Chat().ID, c.Sender().ID)
-	SafeSendMessage(c, h.messageService.HelpText())
+	h.log(c).Info("Команда вызвана", "command", "start")
+	messages := h.messages(c)
+
+	SafeSendMessage(c, messages.HelpText(h.HelpCommands()))
+	return nil
+}
+
+func (h *CommandHandler) handleUnknown(c telebot.Context) error {
+	h.log(c).Info("Неизвестная команда", "text", c.Text())
+	SafeSendMessage(c, h.messages(c).UnknownCommand())
 	return nil
 }
 
 func (h *CommandHandler) handlePersonOfTheDay(c telebot.Context) error {
-	log.Printf("Команда /pidor вызвана в чате %d пользователем %d", c.Chat().ID, c.Sender().ID)
-	// Проверяем, выбран ли пидор дня на сегодня
-	todayPerson, err := h.personOfTheDayRepo.GetByDate(c.Chat().ID, time.Now())
+	h.log(c).Info("Команда вызвана", "command", "pidor")
+	return h.draw(c, h.defaultNomination(c))
+}
+
+func (h *CommandHandler) handleStats(c telebot.Context) error {
+	if strings.EqualFold(strings.TrimSpace(c.Message().Payload), "web") {
+		return h.handleWebLink(c)
+	}
+	return h.stats(c, h.defaultNomination(c))
+}
+
+func (h *CommandHandler) handleInfo(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorinfo")
+	return h.info(c, h.defaultNomination(c))
+}
+
+// defaultNomination возвращает основную номинацию чата
+func (h *CommandHandler) defaultNomination(c telebot.Context) domain.Nomination {
+	return domain.Nomination{
+		ID:       domain.DefaultNominationID,
+		ChatID:   c.Chat().ID,
+		Command:  domain.DefaultNominationCommand,
+		Strategy: domain.StrategyRandom,
+	}
+}
+
+// draw выбирает участника дня в номинации
+func (h *CommandHandler) draw(c telebot.Context, nomination domain.Nomination) error {
+	messages := h.messages(c).ForNomination(nomination)
+
+	result, err := h.core.Draw(nomination)
+	if errors.Is(err, core.ErrNoActiveUsers) {
+		SafeSendMessage(c, messages.NoActiveUsers())
+		return nil
+	}
 	if err != nil {
-		log.Printf("Ошибка при проверке пидора дня: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при проверке пидора дня"))
+		h.log(c).Error("Ошибка при выборе участника", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorDraw))
 		return nil
 	}
 
-	if todayPerson != nil {
-		SafeSendMessage(c, h.messageService.PersonAlreadySelected(*todayPerson))
+	if result.AlreadySelected {
+		SafeSendMessage(c, messages.PersonAlreadySelected(result.User))
 		return nil
 	}
 
-	// Получаем список активных пользователей
-	users, err := h.userRepo.GetByChatID(c.Chat().ID)
+	UpdateMetrics(c).Draw(c.Chat().ID)
+	SafeSendMessage(c, messages.PersonSelected(result.User))
+	return nil
+}
+
+// stats отправляет статистику номинации; /pidorstats img присылает ее картинкой
+func (h *CommandHandler) stats(c telebot.Context, nomination domain.Nomination) error {
+	if strings.EqualFold(strings.TrimSpace(c.Message().Payload), statsImagePayload) {
+		return h.statsImage(c, nomination)
+	}
+
+	messages := h.messages(c).ForNomination(nomination)
+
+	stats, err := h.core.Stats(nomination)
 	if err != nil {
-		log.Printf("Ошибка при получении списка участников: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при получении списка участников"))
+		h.log(c).Error("Ошибка при получении статистики", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorStats))
 		return nil
 	}
 
-	if len(users) == 0 {
-		SafeSendMessage(c, h.messageService.NoActiveUsers())
+	if len(stats) == 0 {
+		SafeSendMessage(c, messages.StatsEmpty())
 		return nil
 	}
 
-	// Выбираем случайного пользователя
-	selectedUser := users[h.rng.Intn(len(users))]
+	SafeSendMessage(c, messages.BuildStatsMessage(stats))
+	return nil
+}
 
-	// Сохраняем результат
-	err = h.personOfTheDayRepo.Set(selectedUser.ID, c.Chat().ID, time.Now())
+// info отправляет информацию о чате и сегодняшнем выборе в номинации
+func (h *CommandHandler) info(c telebot.Context, nomination domain.Nomination) error {
+	messages := h.messages(c).ForNomination(nomination)
+
+	info, err := h.core.Info(nomination)
 	if err != nil {
-		log.Printf("Ошибка при сохранении результата: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при сохранении результата"))
+		h.log(c).Error("Ошибка при получении информации", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorInfo))
 		return nil
 	}
 
-	SafeSendMessage(c, h.messageService.PersonSelected(selectedUser))
+	SafeSendMessage(c, messages.ChatInfo(info.Users, info.Participants, info.Today))
 	return nil
 }
 
-func (h *CommandHandler) handleStats(c telebot.Context) error {
-	stats, err := h.personOfTheDayRepo.GetUserStats(c.Chat().ID)
-	if err != nil {
-		log.Printf("Ошибка при получении статистики: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при получении статистики"))
+func (h *CommandHandler) handleLanguage(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorlang")
+	messages := h.messages(c)
+
+	args := c.Args()
+	if len(args) == 0 {
+		SafeSendMessage(c, messages.LanguageUsage())
 		return nil
 	}
 
-	if len(stats) == 0 {
-		SafeSendMessage(c, "Статистика пока пуста.")
+	locale, err := h.core.SetLanguage(c.Chat().ID, args[0])
+	if errors.Is(err, core.ErrInvalidLanguage) {
+		SafeSendMessage(c, messages.LanguageUsage())
+		return nil
+	}
+	if err != nil {
+		h.log(c).Error("Ошибка при смене языка", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorLanguage))
 		return nil
 	}
 
-	SafeSendMessage(c, h.messageService.BuildStatsMessage(stats))
+	SafeSendMessage(c, messages.WithLocale(locale).LanguageChanged())
 	return nil
 }
 
-func (h *CommandHandler) handleInfo(c telebot.Context) error {
-	// Логируем информацию о сообщении для debug
-	log.Printf("Команда /pidorinfo вызвана в чате %d пользователем %d", c.Chat().ID, c.Sender().ID)
+func (h *CommandHandler) handleTitle(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidortitle")
+	messages := h.messages(c)
 
-	// Получаем статистику чата
-	stats, err := h.personOfTheDayRepo.GetUserStats(c.Chat().ID)
-	if err != nil {
-		log.Printf("Ошибка при получении статистики: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при получении информации"))
+	payload := strings.TrimSpace(c.Message().Payload)
+	if payload == "" {
+		SafeSendMessage(c, messages.TitleUsage())
 		return nil
 	}
 
-	// Получаем количество активных пользователей
-	users, err := h.userRepo.GetByChatID(c.Chat().ID)
-	if err != nil {
-		log.Printf("Ошибка при получении списка пользователей: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при получении информации о пользователях"))
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
 		return nil
 	}
 
-	// Проверяем, выбран ли пидор на сегодня
-	todayPerson, err := h.personOfTheDayRepo.GetByDate(c.Chat().ID, time.Now())
-	if err != nil {
-		log.Printf("Ошибка при проверке пидора дня: %v", err)
-		SafeSendMessage(c, h.messageService.ErrorOccurred("при проверке пидора дня"))
+	title, emoji, err := h.core.SetTitle(c.Chat().ID, payload)
+	if errors.Is(err, core.ErrInvalidTitle) {
+		SafeSendMessage(c, messages.TitleUsage())
 		return nil
 	}
-
-	// Формируем информационное сообщение
-	infoMsg := fmt.Sprintf("📊 Информация о чате:\n\n")
-	infoMsg += fmt.Sprintf("👥 Активных пользователей: %d\n", len(users))
-	infoMsg += fmt.Sprintf("🏆 Записей в статистике: %d\n", len(stats))
-
-	if todayPerson != nil {
-		infoMsg += fmt.Sprintf("🎯 Пидор дня сегодня: %s", todayPerson.FullName())
-	} else {
-		infoMsg += "🎯 Пидор дня сегодня еще не выбран"
+	if err != nil {
+		h.log(c).Error("Ошибка при смене названия роли", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorTitle))
+		return nil
 	}
 
-	SafeSendMessage(c, infoMsg)
+	SafeSendMessage(c, messages.WithTitle(title, emoji).TitleChanged())
 	return nil
 }
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/history"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// handleExport отправляет администратору файл со всей историей выборов чата:
+// /pidorexport [csv|json]
+func (h *CommandHandler) handleExport(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorexport")
+	messages := h.messages(c)
+
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	format := history.FormatCSV
+	if payload := strings.TrimSpace(c.Message().Payload); payload != "" {
+		parsed, ok := history.ParseFormat(payload)
+		if !ok {
+			SafeSendMessage(c, messages.ExportUsage())
+			return nil
+		}
+		format = parsed
+	}
+
+	records, err := h.personOfTheDayRepo.GetHistory(c.Chat().ID)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении истории", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorHistory))
+		return nil
+	}
+
+	if len(records) == 0 {
+		SafeSendMessage(c, messages.ExportEmpty())
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := history.Write(&buf, format, records); err != nil {
+		h.log(c).Error("Ошибка при выгрузке истории", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorExport))
+		return nil
+	}
+
+	SafeSendDocument(c, &telebot.Document{
+		File:     telebot.FromReader(&buf),
+		FileName: format.FileName(c.Chat().ID, h.clock.Now()),
+		MIME:     format.MIME(),
+		Caption:  messages.ExportCaption(len(records)),
+	})
+	return nil
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bytes"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/render"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// statsImagePayload аргумент команды статистики, который присылает ее картинкой
+const statsImagePayload = "img"
+
+// statsImage отправляет таблицу лидеров и календарь победителей текущего месяца картинкой
+func (h *CommandHandler) statsImage(c telebot.Context, nomination domain.Nomination) error {
+	messages := h.messages(c).ForNomination(nomination)
+
+	stats, err := h.core.Stats(nomination)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении статистики", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorStats))
+		return nil
+	}
+
+	if len(stats) == 0 {
+		SafeSendMessage(c, messages.StatsEmpty())
+		return nil
+	}
+
+	history, err := h.personOfTheDayRepo.GetHistory(c.Chat().ID)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении истории", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorHistory))
+		return nil
+	}
+
+	now := h.clock.Now()
+	labels := render.Labels{
+		Title:       messages.StatsImageTitle(),
+		Leaderboard: messages.WebLeaderboard(),
+		ColumnUser:  messages.WebColumnUser(),
+		ColumnWins:  messages.WebColumnWins(),
+		Calendar:    messages.WebCalendar(),
+		Month:       messages.WebCalendarMonth(now),
+		Weekdays:    messages.WebWeekdays(),
+	}
+	if rest := len(stats) - render.MaxRows; rest > 0 {
+		labels.Rest = messages.StatsImageRest(rest)
+	}
+
+	var buf bytes.Buffer
+	if err := render.Stats(&buf, stats, history, nomination.ID, now, labels); err != nil {
+		h.log(c).Error("Ошибка при отрисовке статистики", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorStatsImage))
+		return nil
+	}
+
+	SafeSendPhoto(c, &telebot.Photo{
+		File:    telebot.FromReader(&buf),
+		Caption: messages.StatsImageCaption(),
+	})
+	return nil
+}
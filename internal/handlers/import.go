@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pavel-one/day-of-the-bot/internal/history"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// maxImportSize ограничивает размер импортируемого файла
+const maxImportSize = 5 << 20
+
+// handleImport импортирует историю выборов из файла другого бота.
+// Файл передается в сообщении с командой в подписи или в сообщении, на которое отвечает команда.
+func (h *CommandHandler) handleImport(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorimport")
+	messages := h.messages(c)
+
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	document := importDocument(c.Message())
+	if document == nil {
+		SafeSendMessage(c, messages.ImportUsage())
+		return nil
+	}
+
+	format, ok := importFormat(document)
+	if !ok {
+		SafeSendMessage(c, messages.ImportUsage())
+		return nil
+	}
+
+	if document.FileSize > maxImportSize {
+		SafeSendMessage(c, messages.ImportFailed(fmt.Errorf("file is larger than %d MB", maxImportSize>>20)))
+		return nil
+	}
+
+	reader, err := c.Bot().File(&document.File)
+	if err != nil {
+		h.log(c).Error("Ошибка при загрузке файла", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorFileDownload))
+		return nil
+	}
+	defer func() {
+		if err := reader.Close(); err != nil {
+			h.log(c).Warn("Ошибка закрытия файла", "error", err)
+		}
+	}()
+
+	rows, err := history.Parse(io.LimitReader(reader, maxImportSize), format)
+	if err != nil {
+		SafeSendMessage(c, messages.ImportFailed(err))
+		return nil
+	}
+
+	result, err := history.NewImporter(h.userRepo, h.personOfTheDayRepo, h.nominationRepo).Import(c.Chat().ID, rows)
+	if err != nil {
+		h.log(c).Error("Ошибка при импорте истории", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorImport))
+		return nil
+	}
+
+	h.log(c).Info("История импортирована",
+		"file", document.FileName, "total", result.Total, "imported", result.Imported,
+		"skipped", result.Skipped, "unmatched", len(result.Unmatched))
+	SafeSendMessage(c, messages.BuildImportReport(*result))
+	return nil
+}
+
+// importDocument возвращает файл из сообщения с командой или из сообщения, на которое оно отвечает
+func importDocument(message *telebot.Message) *telebot.Document {
+	if message.Document != nil {
+		return message.Document
+	}
+	if message.ReplyTo != nil && message.ReplyTo.Document != nil {
+		return message.ReplyTo.Document
+	}
+	return nil
+}
+
+// importFormat определяет формат файла по расширению, затем по MIME типу
+func importFormat(document *telebot.Document) (history.Format, bool) {
+	if format, ok := history.FormatFromFileName(document.FileName); ok {
+		return format, true
+	}
+
+	switch document.MIME {
+	case history.FormatCSV.MIME():
+		return history.FormatCSV, true
+	case history.FormatJSON.MIME():
+		return history.FormatJSON, true
+	default:
+		return "", false
+	}
+}
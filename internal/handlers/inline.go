@@ -0,0 +1,276 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// InlineCacheTTL время хранения инлайн-результатов участника в боте и на серверах Telegram
+const InlineCacheTTL = 30 * time.Second
+
+// inlineStartParameter параметр /start кнопки для участников, которых бот не видел в группах
+const inlineStartParameter = "inline"
+
+// InlineHandler отвечает на инлайн-запросы @бот в любом чате: статистика участника,
+// таблица лидеров и сегодняшний выбор в группе, где участник писал последним.
+// В инлайн-запросе нет чата, поэтому группа берется из репозитория пользователей.
+type InlineHandler struct {
+	userRepo           repository.UserRepository
+	personOfTheDayRepo repository.PersonOfTheDayRepository
+	chatSettingsRepo   repository.ChatSettingsRepository
+	messageService     *templates.MessageService
+	cache              *inlineCache
+	clock              clock.Clock
+	logger             *slog.Logger
+}
+
+// NewInlineHandler создает обработчик инлайн-запросов
+func NewInlineHandler(
+	userRepo repository.UserRepository,
+	personOfTheDayRepo repository.PersonOfTheDayRepository,
+	chatSettingsRepo repository.ChatSettingsRepository,
+	messageService *templates.MessageService,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *InlineHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	clk = clock.OrSystem(clk)
+
+	return &InlineHandler{
+		userRepo:           userRepo,
+		personOfTheDayRepo: personOfTheDayRepo,
+		chatSettingsRepo:   chatSettingsRepo,
+		messageService:     messageService,
+		cache:              newInlineCache(InlineCacheTTL, clk),
+		clock:              clk,
+		logger:             logger.With("component", "inline"),
+	}
+}
+
+// RegisterHandlers регистрирует обработчик инлайн-запросов
+func (h *InlineHandler) RegisterHandlers(bot *telebot.Bot) {
+	bot.Handle(telebot.OnQuery, h.HandleQuery)
+}
+
+// HandleQuery отвечает на инлайн-запрос. Текст запроса отбирает результаты
+// по заголовку и описанию, пустой запрос показывает все результаты.
+func (h *InlineHandler) HandleQuery(c telebot.Context) error {
+	query := c.Query()
+	if query == nil {
+		return nil
+	}
+	log := UpdateLogger(c, h.logger)
+
+	key := inlineCacheKey{userID: query.Sender.ID, language: query.Sender.LanguageCode}
+	articles, ok := h.cache.get(key)
+	if !ok {
+		var err error
+		articles, err = h.articles(query.Sender)
+		if err != nil {
+			log.Error("Ошибка подготовки инлайн-результатов", "error", err)
+			return nil
+		}
+		h.cache.put(key, articles)
+	}
+
+	response := &telebot.QueryResponse{
+		Results:    filterInlineArticles(articles, query.Text).results(),
+		CacheTime:  int(InlineCacheTTL.Seconds()),
+		IsPersonal: true,
+	}
+	if len(articles) == 0 {
+		response.Button = &telebot.QueryResponseButton{
+			Text:  h.locale(query.Sender).InlineNoChat(),
+			Start: inlineStartParameter,
+		}
+	}
+
+	if err := c.Answer(response); err != nil {
+		log.Error("Ошибка ответа на инлайн-запрос", "error", err)
+		UpdateMetrics(c).SendError(err)
+	}
+	return nil
+}
+
+// locale возвращает сервис сообщений на языке участника
+func (h *InlineHandler) locale(sender *telebot.User) *templates.MessageService {
+	if locale, ok := templates.ParseLocale(sender.LanguageCode); ok {
+		return h.messageService.WithLocale(locale)
+	}
+	return h.messageService.WithLocale(h.messageService.DefaultLocale())
+}
+
+// articles строит результаты по группе, в которой участник писал последним.
+// Пустой список означает, что бот еще не видел участника в группах.
+func (h *InlineHandler) articles(sender *telebot.User) (inlineArticles, error) {
+	user, err := h.userRepo.GetByUserID(sender.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return inlineArticles{}, nil
+	}
+
+	messages := h.locale(sender)
+	settings, err := h.chatSettingsRepo.Get(user.ChatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat settings: %w", err)
+	}
+	if settings != nil {
+		messages = messages.WithTitle(settings.Title, settings.Emoji)
+	}
+
+	stats, err := h.personOfTheDayRepo.GetUserStats(user.ChatID, domain.DefaultNominationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+	today, err := h.personOfTheDayRepo.GetByDate(user.ChatID, domain.DefaultNominationID, h.clock.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's pick: %w", err)
+	}
+
+	count, rank := userRank(stats, user.ID)
+	myStats := messages.InlineMyStats(*user, count, rank, len(stats))
+
+	statsText := messages.StatsEmpty()
+	statsDescription := statsText
+	if len(stats) > 0 && stats[0].Count > 0 {
+		statsText = messages.BuildStatsMessage(stats)
+		statsDescription = messages.InlineStatsDescription(stats[0])
+	}
+
+	todayText := messages.InlineToday(today)
+
+	return inlineArticles{
+		{id: fmt.Sprintf("me:%d", user.ChatID), title: messages.InlineMyStatsTitle(), description: myStats, text: myStats},
+		{id: fmt.Sprintf("stats:%d", user.ChatID), title: messages.InlineStatsTitle(), description: statsDescription, text: statsText},
+		{id: fmt.Sprintf("today:%d", user.ChatID), title: messages.InlineTodayTitle(), description: todayText, text: todayText},
+	}, nil
+}
+
+// userRank возвращает победы участника и его место: участники с равным числом
+// побед делят место
+func userRank(stats []domain.UserStats, userID int64) (count, rank int) {
+	for _, stat := range stats {
+		if stat.User.ID == userID {
+			count = stat.Count
+		}
+	}
+
+	rank = 1
+	for _, stat := range stats {
+		if stat.Count > count {
+			rank++
+		}
+	}
+	return count, rank
+}
+
+// inlineArticle готовый текст инлайн-результата. В кеше хранятся тексты, а не
+// telebot.Result: telebot изменяет результаты при отправке ответа.
+type inlineArticle struct {
+	id          string
+	title       string
+	description string
+	text        string
+}
+
+// inlineArticles результаты одного участника
+type inlineArticles []inlineArticle
+
+// filterInlineArticles оставляет результаты, в заголовке или описании которых есть текст запроса
+func filterInlineArticles(articles inlineArticles, text string) inlineArticles {
+	text = strings.ToLower(strings.TrimSpace(text))
+	if text == "" {
+		return articles
+	}
+
+	filtered := inlineArticles{}
+	for _, article := range articles {
+		if strings.Contains(strings.ToLower(article.title), text) || strings.Contains(strings.ToLower(article.description), text) {
+			filtered = append(filtered, article)
+		}
+	}
+	return filtered
+}
+
+// results создает статьи telebot для ответа на инлайн-запрос
+func (articles inlineArticles) results() telebot.Results {
+	results := make(telebot.Results, 0, len(articles))
+	for _, article := range articles {
+		result := &telebot.ArticleResult{
+			Title:       article.title,
+			Description: article.description,
+		}
+		result.SetResultID(article.id)
+		result.SetContent(&telebot.InputTextMessageContent{Text: article.text})
+		results = append(results, result)
+	}
+	return results
+}
+
+// inlineCacheKey результаты зависят от участника и языка его клиента
+type inlineCacheKey struct {
+	userID   int64
+	language string
+}
+
+// inlineCacheEntry результаты участника со сроком хранения
+type inlineCacheEntry struct {
+	articles inlineArticles
+	expires  time.Time
+}
+
+// inlineCache хранит результаты участников, чтобы частые инлайн-запросы
+// при наборе текста не обращались к базе данных
+type inlineCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[inlineCacheKey]inlineCacheEntry
+	clock   clock.Clock
+}
+
+func newInlineCache(ttl time.Duration, clk clock.Clock) *inlineCache {
+	return &inlineCache{
+		ttl:     ttl,
+		entries: make(map[inlineCacheKey]inlineCacheEntry),
+		clock:   clk,
+	}
+}
+
+// get возвращает результаты участника, если срок их хранения не истек
+func (c *inlineCache) get(key inlineCacheKey) (inlineArticles, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.clock.Now().Before(entry.expires) {
+		return nil, false
+	}
+	return entry.articles, true
+}
+
+// put сохраняет результаты участника и удаляет устаревшие записи
+func (c *inlineCache) put(key inlineCacheKey, articles inlineArticles) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for existing, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, existing)
+		}
+	}
+	c.entries[key] = inlineCacheEntry{articles: articles, expires: now.Add(c.ttl)}
+}
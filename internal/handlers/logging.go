@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log/slog"
+
+	"github.com/pavel-one/day-of-the-bot/internal/logging"
+	"gopkg.in/telebot.v3"
+)
+
+// loggerKey ключ логгера обновления в контексте telebot
+const loggerKey = "logger"
+
+// UpdateLogger возвращает логгер текущего обновления с идентификатором корреляции.
+// Если middleware не установил логгер, возвращается fallback.
+func UpdateLogger(c telebot.Context, fallback *slog.Logger) *slog.Logger {
+	if logger, ok := c.Get(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return slog.Default()
+}
+
+// LoggerMiddleware сохраняет в контексте логгер с идентификатором корреляции
+// и сведениями об обновлении, чтобы все записи одного обновления можно было связать
+func LoggerMiddleware(logger *slog.Logger) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			attrs := []any{
+				"correlation_id", logging.NewCorrelationID(),
+				"update_id", c.Update().ID,
+			}
+			if c.Chat() != nil {
+				attrs = append(attrs, "chat_id", c.Chat().ID)
+			}
+			if c.Sender() != nil {
+				attrs = append(attrs, "user_id", c.Sender().ID)
+			}
+
+			updateLogger := logger.With(attrs...)
+			c.Set(loggerKey, updateLogger)
+
+			updateLogger.Debug("Получено обновление")
+			err := next(c)
+			if err != nil {
+				updateLogger.Error("Ошибка обработки обновления", "error", err)
+			}
+			return err
+		}
+	}
+}
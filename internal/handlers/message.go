@@ -1,8 +1,9 @@
 package handlers
 
 import (
-	"log"
+	"log/slog"
 
+	"github.com/pavel-one/day-of-the-bot/internal/core"
 	"github.com/pavel-one/day-of-the-bot/internal/domain"
 	"github.com/pavel-one/day-of-the-bot/internal/repository"
 	"github.com/pavel-one/day-of-the-bot/internal/templates"
@@ -11,113 +12,120 @@ import (
 
 // MessageHandler обрабатывает входящие сообщения
 type MessageHandler struct {
-	api                *telebot.Bot
-	userRepo           repository.UserRepository
-	personOfTheDayRepo repository.PersonOfTheDayRepository
-	messageService     *templates.MessageService
-	commandHandler     *CommandHandler
+	api              *telebot.Bot
+	chatSettingsRepo repository.ChatSettingsRepository
+	messageService   *templates.MessageService
+	core             *core.Service
+	commandHandler   *CommandHandler
+	logger           *slog.Logger
 }
 
 // NewMessageHandler создает новый обработчик сообщений
 func NewMessageHandler(
 	api *telebot.Bot,
-	userRepo repository.UserRepository,
-	personOfTheDayRepo repository.PersonOfTheDayRepository,
+	chatSettingsRepo repository.ChatSettingsRepository,
 	messageService *templates.MessageService,
+	coreService *core.Service,
 	commandHandler *CommandHandler,
+	logger *slog.Logger,
 ) *MessageHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	return &MessageHandler{
-		api:                api,
-		userRepo:           userRepo,
-		personOfTheDayRepo: personOfTheDayRepo,
-		messageService:     messageService,
-		commandHandler:     commandHandler,
+		api:              api,
+		chatSettingsRepo: chatSettingsRepo,
+		messageService:   messageService,
+		core:             coreService,
+		commandHandler:   commandHandler,
+		logger:           logger.With("component", "messages"),
 	}
 }
 
+// log возвращает логгер текущего обновления
+func (h *MessageHandler) log(c telebot.Context) *slog.Logger {
+	return UpdateLogger(c, h.logger)
+}
+
 // RegisterHandlers регистрирует обработчики сообщений
 func (h *MessageHandler) RegisterHandlers(bot *telebot.Bot) {
-	// Регистрируем middleware для обработки всех сообщений
-	bot.Use(h.handleMessage)
+	// Регистрируем middleware: сначала логгер обновления, затем обработку сообщений
+	bot.Use(LoggerMiddleware(h.logger), h.handleMessage)
 
 	// Регистрируем обработчики команд
 	h.commandHandler.RegisterHandlers(bot)
 
-	// Альтернативно: регистрируем обработчик для всех текстовых сообщений
+	// Регистрируем обработчик для всех текстовых сообщений, включая команды
 	bot.Handle(telebot.OnText, h.handleTextMessage)
+
+	// Файлы могут прийти с командой в подписи (например, /pidorimport)
+	bot.Handle(telebot.OnDocument, h.handleTextMessage)
 }
 
 // handleMessage обрабатывает все входящие сообщения (middleware)
 func (h *MessageHandler) handleMessage(next telebot.HandlerFunc) telebot.HandlerFunc {
 	return func(c telebot.Context) error {
-		// Логируем для отладки
-		log.Printf("Middleware: получено обновление, тип чата: %s, ID чата: %d", c.Chat().Type, c.Chat().ID)
-
 		// Пропускаем, если нет сообщения
 		if c.Message() == nil {
-			log.Printf("Middleware: нет сообщения, пропускаем")
+			h.log(c).Debug("Обновление без сообщения, пропускаем")
 			return next(c)
 		}
 
 		// Работаем только в группах
 		if c.Chat().Type != telebot.ChatGroup && c.Chat().Type != telebot.ChatSuperGroup {
-			log.Printf("Middleware: приватный чат, отправляем предупреждение")
-			SafeSendMessage(c, h.messageService.BotGroupOnly())
+			h.log(c).Debug("Сообщение не из группы, отправляем предупреждение", "chat_type", c.Chat().Type)
+			SafeSendMessage(c, ChatMessages(c, h.messageService, h.chatSettingsRepo).BotGroupOnly())
 			return nil // Не продолжаем обработку для приватных чатов
 		}
 
-		log.Printf("Middleware: групповой чат, обрабатываем пользователя")
-
 		// Добавляем пользователя в базу данных
 		if c.Sender() != nil {
-			user := domain.User{
-				ID:        c.Sender().ID,
-				Username:  c.Sender().Username,
-				FirstName: c.Sender().FirstName,
-				LastName:  c.Sender().LastName,
-				ChatID:    c.Chat().ID,
-			}
-
-			if err := h.userRepo.Add(user); err != nil {
-				log.Printf("Ошибка добавления пользователя: %v", err)
+			if err := h.core.TrackUser(senderUser(c)); err != nil {
+				h.log(c).Error("Ошибка добавления пользователя", "error", err)
 			} else {
-				log.Printf("Middleware: пользователь %s добавлен/обновлен", user.FirstName)
+				h.log(c).Debug("Пользователь добавлен или обновлен")
 			}
 		}
 
 		// Продолжаем выполнение следующего обработчика
-		log.Printf("Middleware: передаем управление следующему обработчику")
 		return next(c)
 	}
 }
 
 // handleTextMessage обрабатывает текстовые сообщения (альтернативный способ)
 func (h *MessageHandler) handleTextMessage(c telebot.Context) error {
-	log.Printf("TextHandler: получено текстовое сообщение в чате %d от пользователя %d", c.Chat().ID, c.Sender().ID)
-
 	// Работаем только в группах
 	if c.Chat().Type != telebot.ChatGroup && c.Chat().Type != telebot.ChatSuperGroup {
-		log.Printf("TextHandler: приватный чат, отправляем предупреждение")
-		SafeSendMessage(c, h.messageService.BotGroupOnly())
+		SafeSendMessage(c, ChatMessages(c, h.messageService, h.chatSettingsRepo).BotGroupOnly())
 		return nil
 	}
 
 	// Добавляем пользователя в базу данных
 	if c.Sender() != nil {
-		user := domain.User{
-			ID:        c.Sender().ID,
-			Username:  c.Sender().Username,
-			FirstName: c.Sender().FirstName,
-			LastName:  c.Sender().LastName,
-			ChatID:    c.Chat().ID,
+		if err := h.core.TrackUser(senderUser(c)); err != nil {
+			h.log(c).Error("Ошибка добавления пользователя", "error", err)
 		}
+	}
 
-		if err := h.userRepo.Add(user); err != nil {
-			log.Printf("TextHandler: Ошибка добавления пользователя: %v", err)
-		} else {
-			log.Printf("TextHandler: пользователь %s добавлен/обновлен", user.FirstName)
-		}
+	// Команды не регистрируются в telebot и приходят как текст
+	handled, err := h.commandHandler.Route(c)
+	if err != nil {
+		h.log(c).Error("Ошибка обработки команды", "error", err)
+	} else if handled {
+		h.log(c).Debug("Команда обработана")
 	}
 
 	return nil
 }
+
+// senderUser переводит отправителя сообщения в участника чата
+func senderUser(c telebot.Context) domain.User {
+	return domain.User{
+		ID:        c.Sender().ID,
+		Username:  c.Sender().Username,
+		FirstName: c.Sender().FirstName,
+		LastName:  c.Sender().LastName,
+		ChatID:    c.Chat().ID,
+	}
+}
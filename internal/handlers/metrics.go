@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"gopkg.in/telebot.v3"
+)
+
+// metricsKey ключ метрик в контексте telebot
+const metricsKey = "metrics"
+
+// Metrics учитывает события обработки обновлений
+type Metrics interface {
+	Command(command string)
+	Draw(chatID int64)
+	SendError(err error)
+}
+
+// noopMetrics используется, когда метрики выключены
+type noopMetrics struct{}
+
+func (noopMetrics) Command(string)  {}
+func (noopMetrics) Draw(int64)      {}
+func (noopMetrics) SendError(error) {}
+
+// UpdateMetrics возвращает метрики из контекста обновления или заглушку,
+// если middleware метрик не установлен
+func UpdateMetrics(c telebot.Context) Metrics {
+	if metrics, ok := c.Get(metricsKey).(Metrics); ok {
+		return metrics
+	}
+	return noopMetrics{}
+}
+
+// MetricsMiddleware сохраняет метрики в контексте, чтобы обработчики и функции
+// отправки сообщений могли учитывать события
+func MetricsMiddleware(metrics Metrics) telebot.MiddlewareFunc {
+	return func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			c.Set(metricsKey, metrics)
+			return next(c)
+		}
+	}
+}
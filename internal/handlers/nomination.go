@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/core"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"gopkg.in/telebot.v3"
+)
+
+// maxNominations ограничивает количество дополнительных номинаций в чате
+const maxNominations = 10
+
+// nominationCommandRx описывает допустимую команду номинации
+var nominationCommandRx = regexp.MustCompile(`^[a-z][a-z0-9_]{0,23}$`)
+
+// reservedCommands содержит команды, которые нельзя занять номинацией
+var reservedCommands = map[string]bool{
+	"start": true,
+	"help":  true,
+}
+
+// handleNominations показывает и изменяет номинации чата
+func (h *CommandHandler) handleNominations(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidornom")
+	messages := h.messages(c)
+
+	args := strings.Fields(c.Message().Payload)
+	if len(args) == 0 {
+		nominations, err := h.nominationRepo.GetByChatID(c.Chat().ID)
+		if err != nil {
+			h.log(c).Error("Ошибка при получении номинаций", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominations))
+			return nil
+		}
+
+		SafeSendMessage(c, messages.BuildNominationsMessage(nominations))
+		return nil
+	}
+
+	switch args[0] {
+	case "add":
+		return h.addNomination(c, messages, args[1:])
+	case "remove":
+		return h.removeNomination(c, messages, args[1:])
+	default:
+		SafeSendMessage(c, messages.NominationUsage())
+		return nil
+	}
+}
+
+// addNomination добавляет номинацию: add команда стратегия [эмодзи] название
+func (h *CommandHandler) addNomination(c telebot.Context, messages *templates.MessageService, args []string) error {
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	if len(args) < 3 {
+		SafeSendMessage(c, messages.NominationUsage())
+		return nil
+	}
+
+	command := strings.ToLower(args[0])
+	strategy := domain.SelectionStrategy(strings.ToLower(args[1]))
+	title, emoji := core.ParseTitle(strings.Join(args[2:], " "))
+
+	if !isValidNominationCommand(command) || !strategy.IsValid() || !core.ValidTitle(title) {
+		SafeSendMessage(c, messages.NominationUsage())
+		return nil
+	}
+
+	nominations, err := h.nominationRepo.GetByChatID(c.Chat().ID)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении номинаций", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominations))
+		return nil
+	}
+
+	if len(nominations) >= maxNominations {
+		SafeSendMessage(c, messages.NominationLimit(maxNominations))
+		return nil
+	}
+
+	// Команды статистики и информации тоже не должны совпадать с командами других номинаций
+	if taken := nominationConflict(nominations, command); taken != "" {
+		SafeSendMessage(c, messages.NominationExists(taken))
+		return nil
+	}
+
+	// Алиасы проверяются раньше номинаций и перехватили бы любую из команд номинации
+	for _, candidate := range nominationCommands(command) {
+		alias, err := h.aliasRepo.Get(c.Chat().ID, candidate)
+		if err != nil {
+			h.log(c).Error("Ошибка при получении алиаса", "error", err)
+			SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAlias))
+			return nil
+		}
+		if alias != nil || h.router.Has(candidate) {
+			SafeSendMessage(c, messages.NominationExists(candidate))
+			return nil
+		}
+	}
+
+	nomination := domain.Nomination{
+		ChatID:   c.Chat().ID,
+		Command:  command,
+		Title:    title,
+		Emoji:    emoji,
+		Strategy: strategy,
+	}
+	if err := h.nominationRepo.Create(&nomination); err != nil {
+		h.log(c).Error("Ошибка при создании номинации", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominationCreate))
+		return nil
+	}
+
+	SafeSendMessage(c, messages.NominationAdded(nomination))
+	return nil
+}
+
+// removeNomination удаляет номинацию вместе с ее историей и алиасами: remove команда
+func (h *CommandHandler) removeNomination(c telebot.Context, messages *templates.MessageService, args []string) error {
+	if !IsChatAdmin(c) {
+		SafeSendMessage(c, messages.AdminOnly())
+		return nil
+	}
+
+	if len(args) != 1 {
+		SafeSendMessage(c, messages.NominationUsage())
+		return nil
+	}
+
+	command := normalizeCommand(args[0])
+	nomination, err := h.nominationRepo.GetByCommand(c.Chat().ID, command)
+	if err != nil {
+		h.log(c).Error("Ошибка при получении номинации", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNomination))
+		return nil
+	}
+
+	if nomination == nil {
+		SafeSendMessage(c, messages.NominationNotFound(command))
+		return nil
+	}
+
+	if err := h.nominationRepo.Delete(c.Chat().ID, command); err != nil {
+		h.log(c).Error("Ошибка при удалении номинации", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorNominationDelete))
+		return nil
+	}
+
+	if err := h.removeNominationAliases(c.Chat().ID, command); err != nil {
+		h.log(c).Error("Ошибка при удалении алиасов номинации", "error", err)
+		SafeSendMessage(c, messages.ErrorOccurred(templates.ErrorAliasDelete))
+		return nil
+	}
+
+	SafeSendMessage(c, messages.NominationRemoved(command))
+	return nil
+}
+
+// removeNominationAliases удаляет алиасы команд удаленной номинации, иначе они
+// перестали бы работать или перешли бы к новой номинации с той же командой
+func (h *CommandHandler) removeNominationAliases(chatID int64, command string) error {
+	aliases, err := h.aliasRepo.GetByChatID(chatID)
+	if err != nil {
+		return err
+	}
+
+	commands := nominationCommands(command)
+	for _, alias := range aliases {
+		if !slices.Contains(commands, alias.Command) {
+			continue
+		}
+		if err := h.aliasRepo.Delete(chatID, alias.Alias); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Действия с номинацией, доступные через ее команды
+const (
+	nominationDraw  = "draw"
+	nominationStats = "stats"
+	nominationInfo  = "info"
+)
+
+// handleNominationCommand обрабатывает команды номинаций чата
+// (/команда, /командаstats, /командаinfo). Возвращает false, если команда не относится к номинациям.
+func (h *CommandHandler) handleNominationCommand(c telebot.Context, command string) (bool, error) {
+	nomination, action, err := h.resolveNominationCommand(c.Chat().ID, command)
+	if err != nil || nomination == nil {
+		return false, err
+	}
+
+	h.log(c).Info("Команда вызвана", "command", command)
+
+	switch action {
+	case nominationStats:
+		return true, h.stats(c, *nomination)
+	case nominationInfo:
+		return true, h.info(c, *nomination)
+	default:
+		return true, h.draw(c, *nomination)
+	}
+}
+
+// resolveNominationCommand ищет номинацию по команде и возвращает действие с ней.
+// Сначала проверяется точное совпадение, затем команды статистики и информации.
+func (h *CommandHandler) resolveNominationCommand(chatID int64, command string) (*domain.Nomination, string, error) {
+	nominations, err := h.nominationRepo.GetByChatID(chatID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	for i := range nominations {
+		if nominations[i].Command == command {
+			return &nominations[i], nominationDraw, nil
+		}
+	}
+	for i := range nominations {
+		switch command {
+		case nominations[i].Command + nominationStats:
+			return &nominations[i], nominationStats, nil
+		case nominations[i].Command + nominationInfo:
+			return &nominations[i], nominationInfo, nil
+		}
+	}
+
+	return nil, "", nil
+}
+
+// nominationCommands возвращает команды номинации: выбор, статистику и информацию
+func nominationCommands(command string) []string {
+	return []string{command, command + nominationStats, command + nominationInfo}
+}
+
+// nominationConflict возвращает команду новой номинации, которую уже занимает
+// одна из существующих номинаций, или пустую строку
+func nominationConflict(nominations []domain.Nomination, command string) string {
+	for _, nomination := range nominations {
+		taken := nominationCommands(nomination.Command)
+		for _, candidate := range nominationCommands(command) {
+			if slices.Contains(taken, candidate) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// isValidNominationCommand проверяет, что команду можно занять номинацией
+func isValidNominationCommand(command string) bool {
+	return nominationCommandRx.MatchString(command) &&
+		!reservedCommands[command] &&
+		!strings.HasPrefix(command, "pidor")
+}
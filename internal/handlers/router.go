@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"gopkg.in/telebot.v3"
+)
+
+// commandRx разбирает команду вида /command@BotName payload
+var commandRx = regexp.MustCompile(`^/(\w+)(?:@(\w+))?(?:\s|$)`)
+
+// ParseCommand возвращает имя команды (в нижнем регистре, без "/") и имя бота из суффикса @BotName
+func ParseCommand(text string) (command, botName string, ok bool) {
+	match := commandRx.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return "", "", false
+	}
+	return strings.ToLower(match[1]), match[2], true
+}
+
+// Метки метрики команд, не входящих в реестр встроенных
+const (
+	NominationCommandMetric = "nomination"
+	UnknownCommandMetric    = "unknown"
+)
+
+// FallbackFunc обрабатывает команду, не найденную среди встроенных и алиасов.
+// Возвращает false, если команда неизвестна.
+type FallbackFunc func(c telebot.Context, command string) (bool, error)
+
+// CommandRouter направляет команды обработчикам с учетом алиасов чатов
+type CommandRouter struct {
+	botUsername string
+	aliasRepo   repository.CommandAliasRepository
+	commands    map[string]telebot.HandlerFunc
+	fallback    FallbackFunc
+	unknown     telebot.HandlerFunc
+	logger      *slog.Logger
+}
+
+// NewCommandRouter создает новый маршрутизатор команд
+func NewCommandRouter(botUsername string, aliasRepo repository.CommandAliasRepository, logger *slog.Logger) *CommandRouter {
+	return &CommandRouter{
+		botUsername: botUsername,
+		aliasRepo:   aliasRepo,
+		commands:    make(map[string]telebot.HandlerFunc),
+		logger:      logger,
+	}
+}
+
+// Add регистрирует встроенную команду (без "/")
+func (r *CommandRouter) Add(command string, handler telebot.HandlerFunc) {
+	r.commands[strings.ToLower(command)] = handler
+}
+
+// SetFallback задает обработчик команд, не найденных среди встроенных и алиасов
+func (r *CommandRouter) SetFallback(fallback FallbackFunc) {
+	r.fallback = fallback
+}
+
+// SetUnknown задает обработчик неизвестных команд
+func (r *CommandRouter) SetUnknown(handler telebot.HandlerFunc) {
+	r.unknown = handler
+}
+
+// Has проверяет, зарегистрирована ли встроенная команда
+func (r *CommandRouter) Has(command string) bool {
+	_, ok := r.commands[strings.ToLower(command)]
+	return ok
+}
+
+// Commands возвращает отсортированный список встроенных команд
+func (r *CommandRouter) Commands() []string {
+	commands := make([]string, 0, len(r.commands))
+	for command := range r.commands {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	return commands
+}
+
+// Route обрабатывает сообщение, если оно является командой.
+// Возвращает false для обычного текста, команд, адресованных другому боту,
+// и неизвестных команд без явного @BotName.
+func (r *CommandRouter) Route(c telebot.Context) (bool, error) {
+	command, botName, ok := ParseCommand(c.Text())
+	if !ok {
+		return false, nil
+	}
+
+	if botName != "" && !strings.EqualFold(botName, r.botUsername) {
+		return false, nil
+	}
+
+	// Встроенные команды нельзя переопределить алиасом
+	if handler, ok := r.commands[command]; ok {
+		UpdateMetrics(c).Command(command)
+		return true, handler(c)
+	}
+
+	if c.Chat() != nil {
+		alias, err := r.aliasRepo.Get(c.Chat().ID, command)
+		if err != nil {
+			UpdateLogger(c, r.logger).Error("Ошибка получения алиаса", "alias", command, "error", err)
+		} else if alias != nil {
+			UpdateLogger(c, r.logger).Debug("Алиас команды", "alias", command, "command", alias.Command)
+			command = alias.Command
+			if handler, ok := r.commands[command]; ok {
+				UpdateMetrics(c).Command(command)
+				return true, handler(c)
+			}
+		}
+	}
+
+	if r.fallback != nil {
+		handled, err := r.fallback(c, command)
+		if handled || err != nil {
+			// Команды номинаций учитываются вместе, чтобы число меток не зависело от чатов
+			UpdateMetrics(c).Command(NominationCommandMetric)
+			return true, err
+		}
+	}
+
+	// Неизвестная команда без @BotName может предназначаться другому боту в группе.
+	// Личные чаты сюда не доходят: MessageHandler отвечает в них BotGroupOnly.
+	if r.unknown != nil && botName != "" {
+		UpdateMetrics(c).Command(UnknownCommandMetric)
+		return true, r.unknown(c)
+	}
+
+	return false, nil
+}
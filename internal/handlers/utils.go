@@ -6,9 +6,52 @@ import (
 
 // SafeSendMessage безопасно отправляет сообщение, обрабатывая специфичные ошибки Telegram
 func SafeSendMessage(c telebot.Context, text string, opts ...interface{}) {
-	c.Send(text, &telebot.SendOptions{
+	err := c.Send(text, &telebot.SendOptions{
 		ReplyTo:               c.Message(),
 		ThreadID:              c.Message().ThreadID,
 		DisableWebPagePreview: true,
 	})
+	if err != nil {
+		UpdateLogger(c, nil).Error("Ошибка отправки сообщения", "error", err)
+		UpdateMetrics(c).SendError(err)
+	}
+}
+
+// SafeSendDocument отправляет файл ответом на сообщение, ошибка только логируется
+func SafeSendDocument(c telebot.Context, document *telebot.Document) {
+	err := c.Send(document, &telebot.SendOptions{
+		ReplyTo:  c.Message(),
+		ThreadID: c.Message().ThreadID,
+	})
+	if err != nil {
+		UpdateLogger(c, nil).Error("Ошибка отправки файла", "error", err)
+		UpdateMetrics(c).SendError(err)
+	}
+}
+
+// SafeSendPhoto отправляет картинку ответом на сообщение, ошибка только логируется
+func SafeSendPhoto(c telebot.Context, photo *telebot.Photo) {
+	err := c.Send(photo, &telebot.SendOptions{
+		ReplyTo:  c.Message(),
+		ThreadID: c.Message().ThreadID,
+	})
+	if err != nil {
+		UpdateLogger(c, nil).Error("Ошибка отправки картинки", "error", err)
+		UpdateMetrics(c).SendError(err)
+	}
+}
+
+// IsChatAdmin проверяет, является ли отправитель администратором или создателем чата
+func IsChatAdmin(c telebot.Context) bool {
+	if c.Chat() == nil || c.Sender() == nil {
+		return false
+	}
+
+	member, err := c.Bot().ChatMemberOf(c.Chat(), c.Sender())
+	if err != nil {
+		UpdateLogger(c, nil).Error("Ошибка получения прав пользователя", "error", err)
+		return false
+	}
+
+	return member.Role == telebot.Administrator || member.Role == telebot.Creator
 }
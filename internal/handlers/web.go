@@ -0,0 +1,27 @@
+package handlers
+
+import (
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// WebLinks выдает подписанные ссылки на веб-страницу статистики чата
+type WebLinks interface {
+	Link(chatID int64) (url string, expires time.Time)
+}
+
+// handleWebLink отправляет в чат ссылку на веб-страницу статистики: /pidorstats web
+func (h *CommandHandler) handleWebLink(c telebot.Context) error {
+	h.log(c).Info("Команда вызвана", "command", "pidorstats web")
+	messages := h.messages(c)
+
+	if h.webLinks == nil {
+		SafeSendMessage(c, messages.WebDisabled())
+		return nil
+	}
+
+	url, expires := h.webLinks.Link(c.Chat().ID)
+	SafeSendMessage(c, messages.WebLink(url, expires))
+	return nil
+}
@@ -0,0 +1,165 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+)
+
+// pingTimeout время на проверку доступности базы данных
+const pingTimeout = 2 * time.Second
+
+// Пути проверок состояния
+const (
+	LivePath  = "/healthz"
+	ReadyPath = "/readyz"
+)
+
+// Pinger проверяет доступность хранилища
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Checker отвечает на проверки живости и готовности бота.
+// Бот готов, если база данных доступна и последний успешный запрос getUpdates
+// был не раньше updatesThreshold назад.
+type Checker struct {
+	db               Pinger
+	updatesThreshold time.Duration
+	lastUpdates      atomic.Int64
+	clock            clock.Clock
+}
+
+// NewChecker создает проверку состояния. updatesThreshold 0 отключает проверку
+// getUpdates, например в режиме вебхука, где бот не запрашивает обновления сам.
+// Время getUpdates отсчитывается по clk; nil — системные часы.
+func NewChecker(db Pinger, updatesThreshold time.Duration, clk clock.Clock) *Checker {
+	return &Checker{
+		db:               db,
+		updatesThreshold: updatesThreshold,
+		clock:            clock.OrSystem(clk),
+	}
+}
+
+// Transport оборачивает HTTP транспорт клиента Telegram и отмечает успешные запросы getUpdates
+func (c *Checker) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err == nil && resp.StatusCode == http.StatusOK && strings.HasSuffix(req.URL.Path, "/getUpdates") {
+			c.MarkUpdates()
+		}
+		return resp, err
+	})
+}
+
+// MarkUpdates отмечает успешное получение обновлений
+func (c *Checker) MarkUpdates() {
+	c.lastUpdates.Store(c.clock.Now().UnixNano())
+}
+
+// Check проверяет готовность и возвращает результат каждой проверки;
+// ok false, если хотя бы одна проверка не пройдена
+func (c *Checker) Check(ctx context.Context) (results map[string]string, ok bool) {
+	results = make(map[string]string)
+	ok = true
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	if err := c.db.Ping(ctx); err != nil {
+		results["database"] = err.Error()
+		ok = false
+	} else {
+		results["database"] = "ok"
+	}
+
+	if c.updatesThreshold > 0 {
+		last := c.lastUpdates.Load()
+		switch {
+		case last == 0:
+			results["updates"] = "no successful getUpdates yet"
+			ok = false
+		case c.clock.Now().Sub(time.Unix(0, last)) > c.updatesThreshold:
+			results["updates"] = fmt.Sprintf("last successful getUpdates %s ago", c.clock.Now().Sub(time.Unix(0, last)).Round(time.Second))
+			ok = false
+		default:
+			results["updates"] = "ok"
+		}
+	}
+
+	return results, ok
+}
+
+// Mux регистрирует HTTP обработчики, например http.ServeMux или httpserver.Server
+type Mux interface {
+	Handle(pattern string, handler http.Handler)
+}
+
+// Register добавляет обработчики /healthz и /readyz
+func (c *Checker) Register(mux Mux) {
+	mux.Handle(LivePath, http.HandlerFunc(c.handleLive))
+	mux.Handle(ReadyPath, http.HandlerFunc(c.handleReady))
+}
+
+// handleLive отвечает, что процесс жив
+func (c *Checker) handleLive(rw http.ResponseWriter, r *http.Request) {
+	writeStatus(rw, http.StatusOK, "ok", nil)
+}
+
+// handleReady отвечает 200, если бот готов обрабатывать обновления, иначе 503
+func (c *Checker) handleReady(rw http.ResponseWriter, r *http.Request) {
+	results, ok := c.Check(r.Context())
+	if !ok {
+		writeStatus(rw, http.StatusServiceUnavailable, "fail", results)
+		return
+	}
+	writeStatus(rw, http.StatusOK, "ok", results)
+}
+
+// writeStatus записывает ответ проверки в JSON
+func writeStatus(rw http.ResponseWriter, code int, status string, checks map[string]string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("Cache-Control", "no-store")
+	rw.WriteHeader(code)
+	_ = json.NewEncoder(rw).Encode(struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks,omitempty"`
+	}{status, checks})
+}
+
+// roundTripperFunc позволяет использовать функцию как http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Probe запрашивает проверку состояния по url и возвращает ошибку, если ответ не 200.
+// Используется командой healthcheck в HEALTHCHECK контейнера.
+func Probe(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid health url: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("health check failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// Format формат файла истории выборов
+type Format string
+
+const (
+	// FormatCSV таблица с заголовком, открывается в табличных редакторах
+	FormatCSV Format = "csv"
+	// FormatJSON массив объектов Row
+	FormatJSON Format = "json"
+)
+
+// dateLayout формат даты выбора в файлах истории
+const dateLayout = "2006-01-02"
+
+// utf8BOM помечает CSV как UTF-8, чтобы Excel правильно показывал кириллицу
+const utf8BOM = "\ufeff"
+
+// columns заголовок CSV, порядок совпадает с Row.values
+var columns = []string{
+	"chat_id", "date", "nomination_id", "nomination",
+	"user_id", "username", "first_name", "last_name", "display_name",
+}
+
+// Row строка файла истории: один выбор в номинации на дату
+type Row struct {
+	ChatID       int64  `json:"chat_id"`
+	Date         string `json:"date"`
+	NominationID int64  `json:"nomination_id"`
+	Nomination   string `json:"nomination"`
+	UserID       int64  `json:"user_id"`
+	Username     string `json:"username"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	DisplayName  string `json:"display_name"`
+}
+
+// ParseFormat разбирает формат без учета регистра
+func ParseFormat(value string) (Format, bool) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case FormatCSV, FormatJSON:
+		return format, true
+	default:
+		return "", false
+	}
+}
+
+// MIME возвращает MIME тип файла
+func (f Format) MIME() string {
+	if f == FormatJSON {
+		return "application/json"
+	}
+	return "text/csv"
+}
+
+// FileName возвращает имя файла выгрузки чата; chatID 0 означает все чаты
+func (f Format) FileName(chatID int64, date time.Time) string {
+	scope := "all"
+	if chatID != 0 {
+		scope = strconv.FormatInt(chatID, 10)
+	}
+	return fmt.Sprintf("pidor-%s-%s.%s", scope, date.Format("20060102"), f)
+}
+
+// NewRow преобразует запись истории в строку файла
+func NewRow(record domain.PersonOfTheDayRecord) Row {
+	return Row{
+		ChatID:       record.ChatID,
+		Date:         record.Date.Format(dateLayout),
+		NominationID: record.NominationID,
+		Nomination:   record.Nomination,
+		UserID:       record.UserID,
+		Username:     record.User.Username,
+		FirstName:    record.User.FirstName,
+		LastName:     record.User.LastName,
+		DisplayName:  record.User.DisplayName(),
+	}
+}
+
+// values возвращает значения строки в порядке columns.
+// Текстовые значения задают участники, поэтому они экранируются от формул.
+func (r Row) values() []string {
+	return []string{
+		strconv.FormatInt(r.ChatID, 10),
+		r.Date,
+		strconv.FormatInt(r.NominationID, 10),
+		escapeCSVCell(r.Nomination),
+		strconv.FormatInt(r.UserID, 10),
+		escapeCSVCell(r.Username),
+		escapeCSVCell(r.FirstName),
+		escapeCSVCell(r.LastName),
+		escapeCSVCell(r.DisplayName),
+	}
+}
+
+// csvFormulaPrefixes символы, с которых табличные редакторы начинают формулу.
+// Апостроф тоже экранируется, чтобы импорт однозначно снимал экранирование.
+const csvFormulaPrefixes = "=+-@\t\r'"
+
+// escapeCSVCell добавляет апостроф перед значением, которое редактор прочитал бы как формулу
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell снимает экранирование escapeCSVCell
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// Write записывает историю выборов в выбранном формате
+func Write(w io.Writer, format Format, records []domain.PersonOfTheDayRecord) error {
+	rows := make([]Row, 0, len(records))
+	for _, record := range records {
+		rows = append(rows, NewRow(record))
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(rows); err != nil {
+			return fmt.Errorf("failed to write json: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// writeCSV записывает строки в CSV с заголовком
+func writeCSV(w io.Writer, rows []Row) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	for _, row := range rows {
+		if err := writer.Write(row.values()); err != nil {
+			return fmt.Errorf("failed to write csv: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write csv: %w", err)
+	}
+	return nil
+}
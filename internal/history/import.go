@@ -0,0 +1,279 @@
+package history
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// importDateLayouts форматы дат, которые встречаются в выгрузках других ботов
+var importDateLayouts = []string{dateLayout, "02.01.2006", time.RFC3339, "2006-01-02 15:04:05"}
+
+// columnAliases сопоставляет названия колонок CSV с полями ImportRow
+var columnAliases = map[string]string{
+	"date":       "date",
+	"day":        "date",
+	"user_id":    "user_id",
+	"id":         "user_id",
+	"username":   "username",
+	"user":       "username",
+	"nomination": "nomination",
+}
+
+// ImportRow строка импортируемого файла. Участник задается ID или username;
+// пустая номинация означает основную. Err заполнен, если строку не удалось разобрать.
+type ImportRow struct {
+	Line       int
+	Date       time.Time
+	UserID     int64
+	Username   string
+	Nomination string
+	Err        error
+}
+
+// FormatFromFileName определяет формат по расширению файла
+func FormatFromFileName(name string) (Format, bool) {
+	return ParseFormat(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+// Parse читает строки истории из CSV с заголовком или JSON массива объектов.
+// Ошибки отдельных строк сохраняются в ImportRow.Err, чтобы попасть в отчет;
+// ошибка возвращается, только если файл не удалось прочитать целиком.
+func Parse(r io.Reader, format Format) ([]ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return parseJSON(r)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+// parseCSV читает CSV; колонки определяются по заголовку, лишние колонки игнорируются
+func parseCSV(r io.Reader) ([]ImportRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv: %w", err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte(utf8BOM))))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		if field, ok := columnAliases[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, exists := index[field]; !exists {
+				index[field] = i
+			}
+		}
+	}
+	if _, ok := index["date"]; !ok {
+		return nil, errors.New("csv header has no date column")
+	}
+	_, hasID := index["user_id"]
+	_, hasUsername := index["username"]
+	if !hasID && !hasUsername {
+		return nil, errors.New("csv header has no user_id or username column")
+	}
+
+	var rows []ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(field string) string {
+			if i, ok := index[field]; ok && i < len(record) {
+				return unescapeCSVCell(strings.TrimSpace(record[i]))
+			}
+			return ""
+		}
+
+		rows = append(rows, newImportRow(line, value("date"), value("user_id"), value("username"), value("nomination")))
+	}
+
+	return rows, nil
+}
+
+// jsonImportRow объект JSON файла; user_id может быть числом или строкой
+type jsonImportRow struct {
+	Date       string          `json:"date"`
+	UserID     json.RawMessage `json:"user_id"`
+	Username   string          `json:"username"`
+	Nomination string          `json:"nomination"`
+}
+
+// parseJSON читает JSON массив; номер строки — порядковый номер объекта
+func parseJSON(r io.Reader) ([]ImportRow, error) {
+	var items []jsonImportRow
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to read json: %w", err)
+	}
+
+	rows := make([]ImportRow, 0, len(items))
+	for i, item := range items {
+		userID := strings.Trim(strings.TrimSpace(string(item.UserID)), `"`)
+		if userID == "null" {
+			userID = ""
+		}
+		rows = append(rows, newImportRow(i+1, item.Date, userID, item.Username, item.Nomination))
+	}
+
+	return rows, nil
+}
+
+// newImportRow разбирает значения строки
+func newImportRow(line int, date, userID, username, nomination string) ImportRow {
+	row := ImportRow{
+		Line:       line,
+		Username:   strings.TrimPrefix(strings.TrimSpace(username), "@"),
+		Nomination: strings.ToLower(strings.TrimPrefix(strings.TrimSpace(nomination), "/")),
+	}
+
+	parsed, err := parseImportDate(strings.TrimSpace(date))
+	if err != nil {
+		row.Err = err
+		return row
+	}
+	row.Date = parsed
+
+	if userID != "" {
+		id, err := strconv.ParseInt(userID, 10, 64)
+		if err != nil {
+			row.Err = fmt.Errorf("invalid user_id %q", userID)
+			return row
+		}
+		row.UserID = id
+	}
+
+	if row.UserID == 0 && row.Username == "" {
+		row.Err = errors.New("no user_id or username")
+	}
+
+	return row
+}
+
+// parseImportDate разбирает дату в одном из поддерживаемых форматов
+func parseImportDate(value string) (time.Time, error) {
+	for _, layout := range importDateLayouts {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
+
+// Importer добавляет историю выборов из файлов других ботов
+type Importer struct {
+	users       repository.UserRepository
+	persons     repository.PersonOfTheDayRepository
+	nominations repository.NominationRepository
+}
+
+// NewImporter создает импортер истории
+func NewImporter(
+	users repository.UserRepository,
+	persons repository.PersonOfTheDayRepository,
+	nominations repository.NominationRepository,
+) *Importer {
+	return &Importer{users: users, persons: persons, nominations: nominations}
+}
+
+// Import сопоставляет строки с участниками и номинациями чата и добавляет выборы.
+// Участник ищется по ID, затем по username без учета регистра. Даты, на которые
+// в номинации уже есть выбор, не перезаписываются и считаются пропущенными.
+func (i *Importer) Import(chatID int64, rows []ImportRow) (*domain.ImportResult, error) {
+	users, err := i.users.GetByChatID(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat users: %w", err)
+	}
+	byID := make(map[int64]domain.User, len(users))
+	byUsername := make(map[string]domain.User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+		if user.Username != "" {
+			byUsername[strings.ToLower(user.Username)] = user
+		}
+	}
+
+	nominations, err := i.nominations.GetByChatID(chatID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chat nominations: %w", err)
+	}
+	nominationIDs := map[string]int64{
+		"":                              domain.DefaultNominationID,
+		domain.DefaultNominationCommand: domain.DefaultNominationID,
+	}
+	for _, nomination := range nominations {
+		nominationIDs[nomination.Command] = nomination.ID
+	}
+
+	result := &domain.ImportResult{Total: len(rows)}
+	skip := func(row ImportRow, value string, reason domain.ImportReason) {
+		result.Unmatched = append(result.Unmatched, domain.ImportUnmatched{Line: row.Line, Value: value, Reason: reason})
+	}
+
+	for _, row := range rows {
+		if row.Err != nil {
+			skip(row, row.Err.Error(), domain.ReasonInvalidRow)
+			continue
+		}
+
+		user, ok := byID[row.UserID]
+		if !ok {
+			user, ok = byUsername[strings.ToLower(row.Username)]
+		}
+		if !ok {
+			skip(row, row.userLabel(), domain.ReasonUserNotFound)
+			continue
+		}
+
+		nominationID, ok := nominationIDs[row.Nomination]
+		if !ok {
+			skip(row, row.Nomination, domain.ReasonNominationNotFound)
+			continue
+		}
+
+		inserted, err := i.persons.Insert(user.ID, chatID, nominationID, row.Date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import line %d: %w", row.Line, err)
+		}
+		if inserted {
+			result.Imported++
+		} else {
+			result.Skipped++
+		}
+	}
+
+	return result, nil
+}
+
+// userLabel возвращает участника строки для отчета
+func (r ImportRow) userLabel() string {
+	if r.Username != "" {
+		return "@" + r.Username
+	}
+	return strconv.FormatInt(r.UserID, 10)
+}
@@ -0,0 +1,99 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+)
+
+// shutdownTimeout время на завершение запросов при остановке сервера
+const shutdownTimeout = 5 * time.Second
+
+// Server вспомогательный HTTP сервер бота: метрики, проверки состояния
+type Server struct {
+	name     string
+	listen   string
+	mux      *http.ServeMux
+	logger   *slog.Logger
+	listener net.Listener
+}
+
+// New создает HTTP сервер; name используется в логах
+func New(name, listen string, logger *slog.Logger) *Server {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &Server{
+		name:   name,
+		listen: listen,
+		mux:    http.NewServeMux(),
+		logger: logger.With("component", name),
+	}
+}
+
+// Handle регистрирует обработчик по шаблону http.ServeMux
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Listen открывает порт. Вызывается до запуска бота, чтобы ошибка занятого порта
+// обнаруживалась при старте.
+func (s *Server) Listen() error {
+	listener, err := net.Listen("tcp", s.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.listen, err)
+	}
+	s.listener = listener
+	return nil
+}
+
+// Addr возвращает фактический адрес сервера после Listen
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Serve обрабатывает запросы до отмены ctx. Ошибки только логируются,
+// чтобы сбой вспомогательного сервера не останавливал бота.
+func (s *Server) Serve(ctx context.Context) {
+	if s.listener == nil {
+		if err := s.Listen(); err != nil {
+			s.logger.Error("Ошибка запуска HTTP сервера", "error", err)
+			return
+		}
+	}
+
+	server := &http.Server{
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(s.listener)
+	}()
+
+	s.logger.Info("HTTP сервер запущен", "listen", s.Addr().String())
+
+	select {
+	case <-ctx.Done():
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error("Ошибка HTTP сервера", "error", err)
+		}
+		return
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		s.logger.Error("Ошибка остановки HTTP сервера", "error", err)
+	}
+}
@@ -0,0 +1,65 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Форматы вывода логов
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// ParseLevel преобразует название уровня (debug, info, warn, error) в slog.Level
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info", "":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// New создает структурированный логгер с указанными уровнем и форматом
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	parsed, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: parsed}
+
+	switch strings.ToLower(format) {
+	case FormatText, "":
+		return slog.New(slog.NewTextHandler(w, options)), nil
+	case FormatJSON:
+		return slog.New(slog.NewJSONHandler(w, options)), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q", format)
+	}
+}
+
+// Discard возвращает логгер, отбрасывающий все записи
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// NewCorrelationID возвращает случайный идентификатор для связывания записей одного обновления
+func NewCorrelationID() string {
+	var buf [8]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf[:])
+}
@@ -0,0 +1,105 @@
+package metrics
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/telebot.v3"
+)
+
+// Причины ошибок отправки сообщений
+const (
+	ReasonFlood         = "flood"
+	ReasonBlocked       = "blocked"
+	ReasonKicked        = "kicked"
+	ReasonForbidden     = "forbidden"
+	ReasonNoRights      = "no_rights"
+	ReasonChatNotFound  = "chat_not_found"
+	ReasonReplyNotFound = "reply_not_found"
+	ReasonMigrated      = "migrated"
+	ReasonBadRequest    = "bad_request"
+	ReasonServer        = "server"
+	ReasonNetwork       = "network"
+	ReasonOther         = "other"
+)
+
+// errorCodeRx находит код ошибки Telegram, которую telebot не распознал: "telegram: ... (400)"
+var errorCodeRx = regexp.MustCompile(`^telegram: .*\((\d{3})\)$`)
+
+// SendErrorReason классифицирует ошибку отправки сообщения в Telegram.
+// Число причин ограничено, чтобы метрика не разрасталась.
+func SendErrorReason(err error) string {
+	var flood telebot.FloodError
+	if errors.As(err, &flood) {
+		return ReasonFlood
+	}
+
+	var group telebot.GroupError
+	if errors.As(err, &group) {
+		return ReasonMigrated
+	}
+
+	switch {
+	case errors.Is(err, telebot.ErrBlockedByUser),
+		errors.Is(err, telebot.ErrNotStartedByUser),
+		errors.Is(err, telebot.ErrUserIsDeactivated):
+		return ReasonBlocked
+	case errors.Is(err, telebot.ErrKickedFromGroup),
+		errors.Is(err, telebot.ErrKickedFromSuperGroup),
+		errors.Is(err, telebot.ErrKickedFromChannel),
+		errors.Is(err, telebot.ErrNotChannelMember):
+		return ReasonKicked
+	case errors.Is(err, telebot.ErrNoRightsToSend),
+		errors.Is(err, telebot.ErrNoRightsToSendPhoto),
+		errors.Is(err, telebot.ErrNoRightsToSendStickers),
+		errors.Is(err, telebot.ErrNoRightsToSendGifs):
+		return ReasonNoRights
+	case errors.Is(err, telebot.ErrChatNotFound):
+		return ReasonChatNotFound
+	case errors.Is(err, telebot.ErrNotFoundToReply):
+		return ReasonReplyNotFound
+	case errors.Is(err, telebot.ErrGroupMigrated):
+		return ReasonMigrated
+	}
+
+	var apiErr *telebot.Error
+	if errors.As(err, &apiErr) {
+		return reasonByCode(apiErr.Code)
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ReasonNetwork
+	}
+
+	if match := errorCodeRx.FindStringSubmatch(err.Error()); match != nil {
+		code, _ := strconv.Atoi(match[1])
+		return reasonByCode(code)
+	}
+
+	// Ошибки HTTP клиента telebot оборачивает с префиксом "telebot:"
+	if strings.HasPrefix(err.Error(), "telebot: ") {
+		return ReasonNetwork
+	}
+
+	return ReasonOther
+}
+
+// reasonByCode классифицирует ошибку Telegram по HTTP коду
+func reasonByCode(code int) string {
+	switch {
+	case code == 429:
+		return ReasonFlood
+	case code == 403:
+		return ReasonForbidden
+	case code >= 500:
+		return ReasonServer
+	case code >= 400:
+		return ReasonBadRequest
+	default:
+		return ReasonOther
+	}
+}
@@ -0,0 +1,197 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gopkg.in/telebot.v3"
+)
+
+// namespace префикс имен всех метрик бота
+const namespace = "dayofthebot"
+
+// ActiveWindow период, в течение которого чат считается активным после последнего обновления
+const ActiveWindow = 24 * time.Hour
+
+// Metrics собирает метрики бота в собственном реестре Prometheus
+type Metrics struct {
+	registry      *prometheus.Registry
+	updates       *prometheus.CounterVec
+	commands      *prometheus.CounterVec
+	draws         *prometheus.CounterVec
+	sendErrors    *prometheus.CounterVec
+	queryDuration *prometheus.HistogramVec
+
+	mu       sync.Mutex
+	lastSeen map[int64]time.Time
+	clock    clock.Clock
+}
+
+// New создает метрики и регистрирует их вместе со стандартными метриками процесса и Go.
+// Активность чатов отсчитывается по clk; nil — системные часы.
+func New(clk clock.Clock) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		updates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "updates_total",
+			Help:      "Обновления Telegram по типам.",
+		}, []string{"type"}),
+		commands: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "commands_total",
+			Help:      "Обработанные команды.",
+		}, []string{"command"}),
+		draws: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "draws_total",
+			Help:      "Выборы человека дня по чатам.",
+		}, []string{"chat_id"}),
+		sendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "send_errors_total",
+			Help:      "Ошибки отправки сообщений в Telegram по причинам.",
+		}, []string{"reason"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "repository_query_duration_seconds",
+			Help:      "Время выполнения запросов репозиториев.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"repository", "method"}),
+		lastSeen: make(map[int64]time.Time),
+		clock:    clock.OrSystem(clk),
+	}
+
+	activeChats := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "active_chats",
+		Help:      "Чаты, из которых приходили обновления за последние 24 часа.",
+	}, func() float64 {
+		return float64(m.ActiveChats())
+	})
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.updates,
+		m.commands,
+		m.draws,
+		m.sendErrors,
+		m.queryDuration,
+		activeChats,
+	)
+
+	return m
+}
+
+// Registry возвращает реестр метрик
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler возвращает HTTP обработчик метрик в формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveUpdate учитывает обновление и активность чата.
+// Сигнатура совпадает с фильтром telebot.MiddlewarePoller, обновление всегда пропускается.
+func (m *Metrics) ObserveUpdate(update *telebot.Update) bool {
+	m.updates.WithLabelValues(UpdateType(update)).Inc()
+
+	if chat := updateChat(update); chat != nil {
+		m.mu.Lock()
+		m.lastSeen[chat.ID] = m.clock.Now()
+		m.mu.Unlock()
+	}
+
+	return true
+}
+
+// Command учитывает обработанную команду
+func (m *Metrics) Command(command string) {
+	m.commands.WithLabelValues(command).Inc()
+}
+
+// Draw учитывает выбор человека дня в чате
+func (m *Metrics) Draw(chatID int64) {
+	m.draws.WithLabelValues(strconv.FormatInt(chatID, 10)).Inc()
+}
+
+// SendError учитывает ошибку отправки сообщения с классифицированной причиной
+func (m *Metrics) SendError(err error) {
+	m.sendErrors.WithLabelValues(SendErrorReason(err)).Inc()
+}
+
+// ObserveQuery учитывает время выполнения запроса репозитория, начатого в start
+func (m *Metrics) ObserveQuery(repository, method string, start time.Time) {
+	m.queryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
+
+// ActiveChats возвращает число чатов, активных в течение ActiveWindow.
+// Давно неактивные чаты удаляются, чтобы список не рос бесконечно.
+func (m *Metrics) ActiveChats() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	threshold := m.clock.Now().Add(-ActiveWindow)
+	for chatID, seen := range m.lastSeen {
+		if seen.Before(threshold) {
+			delete(m.lastSeen, chatID)
+		}
+	}
+
+	return len(m.lastSeen)
+}
+
+// UpdateType возвращает тип обновления для метрик
+func UpdateType(update *telebot.Update) string {
+	switch {
+	case update.Message != nil:
+		return "message"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	case update.ChannelPost != nil:
+		return "channel_post"
+	case update.EditedChannelPost != nil:
+		return "edited_channel_post"
+	case update.Callback != nil:
+		return "callback_query"
+	case update.Query != nil:
+		return "inline_query"
+	case update.InlineResult != nil:
+		return "chosen_inline_result"
+	case update.MyChatMember != nil:
+		return "my_chat_member"
+	case update.ChatMember != nil:
+		return "chat_member"
+	case update.ChatJoinRequest != nil:
+		return "chat_join_request"
+	default:
+		return "other"
+	}
+}
+
+// updateChat возвращает чат, из которого пришло обновление
+func updateChat(update *telebot.Update) *telebot.Chat {
+	switch {
+	case update.Message != nil:
+		return update.Message.Chat
+	case update.EditedMessage != nil:
+		return update.EditedMessage.Chat
+	case update.Callback != nil && update.Callback.Message != nil:
+		return update.Callback.Message.Chat
+	case update.MyChatMember != nil:
+		return update.MyChatMember.Chat
+	case update.ChatMember != nil:
+		return update.ChatMember.Chat
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// observer замеряет время вызовов методов одного репозитория
+type observer struct {
+	metrics    *Metrics
+	repository string
+}
+
+// observe начинает замер вызова; возвращенную функцию нужно вызвать через defer
+func (o observer) observe(method string) func() {
+	start := time.Now()
+	return func() {
+		o.metrics.ObserveQuery(o.repository, method, start)
+	}
+}
+
+// userRepository замеряет время запросов репозитория пользователей
+type userRepository struct {
+	observer
+	next repository.UserRepository
+}
+
+// WrapUserRepository добавляет замер времени запросов к репозиторию пользователей
+func (m *Metrics) WrapUserRepository(next repository.UserRepository) repository.UserRepository {
+	return &userRepository{observer: observer{metrics: m, repository: "users"}, next: next}
+}
+
+func (r *userRepository) Add(user domain.User) error {
+	defer r.observe("Add")()
+	return r.next.Add(user)
+}
+
+func (r *userRepository) GetByChatID(chatID int64) ([]domain.User, error) {
+	defer r.observe("GetByChatID")()
+	return r.next.GetByChatID(chatID)
+}
+
+func (r *userRepository) GetByID(userID, chatID int64) (*domain.User, error) {
+	defer r.observe("GetByID")()
+	return r.next.GetByID(userID, chatID)
+}
+
+func (r *userRepository) GetByUserID(userID int64) (*domain.User, error) {
+	defer r.observe("GetByUserID")()
+	return r.next.GetByUserID(userID)
+}
+
+// personOfTheDayRepository замеряет время запросов репозитория выборов
+type personOfTheDayRepository struct {
+	observer
+	next repository.PersonOfTheDayRepository
+}
+
+// WrapPersonOfTheDayRepository добавляет замер времени запросов к репозиторию выборов
+func (m *Metrics) WrapPersonOfTheDayRepository(next repository.PersonOfTheDayRepository) repository.PersonOfTheDayRepository {
+	return &personOfTheDayRepository{observer: observer{metrics: m, repository: "person_of_the_day"}, next: next}
+}
+
+func (r *personOfTheDayRepository) Set(userID, chatID, nominationID int64, date time.Time) error {
+	defer r.observe("Set")()
+	return r.next.Set(userID, chatID, nominationID, date)
+}
+
+func (r *personOfTheDayRepository) Insert(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	defer r.observe("Insert")()
+	return r.next.Insert(userID, chatID, nominationID, date)
+}
+
+func (r *personOfTheDayRepository) ReplaceStale(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	defer r.observe("ReplaceStale")()
+	return r.next.ReplaceStale(userID, chatID, nominationID, date)
+}
+
+func (r *personOfTheDayRepository) GetByDate(chatID, nominationID int64, date time.Time) (*domain.User, error) {
+	defer r.observe("GetByDate")()
+	return r.next.GetByDate(chatID, nominationID, date)
+}
+
+func (r *personOfTheDayRepository) GetUserStats(chatID, nominationID int64) ([]domain.UserStats, error) {
+	defer r.observe("GetUserStats")()
+	return r.next.GetUserStats(chatID, nominationID)
+}
+
+func (r *personOfTheDayRepository) GetHistory(chatID int64) ([]domain.PersonOfTheDayRecord, error) {
+	defer r.observe("GetHistory")()
+	return r.next.GetHistory(chatID)
+}
+
+func (r *personOfTheDayRepository) GetAllHistory() ([]domain.PersonOfTheDayRecord, error) {
+	defer r.observe("GetAllHistory")()
+	return r.next.GetAllHistory()
+}
+
+// nominationRepository замеряет время запросов репозитория номинаций
+type nominationRepository struct {
+	observer
+	next repository.NominationRepository
+}
+
+// WrapNominationRepository добавляет замер времени запросов к репозиторию номинаций
+func (m *Metrics) WrapNominationRepository(next repository.NominationRepository) repository.NominationRepository {
+	return &nominationRepository{observer: observer{metrics: m, repository: "nominations"}, next: next}
+}
+
+func (r *nominationRepository) Create(nomination *domain.Nomination) error {
+	defer r.observe("Create")()
+	return r.next.Create(nomination)
+}
+
+func (r *nominationRepository) GetByChatID(chatID int64) ([]domain.Nomination, error) {
+	defer r.observe("GetByChatID")()
+	return r.next.GetByChatID(chatID)
+}
+
+func (r *nominationRepository) GetByCommand(chatID int64, command string) (*domain.Nomination, error) {
+	defer r.observe("GetByCommand")()
+	return r.next.GetByCommand(chatID, command)
+}
+
+func (r *nominationRepository) Delete(chatID int64, command string) error {
+	defer r.observe("Delete")()
+	return r.next.Delete(chatID, command)
+}
+
+// chatSettingsRepository замеряет время запросов репозитория настроек чатов
+type chatSettingsRepository struct {
+	observer
+	next repository.ChatSettingsRepository
+}
+
+// WrapChatSettingsRepository добавляет замер времени запросов к репозиторию настроек чатов
+func (m *Metrics) WrapChatSettingsRepository(next repository.ChatSettingsRepository) repository.ChatSettingsRepository {
+	return &chatSettingsRepository{observer: observer{metrics: m, repository: "chat_settings"}, next: next}
+}
+
+func (r *chatSettingsRepository) Get(chatID int64) (*domain.ChatSettings, error) {
+	defer r.observe("Get")()
+	return r.next.Get(chatID)
+}
+
+func (r *chatSettingsRepository) SetLanguage(chatID int64, language string) error {
+	defer r.observe("SetLanguage")()
+	return r.next.SetLanguage(chatID, language)
+}
+
+func (r *chatSettingsRepository) SetTitle(chatID int64, title, emoji string) error {
+	defer r.observe("SetTitle")()
+	return r.next.SetTitle(chatID, title, emoji)
+}
+
+// commandAliasRepository замеряет время запросов репозитория алиасов
+type commandAliasRepository struct {
+	observer
+	next repository.CommandAliasRepository
+}
+
+// WrapCommandAliasRepository добавляет замер времени запросов к репозиторию алиасов
+func (m *Metrics) WrapCommandAliasRepository(next repository.CommandAliasRepository) repository.CommandAliasRepository {
+	return &commandAliasRepository{observer: observer{metrics: m, repository: "command_aliases"}, next: next}
+}
+
+func (r *commandAliasRepository) Set(chatID int64, alias, command string) error {
+	defer r.observe("Set")()
+	return r.next.Set(chatID, alias, command)
+}
+
+func (r *commandAliasRepository) Get(chatID int64, alias string) (*domain.CommandAlias, error) {
+	defer r.observe("Get")()
+	return r.next.Get(chatID, alias)
+}
+
+func (r *commandAliasRepository) GetByChatID(chatID int64) ([]domain.CommandAlias, error) {
+	defer r.observe("GetByChatID")()
+	return r.next.GetByChatID(chatID)
+}
+
+func (r *commandAliasRepository) Delete(chatID int64, alias string) error {
+	defer r.observe("Delete")()
+	return r.next.Delete(chatID, alias)
+}
+
+// apiTokenRepository замеряет время запросов репозитория токенов API
+type apiTokenRepository struct {
+	observer
+	next repository.APITokenRepository
+}
+
+// WrapAPITokenRepository добавляет замер времени запросов к репозиторию токенов API
+func (m *Metrics) WrapAPITokenRepository(next repository.APITokenRepository) repository.APITokenRepository {
+	return &apiTokenRepository{observer: observer{metrics: m, repository: "api_tokens"}, next: next}
+}
+
+func (r *apiTokenRepository) Set(chatID int64, tokenHash string, createdBy int64) error {
+	defer r.observe("Set")()
+	return r.next.Set(chatID, tokenHash, createdBy)
+}
+
+func (r *apiTokenRepository) GetByChatID(chatID int64) (*domain.APIToken, error) {
+	defer r.observe("GetByChatID")()
+	return r.next.GetByChatID(chatID)
+}
+
+func (r *apiTokenRepository) GetByHash(tokenHash string) (*domain.APIToken, error) {
+	defer r.observe("GetByHash")()
+	return r.next.GetByHash(tokenHash)
+}
+
+func (r *apiTokenRepository) Delete(chatID int64) error {
+	defer r.observe("Delete")()
+	return r.next.Delete(chatID)
+}
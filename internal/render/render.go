@@ -0,0 +1,349 @@
+// Package render рисует статистику номинации картинкой: таблицу лидеров
+// и календарь победителей текущего месяца. Используются шрифты Go с кириллицей,
+// встроенные в бинарник, поэтому рендер не зависит от шрифтов системы.
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// MaxRows сколько участников помещается в таблицу лидеров; об остальных
+// сообщает подпись Labels.Rest
+const MaxRows = 30
+
+// Размеры изображения в пикселях
+const (
+	width     = 800
+	padding   = 32
+	rowHeight = 36
+	cellGap   = 6
+	cellSize  = 44
+	swatch    = 14
+	barWidth  = 200
+)
+
+// Цвета совпадают с веб-страницей статистики: palette[1..8] для первых мест
+// таблицы лидеров, palette[0] для остальных участников
+var (
+	palette = []color.RGBA{
+		{0x8c, 0x95, 0x9f, 0xff},
+		{0xcf, 0x22, 0x2e, 0xff},
+		{0x2f, 0x81, 0xf7, 0xff},
+		{0x1a, 0x7f, 0x37, 0xff},
+		{0xbf, 0x87, 0x00, 0xff},
+		{0x82, 0x50, 0xdf, 0xff},
+		{0xe1, 0x6f, 0x24, 0xff},
+		{0x1b, 0x7c, 0x83, 0xff},
+		{0xbf, 0x39, 0x89, 0xff},
+	}
+	background = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	foreground = color.RGBA{0x1f, 0x23, 0x28, 0xff}
+	muted      = color.RGBA{0x6e, 0x77, 0x81, 0xff}
+	line       = color.RGBA{0xd8, 0xde, 0xe4, 0xff}
+	cell       = color.RGBA{0xf0, 0xf2, 0xf4, 0xff}
+	white      = color.RGBA{0xff, 0xff, 0xff, 0xff}
+)
+
+// Labels подписи изображения на языке чата
+type Labels struct {
+	Title       string
+	Leaderboard string
+	ColumnUser  string
+	ColumnWins  string
+	Calendar    string
+	Month       string
+	// Weekdays сокращения дней недели с понедельника
+	Weekdays []string
+	// Rest подпись под таблицей, если участников больше MaxRows
+	Rest string
+}
+
+// fonts шрифты разбираются один раз, начертания создаются на каждое изображение:
+// opentype.Face нельзя использовать из нескольких горутин
+var fonts = sync.OnceValues(func() ([2]*opentype.Font, error) {
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, fmt.Errorf("failed to parse regular font: %w", err)
+	}
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return [2]*opentype.Font{}, fmt.Errorf("failed to parse bold font: %w", err)
+	}
+	return [2]*opentype.Font{regular, bold}, nil
+})
+
+// faces начертания одного изображения
+type faces struct {
+	title   font.Face
+	heading font.Face
+	text    font.Face
+	bold    font.Face
+	small   font.Face
+}
+
+func newFaces() (*faces, error) {
+	parsed, err := fonts()
+	if err != nil {
+		return nil, err
+	}
+	regular, bold := parsed[0], parsed[1]
+
+	var result faces
+	for _, face := range []struct {
+		dst  *font.Face
+		font *opentype.Font
+		size float64
+	}{
+		{&result.title, bold, 28},
+		{&result.heading, bold, 20},
+		{&result.text, regular, 17},
+		{&result.bold, bold, 17},
+		{&result.small, regular, 14},
+	} {
+		*face.dst, err = opentype.NewFace(face.font, &opentype.FaceOptions{Size: face.size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create font face: %w", err)
+		}
+	}
+	return &result, nil
+}
+
+// Stats рисует PNG со статистикой номинации и пишет его в w.
+// stats — участники с победами по убыванию, history — выборы чата по возрастанию даты,
+// календарь показывает месяц даты now.
+func Stats(w io.Writer, stats []domain.UserStats, history []domain.PersonOfTheDayRecord, nominationID int64, now time.Time, labels Labels) error {
+	faces, err := newFaces()
+	if err != nil {
+		return err
+	}
+
+	rows := stats
+	if len(rows) > MaxRows {
+		rows = rows[:MaxRows]
+	}
+	start := month(day(now))
+	weeks := weeksInMonth(start)
+
+	height := padding + 40 + // заголовок
+		40 + rowHeight + len(rows)*rowHeight + // таблица лидеров
+		40 + 28 + 28 + weeks*(cellSize+cellGap) + // календарь
+		padding
+	if labels.Rest != "" {
+		height += rowHeight
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	c := canvas{img: img}
+
+	y := padding + 28
+	c.text(faces.title, foreground, padding, y, fit(faces.title, labels.Title, width-2*padding))
+	y += 12
+
+	colors := make(map[int64]int, len(stats))
+	maxWins := 0
+	for i, stat := range stats {
+		if i < len(palette)-1 && stat.Count > 0 {
+			colors[stat.User.ID] = i + 1
+		}
+		maxWins = max(maxWins, stat.Count)
+	}
+
+	y = c.leaderboard(faces, rows, colors, maxWins, labels, y)
+	c.calendar(faces, history, nominationID, colors, start, labels, y)
+
+	if err := png.Encode(w, img); err != nil {
+		return fmt.Errorf("failed to encode png: %w", err)
+	}
+	return nil
+}
+
+// canvas изображение с примитивами рисования
+type canvas struct {
+	img *image.RGBA
+}
+
+// leaderboard рисует таблицу лидеров начиная с y и возвращает y под ней
+func (c canvas) leaderboard(faces *faces, rows []domain.UserStats, colors map[int64]int, maxWins int, labels Labels, y int) int {
+	y += 40
+	c.text(faces.heading, foreground, padding, y, labels.Leaderboard)
+
+	winsRight := width - padding
+	barLeft := winsRight - 60 - barWidth
+	nameLeft := padding + 44 + swatch + 10
+	nameWidth := barLeft - 16 - nameLeft
+
+	y += rowHeight
+	c.text(faces.small, muted, padding, y-10, "#")
+	c.text(faces.small, muted, nameLeft, y-10, labels.ColumnUser)
+	c.textRight(faces.small, muted, winsRight, y-10, labels.ColumnWins)
+	c.rect(padding, y, width-padding, y+1, line)
+
+	for i, stat := range rows {
+		top := y
+		y += rowHeight
+		baseline := y - 11
+
+		rank := strconv.Itoa(rankOf(rows, i))
+		c.text(faces.bold, muted, padding, baseline, rank)
+
+		fill := palette[colors[stat.User.ID]]
+		swatchTop := top + (rowHeight-swatch)/2
+		c.rect(padding+44, swatchTop, padding+44+swatch, swatchTop+swatch, fill)
+		c.text(faces.text, foreground, nameLeft, baseline, fit(faces.text, stat.User.FullName(), nameWidth))
+
+		if maxWins > 0 && stat.Count > 0 {
+			bar := max(2, barWidth*stat.Count/maxWins)
+			c.rect(barLeft, top+12, barLeft+bar, y-12, fill)
+		}
+		c.textRight(faces.bold, foreground, winsRight, baseline, strconv.Itoa(stat.Count))
+		c.rect(padding, y, width-padding, y+1, line)
+	}
+
+	if labels.Rest != "" {
+		y += rowHeight
+		c.text(faces.small, muted, nameLeft, y-12, labels.Rest)
+	}
+	return y
+}
+
+// calendar рисует календарь месяца start: клетка дня закрашена цветом победителя
+func (c canvas) calendar(faces *faces, history []domain.PersonOfTheDayRecord, nominationID int64, colors map[int64]int, start time.Time, labels Labels, y int) {
+	winners := make(map[int]int64)
+	for _, record := range history {
+		if record.NominationID != nominationID {
+			continue
+		}
+		if date := day(record.Date); month(date).Equal(start) {
+			winners[date.Day()] = record.UserID
+		}
+	}
+
+	y += 40
+	c.text(faces.heading, foreground, padding, y, labels.Calendar)
+	y += 28
+	c.text(faces.text, muted, padding, y, labels.Month)
+
+	cellWidth := (width - 2*padding - 6*cellGap) / 7
+	y += 28
+	for i, weekday := range labels.Weekdays {
+		if i == 7 {
+			break
+		}
+		x := padding + i*(cellWidth+cellGap)
+		c.textCenter(faces.small, muted, x+cellWidth/2, y-8, weekday)
+	}
+
+	// Неделя начинается с понедельника: Weekday воскресенья 0 становится 6
+	column := (int(start.Weekday()) + 6) % 7
+	top := y
+	for date := start; date.Month() == start.Month(); date = date.AddDate(0, 0, 1) {
+		x := padding + column*(cellWidth+cellGap)
+
+		fill, text := cell, foreground
+		if userID, ok := winners[date.Day()]; ok {
+			fill, text = palette[colors[userID]], white
+		}
+		c.rect(x, top, x+cellWidth, top+cellSize, fill)
+		c.textCenter(faces.bold, text, x+cellWidth/2, top+cellSize/2+6, strconv.Itoa(date.Day()))
+
+		column++
+		if column == 7 {
+			column = 0
+			top += cellSize + cellGap
+		}
+	}
+}
+
+// rect закрашивает прямоугольник
+func (c canvas) rect(x0, y0, x1, y1 int, fill color.Color) {
+	draw.Draw(c.img, image.Rect(x0, y0, x1, y1), image.NewUniform(fill), image.Point{}, draw.Src)
+}
+
+// text пишет строку от x по базовой линии y
+func (c canvas) text(face font.Face, fill color.Color, x, y int, s string) {
+	drawer := font.Drawer{Dst: c.img, Src: image.NewUniform(fill), Face: face, Dot: fixed.P(x, y)}
+	drawer.DrawString(printable(face, s))
+}
+
+// textRight пишет строку, выровненную по правому краю x
+func (c canvas) textRight(face font.Face, fill color.Color, x, y int, s string) {
+	s = printable(face, s)
+	c.text(face, fill, x-font.MeasureString(face, s).Round(), y, s)
+}
+
+// textCenter пишет строку с центром в x
+func (c canvas) textCenter(face font.Face, fill color.Color, x, y int, s string) {
+	s = printable(face, s)
+	c.text(face, fill, x-font.MeasureString(face, s).Round()/2, y, s)
+}
+
+// printable убирает символы, которых нет в шрифте (например, эмодзи в именах),
+// чтобы вместо них не рисовались пустые квадраты
+func printable(face font.Face, s string) string {
+	runes := make([]rune, 0, len(s))
+	for _, r := range s {
+		if _, ok := face.GlyphAdvance(r); ok {
+			runes = append(runes, r)
+		}
+	}
+	return string(runes)
+}
+
+// fit обрезает строку с многоточием, чтобы она помещалась в maxWidth пикселей
+func fit(face font.Face, s string, maxWidth int) string {
+	s = printable(face, s)
+	if font.MeasureString(face, s).Round() <= maxWidth {
+		return s
+	}
+
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if truncated := string(runes) + "…"; font.MeasureString(face, truncated).Round() <= maxWidth {
+			return truncated
+		}
+	}
+	return ""
+}
+
+// rankOf возвращает место участника: участники с равным числом побед делят место
+func rankOf(rows []domain.UserStats, i int) int {
+	for i > 0 && rows[i-1].Count == rows[i].Count {
+		i--
+	}
+	return i + 1
+}
+
+// weeksInMonth возвращает число строк календаря месяца start с понедельника
+func weeksInMonth(start time.Time) int {
+	days := start.AddDate(0, 1, -1).Day()
+	offset := (int(start.Weekday()) + 6) % 7
+	return (offset + days + 6) / 7
+}
+
+// day возвращает календарную дату выбора в часовом поясе по умолчанию
+func day(date time.Time) time.Time {
+	year, month, dayOfMonth := date.Date()
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.Local)
+}
+
+// month возвращает первое число месяца даты
+func month(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// APITokenRepositoryImpl реализует APITokenRepository
+type APITokenRepositoryImpl struct {
+	db *Database
+}
+
+// NewAPITokenRepository создает новый экземпляр APITokenRepository
+func NewAPITokenRepository(db *Database) APITokenRepository {
+	return &APITokenRepositoryImpl{db: db}
+}
+
+// Set сохраняет токен чата, заменяя предыдущий
+func (r *APITokenRepositoryImpl) Set(chatID int64, tokenHash string, createdBy int64) error {
+	query := r.db.psql.Insert("api_tokens").
+		Columns("chat_id", "token_hash", "created_by", "created_at").
+		Values(chatID, tokenHash, createdBy, squirrel.Expr("CURRENT_TIMESTAMP")).
+		Suffix(`ON CONFLICT(chat_id) DO UPDATE SET token_hash = excluded.token_hash,
+			created_by = excluded.created_by, created_at = excluded.created_at`)
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set api token: %w", err)
+	}
+
+	return nil
+}
+
+// GetByChatID возвращает токен чата или nil, если его нет
+func (r *APITokenRepositoryImpl) GetByChatID(chatID int64) (*domain.APIToken, error) {
+	return r.get(squirrel.Eq{"chat_id": chatID})
+}
+
+// GetByHash возвращает токен по хешу или nil, если такого токена нет
+func (r *APITokenRepositoryImpl) GetByHash(tokenHash string) (*domain.APIToken, error) {
+	return r.get(squirrel.Eq{"token_hash": tokenHash})
+}
+
+// get возвращает токен по условию
+func (r *APITokenRepositoryImpl) get(where squirrel.Eq) (*domain.APIToken, error) {
+	query := r.db.psql.Select("chat_id", "token_hash", "created_by", "created_at").
+		From("api_tokens").
+		Where(where)
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var token domain.APIToken
+	err = r.db.conn.QueryRow(sqlStr, args...).
+		Scan(&token.ChatID, &token.TokenHash, &token.CreatedBy, &token.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get api token: %w", err)
+	}
+
+	return &token, nil
+}
+
+// Delete удаляет токен чата
+func (r *APITokenRepositoryImpl) Delete(chatID int64) error {
+	query := r.db.psql.Delete("api_tokens").
+		Where(squirrel.Eq{"chat_id": chatID})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete api token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// SchemaVersion версия схемы базы данных, записывается в PRAGMA user_version SQLite.
+// Увеличивается при каждой новой миграции; резервную копию с более новой схемой
+// восстановить нельзя, копии более старых версий обновляются миграциями при запуске.
+const SchemaVersion = 2
+
+// requiredTables таблицы, без которых файл не считается базой бота
+var requiredTables = []string{"users", "person_of_the_day"}
+
+// Backup создает согласованную копию базы SQLite через VACUUM INTO.
+// Копия сначала пишется во временный файл и переименовывается, поэтому
+// файл по пути path всегда содержит полную копию.
+func (db *Database) Backup(path string) error {
+	if !IsSQLite(db.dialect.Driver) {
+		return fmt.Errorf("backup is supported only for SQLite, use pg_dump for %s", db.dialect.Driver)
+	}
+
+	tmpPath := path + ".tmp"
+	// VACUUM INTO не перезаписывает существующий файл
+	if err := os.Remove(tmpPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove stale backup %s: %w", tmpPath, err)
+	}
+
+	started := time.Now()
+	if _, err := db.conn.Exec(`VACUUM INTO ?`, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to backup database: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("failed to save backup %s: %w", path, err)
+	}
+
+	db.logger.Info("Резервная копия создана", "path", path, "duration", time.Since(started))
+	return nil
+}
+
+// setSchemaVersion записывает версию схемы после применения миграций
+func (db *Database) setSchemaVersion() error {
+	if !IsSQLite(db.dialect.Driver) {
+		return nil
+	}
+	if _, err := db.conn.Exec(fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+		return fmt.Errorf("failed to set schema version: %w", err)
+	}
+	return nil
+}
+
+// ValidateBackup проверяет, что файл является целой базой бота со схемой,
+// которую поддерживает текущая версия, и возвращает версию схемы копии.
+// Версия 0 означает копию, созданную до появления версий схемы.
+func ValidateBackup(driver, path string) (int, error) {
+	if !IsSQLite(driver) {
+		return 0, fmt.Errorf("restore is supported only for SQLite, got driver %s", driver)
+	}
+	if _, err := DialectFor(driver); err != nil {
+		return 0, err
+	}
+	if _, err := os.Stat(path); err != nil {
+		return 0, fmt.Errorf("backup not found: %w", err)
+	}
+
+	conn, err := sql.Open(driver, readOnlyDSN(path))
+	if err != nil {
+		return 0, fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	var integrity string
+	if err := conn.QueryRow(`PRAGMA integrity_check`).Scan(&integrity); err != nil {
+		return 0, fmt.Errorf("failed to check backup integrity: %w", err)
+	}
+	if integrity != "ok" {
+		return 0, fmt.Errorf("backup integrity check failed: %s", integrity)
+	}
+
+	var version int
+	if err := conn.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to read backup schema version: %w", err)
+	}
+	if version > SchemaVersion {
+		return version, fmt.Errorf("backup schema version %d is newer than supported version %d", version, SchemaVersion)
+	}
+
+	for _, table := range requiredTables {
+		var count int
+		err := conn.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+		if err != nil {
+			return version, fmt.Errorf("failed to read backup schema: %w", err)
+		}
+		if count == 0 {
+			return version, fmt.Errorf("backup has no table %s", table)
+		}
+	}
+
+	return version, nil
+}
+
+// Restore заменяет файл базы dbPath проверенной резервной копией.
+// Текущая база вместе с файлами журнала сохраняется рядом под уникальным именем
+// с суффиксом .before-restore-<время>; при ошибке файлы возвращаются на место.
+// Бот должен быть остановлен: открытые соединения продолжат работать со старым файлом.
+// Возвращает версию схемы восстановленной копии и путь сохраненной базы
+// (пустой, если базы не было).
+func Restore(driver, backupPath, dbPath string) (int, string, error) {
+	version, err := ValidateBackup(driver, backupPath)
+	if err != nil {
+		return version, "", err
+	}
+
+	tmpPath := dbPath + ".restore"
+	if err := copyFile(backupPath, tmpPath); err != nil {
+		return version, "", err
+	}
+
+	savedPath, err := freeRestorePath(dbPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return version, "", err
+	}
+
+	// Файлы журнала принадлежат текущей базе и переносятся вместе с ней,
+	// даже если самого файла базы нет: иначе SQLite применит чужой журнал к копии
+	var moved []string
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			_ = os.Rename(savedPath+moved[i], dbPath+moved[i])
+		}
+		_ = os.Remove(tmpPath)
+	}
+	for _, suffix := range restoreSuffixes {
+		current := dbPath + suffix
+		if _, err := os.Stat(current); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err := os.Rename(current, savedPath+suffix); err != nil {
+			rollback()
+			return version, "", fmt.Errorf("failed to move current database %s: %w", current, err)
+		}
+		moved = append(moved, suffix)
+	}
+
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		rollback()
+		return version, "", fmt.Errorf("failed to replace database %s: %w", dbPath, err)
+	}
+
+	if len(moved) == 0 {
+		savedPath = ""
+	}
+	return version, savedPath, nil
+}
+
+// restoreSuffixes суффиксы файла базы SQLite и ее файлов журнала
+var restoreSuffixes = []string{"", "-wal", "-shm"}
+
+// freeRestorePath возвращает имя для сохранения текущей базы перед восстановлением,
+// не занятое ни самой базой, ни файлами журнала от прошлых восстановлений
+func freeRestorePath(dbPath string) (string, error) {
+	base := dbPath + ".before-restore-" + time.Now().Format("20060102-150405")
+	for attempt := 1; attempt <= 100; attempt++ {
+		candidate := base
+		if attempt > 1 {
+			candidate = fmt.Sprintf("%s-%d", base, attempt)
+		}
+
+		free := true
+		for _, suffix := range restoreSuffixes {
+			if _, err := os.Lstat(candidate + suffix); !errors.Is(err, os.ErrNotExist) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("no free name to save current database %s", dbPath)
+}
+
+// readOnlyDSN возвращает URI для открытия файла SQLite только на чтение
+func readOnlyDSN(path string) string {
+	escaped := strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23").Replace(path)
+	return "file:" + escaped + "?mode=ro"
+}
+
+// copyFile копирует файл и сбрасывает его на диск
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer func() { _ = in.Close() }()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		_ = os.Remove(dst)
+		return fmt.Errorf("failed to sync %s: %w", dst, err)
+	}
+	return out.Close()
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// ChatSettingsRepositoryImpl реализует ChatSettingsRepository
+type ChatSettingsRepositoryImpl struct {
+	db *Database
+}
+
+// NewChatSettingsRepository создает новый экземпляр ChatSettingsRepository
+func NewChatSettingsRepository(db *Database) ChatSettingsRepository {
+	return &ChatSettingsRepositoryImpl{db: db}
+}
+
+// Get возвращает настройки чата или nil, если они не заданы
+func (r *ChatSettingsRepositoryImpl) Get(chatID int64) (*domain.ChatSettings, error) {
+	query := r.db.psql.Select("chat_id", "language", "title", "emoji", "updated_at").
+		From("chat_settings").
+		Where(squirrel.Eq{"chat_id": chatID})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	row := r.db.conn.QueryRow(sqlStr, args...)
+
+	var settings domain.ChatSettings
+	var language sql.NullString
+	var title sql.NullString
+	var emoji sql.NullString
+
+	err = row.Scan(&settings.ChatID, &language, &title, &emoji, &settings.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get chat settings: %w", err)
+	}
+
+	if language.Valid {
+		settings.Language = language.String
+	}
+	if title.Valid {
+		settings.Title = title.String
+	}
+	if emoji.Valid {
+		settings.Emoji = emoji.String
+	}
+
+	return &settings, nil
+}
+
+// SetLanguage устанавливает язык чата
+func (r *ChatSettingsRepositoryImpl) SetLanguage(chatID int64, language string) error {
+	query := r.db.psql.Insert("chat_settings").
+		Columns("chat_id", "language").
+		Values(chatID, language).
+		Suffix("ON CONFLICT(chat_id) DO UPDATE SET language = excluded.language, updated_at = CURRENT_TIMESTAMP")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set chat language: %w", err)
+	}
+
+	return nil
+}
+
+// SetTitle устанавливает название и эмодзи роли чата
+func (r *ChatSettingsRepositoryImpl) SetTitle(chatID int64, title, emoji string) error {
+	query := r.db.psql.Insert("chat_settings").
+		Columns("chat_id", "title", "emoji").
+		Values(chatID, title, emoji).
+		Suffix("ON CONFLICT(chat_id) DO UPDATE SET title = excluded.title, emoji = excluded.emoji, updated_at = CURRENT_TIMESTAMP")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set chat title: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// CommandAliasRepositoryImpl реализует CommandAliasRepository
+type CommandAliasRepositoryImpl struct {
+	db *Database
+}
+
+// NewCommandAliasRepository создает новый экземпляр CommandAliasRepository
+func NewCommandAliasRepository(db *Database) CommandAliasRepository {
+	return &CommandAliasRepositoryImpl{db: db}
+}
+
+// Set добавляет или изменяет алиас команды в чате
+func (r *CommandAliasRepositoryImpl) Set(chatID int64, alias, command string) error {
+	query := r.db.psql.Insert("command_aliases").
+		Columns("chat_id", "alias", "command").
+		Values(chatID, alias, command).
+		Suffix("ON CONFLICT(chat_id, alias) DO UPDATE SET command = excluded.command")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to set command alias: %w", err)
+	}
+
+	return nil
+}
+
+// Get возвращает алиас чата или nil, если его нет
+func (r *CommandAliasRepositoryImpl) Get(chatID int64, alias string) (*domain.CommandAlias, error) {
+	query := r.db.psql.Select("chat_id", "alias", "command", "created_at").
+		From("command_aliases").
+		Where(squirrel.Eq{"chat_id": chatID, "alias": alias})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var commandAlias domain.CommandAlias
+	err = r.db.conn.QueryRow(sqlStr, args...).
+		Scan(&commandAlias.ChatID, &commandAlias.Alias, &commandAlias.Command, &commandAlias.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get command alias: %w", err)
+	}
+
+	return &commandAlias, nil
+}
+
+// GetByChatID возвращает все алиасы чата
+func (r *CommandAliasRepositoryImpl) GetByChatID(chatID int64) ([]domain.CommandAlias, error) {
+	query := r.db.psql.Select("chat_id", "alias", "command", "created_at").
+		From("command_aliases").
+		Where(squirrel.Eq{"chat_id": chatID}).
+		OrderBy("alias")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.conn.Query(sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get command aliases: %w", err)
+	}
+	defer r.db.closeRows(rows)
+
+	var aliases []domain.CommandAlias
+	for rows.Next() {
+		var commandAlias domain.CommandAlias
+		err := rows.Scan(&commandAlias.ChatID, &commandAlias.Alias, &commandAlias.Command, &commandAlias.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan command alias: %w", err)
+		}
+		aliases = append(aliases, commandAlias)
+	}
+
+	return aliases, nil
+}
+
+// Delete удаляет алиас чата
+func (r *CommandAliasRepositoryImpl) Delete(chatID int64, alias string) error {
+	query := r.db.psql.Delete("command_aliases").
+		Where(squirrel.Eq{"chat_id": chatID, "alias": alias})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	_, err = r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return fmt.Errorf("failed to delete command alias: %w", err)
+	}
+
+	return nil
+}
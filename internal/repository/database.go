@@ -1,64 +1,351 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/Masterminds/squirrel"
-	_ "github.com/mattn/go-sqlite3"
+	_ "github.com/lib/pq"
+	_ "modernc.org/sqlite"
 )
 
 // Database представляет подключение к базе данных
 type Database struct {
-	conn *sql.DB
-	psql squirrel.StatementBuilderType
+	conn    *sql.DB
+	psql    squirrel.StatementBuilderType
+	dialect Dialect
+	options Options
+	logger  *slog.Logger
 }
 
-// NewDatabase создает новое подключение к базе данных
-func NewDatabase(dbPath string) (*Database, error) {
-	conn, err := sql.Open("sqlite3", dbPath)
+// Options содержит настройки подключения к хранилищу.
+// Параметры SQLite передаются через строку подключения, поэтому применяются
+// к каждому соединению пула; для PostgreSQL используются только настройки пула.
+type Options struct {
+	// JournalMode режим журнала SQLite (WAL, DELETE, ...), пустая строка — режим по умолчанию
+	JournalMode string
+	// BusyTimeout время ожидания снятия блокировки SQLite вместо ошибки "database is locked"
+	BusyTimeout time.Duration
+	// ForeignKeys включает проверку внешних ключей SQLite
+	ForeignKeys bool
+	// IntegrityCheck выполняет PRAGMA integrity_check при открытии базы SQLite
+	IntegrityCheck bool
+	// MaxOpenConns максимальное число открытых соединений, 0 — без ограничения
+	MaxOpenConns int
+	// MaxIdleConns максимальное число простаивающих соединений
+	MaxIdleConns int
+	// ConnMaxLifetime максимальное время жизни соединения, 0 — без ограничения
+	ConnMaxLifetime time.Duration
+}
+
+// DefaultOptions возвращает настройки подключения по умолчанию
+func DefaultOptions() Options {
+	return Options{
+		JournalMode:  "WAL",
+		BusyTimeout:  5 * time.Second,
+		ForeignKeys:  true,
+		MaxOpenConns: 8,
+		MaxIdleConns: 4,
+	}
+}
+
+// JournalModes возвращает допустимые режимы журнала SQLite
+func JournalModes() []string {
+	return []string{"DELETE", "TRUNCATE", "PERSIST", "MEMORY", "WAL", "OFF"}
+}
+
+// NewDatabase создает новое подключение к базе данных SQLite драйвером по умолчанию
+func NewDatabase(dbPath string, logger *slog.Logger) (*Database, error) {
+	return Open(DefaultSQLiteDriver, dbPath, DefaultOptions(), logger)
+}
+
+// Open создает подключение к хранилищу выбранного драйвера и применяет миграции.
+// Если logger равен nil, используется slog.Default().
+func Open(driver, dsn string, opts Options, logger *slog.Logger) (*Database, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	dialect, err := DialectFor(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := sql.Open(dialect.Driver, dialect.dataSource(dsn, opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	conn.SetMaxOpenConns(opts.MaxOpenConns)
+	conn.SetMaxIdleConns(opts.MaxIdleConns)
+	conn.SetConnMaxLifetime(opts.ConnMaxLifetime)
+
 	db := &Database{
-		conn: conn,
-		psql: squirrel.StatementBuilder.PlaceholderFormat(squirrel.Question),
+		conn:    conn,
+		psql:    squirrel.StatementBuilder.PlaceholderFormat(dialect.Placeholder),
+		dialect: dialect,
+		options: opts,
+		logger:  logger.With("component", "repository", "driver", driver),
+	}
+
+	if opts.IntegrityCheck && IsSQLite(driver) {
+		if err := db.checkIntegrity(); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
 	}
 
 	if err := db.createTables(); err != nil {
+		_ = conn.Close()
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 
 	return db, nil
 }
 
+// checkIntegrity проверяет целостность файла SQLite.
+// PRAGMA integrity_check возвращает "ok" или список найденных проблем.
+func (db *Database) checkIntegrity() error {
+	rows, err := db.conn.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return fmt.Errorf("failed to check database integrity: %w", err)
+	}
+	defer db.closeRows(rows)
+
+	var problems []string
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			return fmt.Errorf("failed to scan integrity check result: %w", err)
+		}
+		if result != "ok" {
+			problems = append(problems, result)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to check database integrity: %w", err)
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("database integrity check failed: %s", strings.Join(problems, "; "))
+	}
+
+	db.logger.Info("Проверка целостности базы данных пройдена")
+	return nil
+}
+
+// Driver возвращает имя драйвера хранилища
+func (db *Database) Driver() string {
+	return db.dialect.Driver
+}
+
+// Ping проверяет, что база данных доступна
+func (db *Database) Ping(ctx context.Context) error {
+	if err := db.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// personOfTheDaySchema описывает таблицу выборов с учетом номинаций.
+// Типы в фигурных скобках подставляет диалект хранилища.
+const personOfTheDaySchema = `CREATE TABLE IF NOT EXISTS person_of_the_day (
+			id {serial},
+			user_id {bigint} NOT NULL,
+			chat_id {bigint} NOT NULL,
+			nomination_id {bigint} NOT NULL DEFAULT 0,
+			date DATE NOT NULL,
+			created_at {timestamp} DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id),
+			UNIQUE(chat_id, nomination_id, date)
+		)`
+
 // createTables создает необходимые таблицы
 func (db *Database) createTables() error {
-	queries := []string{
+	tables := []string{
 		`CREATE TABLE IF NOT EXISTS users (
-			id INTEGER PRIMARY KEY,
+			id {bigint} PRIMARY KEY,
 			username TEXT,
 			first_name TEXT NOT NULL,
 			last_name TEXT,
-			chat_id INTEGER NOT NULL,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			chat_id {bigint} NOT NULL,
+			created_at {timestamp} DEFAULT CURRENT_TIMESTAMP,
 			UNIQUE(id, chat_id)
 		)`,
-		`CREATE TABLE IF NOT EXISTS person_of_the_day (
-			id INTEGER PRIMARY KEY AUTOINCREMENT,
-			user_id INTEGER NOT NULL,
-			chat_id INTEGER NOT NULL,
-			date DATE NOT NULL,
-			created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-			FOREIGN KEY (user_id) REFERENCES users(id),
-			UNIQUE(chat_id, date)
+		personOfTheDaySchema,
+		`CREATE TABLE IF NOT EXISTS chat_settings (
+			chat_id {bigint} PRIMARY KEY,
+			language TEXT,
+			title TEXT,
+			emoji TEXT,
+			updated_at {timestamp} DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS nominations (
+			id {serial},
+			chat_id {bigint} NOT NULL,
+			command TEXT NOT NULL,
+			title TEXT NOT NULL,
+			emoji TEXT,
+			strategy TEXT NOT NULL DEFAULT 'random',
+			created_at {timestamp} DEFAULT CURRENT_TIMESTAMP,
+			UNIQUE(chat_id, command)
+		)`,
+		`CREATE TABLE IF NOT EXISTS command_aliases (
+			chat_id {bigint} NOT NULL,
+			alias TEXT NOT NULL,
+			command TEXT NOT NULL,
+			created_at {timestamp} DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (chat_id, alias)
 		)`,
+		`CREATE TABLE IF NOT EXISTS api_tokens (
+			chat_id {bigint} PRIMARY KEY,
+			token_hash TEXT NOT NULL UNIQUE,
+			created_by {bigint} NOT NULL,
+			created_at {timestamp} DEFAULT CURRENT_TIMESTAMP
+		)`,
+	}
+	indexes := []string{
 		`CREATE INDEX IF NOT EXISTS idx_person_of_the_day_chat_date ON person_of_the_day(chat_id, date)`,
 		`CREATE INDEX IF NOT EXISTS idx_users_chat ON users(chat_id)`,
 	}
 
+	if err := db.execAll(tables); err != nil {
+		return err
+	}
+
+	// Добавляем колонки, появившиеся после создания таблиц
+	if err := db.ensureColumns("chat_settings", map[string]string{
+		"title": "TEXT",
+		"emoji": "TEXT",
+	}); err != nil {
+		return err
+	}
+
+	if err := db.migratePersonOfTheDayNominations(); err != nil {
+		return err
+	}
+
+	if err := db.execAll(indexes); err != nil {
+		return err
+	}
+
+	return db.setSchemaVersion()
+}
+
+// execAll последовательно выполняет запросы с типами диалекта
+func (db *Database) execAll(queries []string) error {
 	for _, query := range queries {
+		query = db.dialect.schema(query)
+		if _, err := db.conn.Exec(query); err != nil {
+			return fmt.Errorf("failed to execute query %s: %w", query, err)
+		}
+	}
+	return nil
+}
+
+// migratePersonOfTheDayNominations пересоздает таблицу person_of_the_day,
+// созданную до появления номинаций: SQLite не позволяет изменить UNIQUE ограничение
+func (db *Database) migratePersonOfTheDayNominations() error {
+	columns, err := db.tableColumns("person_of_the_day")
+	if err != nil {
+		return err
+	}
+	if columns["nomination_id"] {
+		return nil
+	}
+
+	db.logger.Info("Миграция таблицы person_of_the_day на номинации")
+
+	ctx := context.Background()
+	conn, err := db.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			db.logger.Warn("Ошибка закрытия соединения миграции", "error", err)
+		}
+	}()
+
+	// Старые базы могли накопить записи без пользователя, пока внешние ключи не проверялись.
+	// Пересоздание таблицы выполняется с отключенными внешними ключами, как рекомендует SQLite;
+	// PRAGMA действует только на это соединение и не меняется внутри транзакции.
+	if IsSQLite(db.dialect.Driver) {
+		if _, err := conn.ExecContext(ctx, `PRAGMA foreign_keys = OFF`); err != nil {
+			return fmt.Errorf("failed to disable foreign keys for migration: %w", err)
+		}
+		defer func() {
+			restore := "PRAGMA foreign_keys = " + sqliteBool(db.options.ForeignKeys)
+			if _, err := conn.ExecContext(ctx, restore); err != nil {
+				db.logger.Warn("Ошибка включения внешних ключей после миграции", "error", err)
+			}
+		}()
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration: %w", err)
+	}
+
+	queries := []string{
+		`ALTER TABLE person_of_the_day RENAME TO person_of_the_day_old`,
+		personOfTheDaySchema,
+		`INSERT INTO person_of_the_day (id, user_id, chat_id, nomination_id, date, created_at)
+			SELECT id, user_id, chat_id, 0, date, created_at FROM person_of_the_day_old`,
+		`DROP TABLE person_of_the_day_old`,
+	}
+	for _, query := range queries {
+		query = db.dialect.schema(query)
+		if _, err := tx.Exec(query); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to execute query %s: %w", query, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+
+	return nil
+}
+
+// tableColumns возвращает множество колонок таблицы
+func (db *Database) tableColumns(table string) (map[string]bool, error) {
+	rows, err := db.conn.Query(db.dialect.columnsQuery, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get columns of %s: %w", table, err)
+	}
+	defer db.closeRows(rows)
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to scan columns of %s: %w", table, err)
+		}
+		columns[name] = true
+	}
+
+	return columns, rows.Err()
+}
+
+// ensureColumns добавляет в таблицу отсутствующие колонки
+func (db *Database) ensureColumns(table string, columns map[string]string) error {
+	existing, err := db.tableColumns(table)
+	if err != nil {
+		return err
+	}
+
+	for name, definition := range columns {
+		if existing[name] {
+			continue
+		}
+
+		query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, name, db.dialect.schema(definition))
 		if _, err := db.conn.Exec(query); err != nil {
 			return fmt.Errorf("failed to execute query %s: %w", query, err)
 		}
@@ -67,6 +354,13 @@ func (db *Database) createTables() error {
 	return nil
 }
 
+// closeRows закрывает результат запроса, ошибка только логируется
+func (db *Database) closeRows(rows *sql.Rows) {
+	if err := rows.Close(); err != nil {
+		db.logger.Warn("Ошибка закрытия rows", "error", err)
+	}
+}
+
 // Close закрывает соединение с базой данных
 func (db *Database) Close() error {
 	if db.conn != nil {
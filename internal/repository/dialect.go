@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+)
+
+// Поддерживаемые драйверы хранилища. DriverSQLite использует CGO (mattn/go-sqlite3)
+// и доступен только в сборке с CGO, DriverSQLitePure написан на чистом Go (modernc.org/sqlite).
+const (
+	DriverSQLite     = "sqlite3"
+	DriverSQLitePure = "sqlite"
+	DriverPostgres   = "postgres"
+)
+
+// sqliteTypes типы колонок SQLite
+var sqliteTypes = strings.NewReplacer(
+	"{serial}", "INTEGER PRIMARY KEY AUTOINCREMENT",
+	"{bigint}", "INTEGER",
+	"{timestamp}", "DATETIME",
+)
+
+// sqliteColumnsQuery запрос колонок таблицы SQLite
+const sqliteColumnsQuery = `SELECT name FROM pragma_table_info(?)`
+
+// Dialect описывает различия SQL диалектов хранилищ.
+// Запросы репозиториев и набор миграций общие, диалект подставляет
+// формат плейсхолдеров, типы колонок и запрос списка колонок таблицы.
+type Dialect struct {
+	// Driver имя драйвера database/sql
+	Driver string
+	// Placeholder формат плейсхолдеров squirrel
+	Placeholder squirrel.PlaceholderFormat
+	// types заменяет обозначения типов в схеме ({serial}, {bigint}, {timestamp})
+	types *strings.Replacer
+	// columnsQuery возвращает имена колонок таблицы, параметр — имя таблицы
+	columnsQuery string
+	// params возвращает параметры строки подключения для настроек Options, nil — не поддерживаются
+	params func(opts Options) url.Values
+}
+
+// dialects содержит диалекты по имени драйвера
+var dialects = map[string]Dialect{
+	DriverSQLite: {
+		Driver:       DriverSQLite,
+		Placeholder:  squirrel.Question,
+		types:        sqliteTypes,
+		columnsQuery: sqliteColumnsQuery,
+		params:       mattnParams,
+	},
+	DriverSQLitePure: {
+		Driver:       DriverSQLitePure,
+		Placeholder:  squirrel.Question,
+		types:        sqliteTypes,
+		columnsQuery: sqliteColumnsQuery,
+		params:       moderncParams,
+	},
+	DriverPostgres: {
+		Driver:      DriverPostgres,
+		Placeholder: squirrel.Dollar,
+		types: strings.NewReplacer(
+			"{serial}", "BIGSERIAL PRIMARY KEY",
+			"{bigint}", "BIGINT",
+			"{timestamp}", "TIMESTAMP",
+		),
+		columnsQuery: `SELECT column_name FROM information_schema.columns
+			WHERE table_schema = current_schema() AND table_name = $1`,
+	},
+}
+
+// mattnParams задает PRAGMA через собственные параметры mattn/go-sqlite3
+func mattnParams(opts Options) url.Values {
+	params := url.Values{}
+	if opts.JournalMode != "" {
+		params.Set("_journal_mode", strings.ToUpper(opts.JournalMode))
+	}
+	if opts.BusyTimeout > 0 {
+		params.Set("_busy_timeout", fmt.Sprint(opts.BusyTimeout.Milliseconds()))
+	}
+	params.Set("_foreign_keys", sqliteBool(opts.ForeignKeys))
+	return params
+}
+
+// moderncParams задает PRAGMA через параметры _pragma modernc.org/sqlite
+func moderncParams(opts Options) url.Values {
+	params := url.Values{}
+	if opts.JournalMode != "" {
+		params.Add("_pragma", fmt.Sprintf("journal_mode(%s)", strings.ToUpper(opts.JournalMode)))
+	}
+	if opts.BusyTimeout > 0 {
+		params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", opts.BusyTimeout.Milliseconds()))
+	}
+	params.Add("_pragma", fmt.Sprintf("foreign_keys(%s)", sqliteBool(opts.ForeignKeys)))
+	return params
+}
+
+// sqliteBool возвращает значение логической PRAGMA
+func sqliteBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "0"
+}
+
+// DialectFor возвращает диалект для драйвера, включенного в сборку
+func DialectFor(driver string) (Dialect, error) {
+	dialect, ok := dialects[driver]
+	if !ok {
+		return Dialect{}, fmt.Errorf("unsupported storage driver %q", driver)
+	}
+	if !slices.Contains(sql.Drivers(), driver) {
+		return Dialect{}, fmt.Errorf("storage driver %q is not available in this build", driver)
+	}
+	return dialect, nil
+}
+
+// Drivers возвращает отсортированный список драйверов, включенных в сборку
+func Drivers() []string {
+	registered := sql.Drivers()
+
+	drivers := make([]string, 0, len(dialects))
+	for driver := range dialects {
+		if slices.Contains(registered, driver) {
+			drivers = append(drivers, driver)
+		}
+	}
+	sort.Strings(drivers)
+	return drivers
+}
+
+// IsSQLite проверяет, является ли драйвер драйвером SQLite
+func IsSQLite(driver string) bool {
+	return driver == DriverSQLite || driver == DriverSQLitePure
+}
+
+// dataSource добавляет к строке подключения параметры диалекта для настроек opts.
+// Параметры, уже указанные в строке подключения, не переопределяются.
+func (d Dialect) dataSource(dsn string, opts Options) string {
+	if d.params == nil {
+		return dsn
+	}
+
+	base, query, _ := strings.Cut(dsn, "?")
+	existing, err := url.ParseQuery(query)
+	if err != nil {
+		return dsn
+	}
+
+	params := d.params(opts)
+	for key := range params {
+		if existing.Has(key) {
+			params.Del(key)
+		}
+	}
+	if len(params) == 0 {
+		return dsn
+	}
+
+	if query != "" {
+		return base + "?" + query + "&" + params.Encode()
+	}
+	return base + "?" + params.Encode()
+}
+
+// schema подставляет типы диалекта в DDL запрос
+func (d Dialect) schema(ddl string) string {
+	return d.types.Replace(ddl)
+}
@@ -11,11 +11,47 @@ type UserRepository interface {
 	Add(user domain.User) error
 	GetByChatID(chatID int64) ([]domain.User, error)
 	GetByID(userID, chatID int64) (*domain.User, error)
+	GetByUserID(userID int64) (*domain.User, error)
 }
 
 // PersonOfTheDayRepository определяет интерфейс для работы с записями человека дня
 type PersonOfTheDayRepository interface {
-	Set(userID, chatID int64, date time.Time) error
-	GetByDate(chatID int64, date time.Time) (*domain.User, error)
-	GetUserStats(chatID int64) ([]domain.UserStats, error)
+	Set(userID, chatID, nominationID int64, date time.Time) error
+	Insert(userID, chatID, nominationID int64, date time.Time) (bool, error)
+	ReplaceStale(userID, chatID, nominationID int64, date time.Time) (bool, error)
+	GetByDate(chatID, nominationID int64, date time.Time) (*domain.User, error)
+	GetUserStats(chatID, nominationID int64) ([]domain.UserStats, error)
+	GetHistory(chatID int64) ([]domain.PersonOfTheDayRecord, error)
+	GetAllHistory() ([]domain.PersonOfTheDayRecord, error)
+}
+
+// NominationRepository определяет интерфейс для работы с номинациями чатов
+type NominationRepository interface {
+	Create(nomination *domain.Nomination) error
+	GetByChatID(chatID int64) ([]domain.Nomination, error)
+	GetByCommand(chatID int64, command string) (*domain.Nomination, error)
+	Delete(chatID int64, command string) error
+}
+
+// ChatSettingsRepository определяет интерфейс для работы с настройками чатов
+type ChatSettingsRepository interface {
+	Get(chatID int64) (*domain.ChatSettings, error)
+	SetLanguage(chatID int64, language string) error
+	SetTitle(chatID int64, title, emoji string) error
+}
+
+// CommandAliasRepository определяет интерфейс для работы с алиасами команд чатов
+type CommandAliasRepository interface {
+	Set(chatID int64, alias, command string) error
+	Get(chatID int64, alias string) (*domain.CommandAlias, error)
+	GetByChatID(chatID int64) ([]domain.CommandAlias, error)
+	Delete(chatID int64, alias string) error
+}
+
+// APITokenRepository определяет интерфейс для работы с токенами HTTP API чатов
+type APITokenRepository interface {
+	Set(chatID int64, tokenHash string, createdBy int64) error
+	GetByChatID(chatID int64) (*domain.APIToken, error)
+	GetByHash(tokenHash string) (*domain.APIToken, error)
+	Delete(chatID int64) error
 }
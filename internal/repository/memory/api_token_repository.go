@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// APITokenRepositoryImpl реализует APITokenRepository в памяти
+type APITokenRepositoryImpl struct {
+	store *Store
+}
+
+// NewAPITokenRepository создает новый экземпляр APITokenRepository в памяти
+func NewAPITokenRepository(store *Store) repository.APITokenRepository {
+	return &APITokenRepositoryImpl{store: store}
+}
+
+// Set сохраняет токен чата, заменяя предыдущий
+func (r *APITokenRepositoryImpl) Set(chatID int64, tokenHash string, createdBy int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Хеш токена уникален, как и в базе данных
+	for _, token := range r.store.tokens {
+		if token.TokenHash == tokenHash && token.ChatID != chatID {
+			return fmt.Errorf("failed to set api token: hash already used by chat %d", token.ChatID)
+		}
+	}
+
+	r.store.tokens[chatID] = domain.APIToken{
+		ChatID:    chatID,
+		TokenHash: tokenHash,
+		CreatedBy: createdBy,
+		CreatedAt: r.store.timestamp(),
+	}
+
+	return nil
+}
+
+// GetByChatID возвращает токен чата или nil, если его нет
+func (r *APITokenRepositoryImpl) GetByChatID(chatID int64) (*domain.APIToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	token, ok := r.store.tokens[chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &token, nil
+}
+
+// GetByHash возвращает токен по хешу или nil, если такого токена нет
+func (r *APITokenRepositoryImpl) GetByHash(tokenHash string) (*domain.APIToken, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, token := range r.store.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+// Delete удаляет токен чата
+func (r *APITokenRepositoryImpl) Delete(chatID int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.tokens, chatID)
+	return nil
+}
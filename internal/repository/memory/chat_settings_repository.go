@@ -0,0 +1,57 @@
+package memory
+
+import (
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// ChatSettingsRepositoryImpl реализует ChatSettingsRepository в памяти
+type ChatSettingsRepositoryImpl struct {
+	store *Store
+}
+
+// NewChatSettingsRepository создает новый экземпляр ChatSettingsRepository в памяти
+func NewChatSettingsRepository(store *Store) repository.ChatSettingsRepository {
+	return &ChatSettingsRepositoryImpl{store: store}
+}
+
+// Get возвращает настройки чата или nil, если они не заданы
+func (r *ChatSettingsRepositoryImpl) Get(chatID int64) (*domain.ChatSettings, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	settings, ok := r.store.settings[chatID]
+	if !ok {
+		return nil, nil
+	}
+	return &settings, nil
+}
+
+// SetLanguage устанавливает язык чата
+func (r *ChatSettingsRepositoryImpl) SetLanguage(chatID int64, language string) error {
+	r.update(chatID, func(settings *domain.ChatSettings) {
+		settings.Language = language
+	})
+	return nil
+}
+
+// SetTitle устанавливает название и эмодзи роли в чате
+func (r *ChatSettingsRepositoryImpl) SetTitle(chatID int64, title, emoji string) error {
+	r.update(chatID, func(settings *domain.ChatSettings) {
+		settings.Title = title
+		settings.Emoji = emoji
+	})
+	return nil
+}
+
+// update создает или изменяет настройки чата
+func (r *ChatSettingsRepositoryImpl) update(chatID int64, change func(settings *domain.ChatSettings)) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	settings := r.store.settings[chatID]
+	settings.ChatID = chatID
+	settings.UpdatedAt = r.store.timestamp()
+	change(&settings)
+	r.store.settings[chatID] = settings
+}
@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// CommandAliasRepositoryImpl реализует CommandAliasRepository в памяти
+type CommandAliasRepositoryImpl struct {
+	store *Store
+}
+
+// NewCommandAliasRepository создает новый экземпляр CommandAliasRepository в памяти
+func NewCommandAliasRepository(store *Store) repository.CommandAliasRepository {
+	return &CommandAliasRepositoryImpl{store: store}
+}
+
+// Set добавляет алиас или меняет его команду
+func (r *CommandAliasRepositoryImpl) Set(chatID int64, alias, command string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := aliasKey{chatID: chatID, alias: alias}
+	existing, ok := r.store.aliases[key]
+	if !ok {
+		existing = domain.CommandAlias{ChatID: chatID, Alias: alias, CreatedAt: r.store.timestamp()}
+	}
+	existing.Command = command
+	r.store.aliases[key] = existing
+
+	return nil
+}
+
+// Get возвращает алиас чата или nil, если его нет
+func (r *CommandAliasRepositoryImpl) Get(chatID int64, alias string) (*domain.CommandAlias, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	existing, ok := r.store.aliases[aliasKey{chatID: chatID, alias: alias}]
+	if !ok {
+		return nil, nil
+	}
+	return &existing, nil
+}
+
+// GetByChatID возвращает все алиасы чата
+func (r *CommandAliasRepositoryImpl) GetByChatID(chatID int64) ([]domain.CommandAlias, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var aliases []domain.CommandAlias
+	for key, alias := range r.store.aliases {
+		if key.chatID == chatID {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.SortFunc(aliases, func(a, b domain.CommandAlias) int {
+		return cmp.Compare(a.Alias, b.Alias)
+	})
+
+	return aliases, nil
+}
+
+// Delete удаляет алиас чата
+func (r *CommandAliasRepositoryImpl) Delete(chatID int64, alias string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.aliases, aliasKey{chatID: chatID, alias: alias})
+	return nil
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// NominationRepositoryImpl реализует NominationRepository в памяти
+type NominationRepositoryImpl struct {
+	store *Store
+}
+
+// NewNominationRepository создает новый экземпляр NominationRepository в памяти
+func NewNominationRepository(store *Store) repository.NominationRepository {
+	return &NominationRepositoryImpl{store: store}
+}
+
+// Create добавляет номинацию и заполняет ее ID
+func (r *NominationRepositoryImpl) Create(nomination *domain.Nomination) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.store.nomination(nomination.ChatID, nomination.Command) != nil {
+		return fmt.Errorf("failed to create nomination: command %q already exists in chat %d",
+			nomination.Command, nomination.ChatID)
+	}
+
+	r.store.lastNominationID++
+	created := *nomination
+	created.ID = r.store.lastNominationID
+	created.CreatedAt = r.store.timestamp()
+	r.store.nominations[created.ID] = created
+
+	nomination.ID = created.ID
+	return nil
+}
+
+// GetByChatID возвращает все номинации чата
+func (r *NominationRepositoryImpl) GetByChatID(chatID int64) ([]domain.Nomination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var nominations []domain.Nomination
+	for _, nomination := range r.store.nominations {
+		if nomination.ChatID == chatID {
+			nominations = append(nominations, nomination)
+		}
+	}
+	slices.SortFunc(nominations, func(a, b domain.Nomination) int {
+		return cmp.Compare(a.Command, b.Command)
+	})
+
+	return nominations, nil
+}
+
+// GetByCommand возвращает номинацию чата по команде или nil, если ее нет
+func (r *NominationRepositoryImpl) GetByCommand(chatID int64, command string) (*domain.Nomination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.nomination(chatID, command), nil
+}
+
+// Delete удаляет номинацию чата вместе с историей ее выборов
+func (r *NominationRepositoryImpl) Delete(chatID int64, command string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	nomination := r.store.nomination(chatID, command)
+	if nomination == nil {
+		return nil
+	}
+
+	for key := range r.store.picks {
+		if key.chatID == chatID && key.nominationID == nomination.ID {
+			delete(r.store.picks, key)
+		}
+	}
+	delete(r.store.nominations, nomination.ID)
+
+	return nil
+}
+
+// nomination ищет номинацию чата по команде. Вызывается под блокировкой.
+func (s *Store) nomination(chatID int64, command string) *domain.Nomination {
+	for _, nomination := range s.nominations {
+		if nomination.ChatID == chatID && nomination.Command == command {
+			return &nomination
+		}
+	}
+	return nil
+}
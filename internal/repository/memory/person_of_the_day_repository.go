@@ -0,0 +1,191 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// PersonOfTheDayRepositoryImpl реализует PersonOfTheDayRepository в памяти
+type PersonOfTheDayRepositoryImpl struct {
+	store *Store
+}
+
+// NewPersonOfTheDayRepository создает новый экземпляр PersonOfTheDayRepository в памяти
+func NewPersonOfTheDayRepository(store *Store) repository.PersonOfTheDayRepository {
+	return &PersonOfTheDayRepositoryImpl{store: store}
+}
+
+// Set устанавливает человека дня в номинации
+func (r *PersonOfTheDayRepositoryImpl) Set(userID, chatID, nominationID int64, date time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return fmt.Errorf("failed to set person of the day: user %d not found", userID)
+	}
+
+	key := pickKey{chatID: chatID, nominationID: nominationID, date: date.Format(dateLayout)}
+	if pick, ok := r.store.picks[key]; ok {
+		pick.UserID = userID
+		r.store.picks[key] = pick
+		return nil
+	}
+	r.store.addPick(key, userID)
+
+	return nil
+}
+
+// Insert добавляет выбор, если на дату в номинации его еще нет.
+// Возвращает false, если дата уже занята: существующий выбор не перезаписывается.
+func (r *PersonOfTheDayRepositoryImpl) Insert(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return false, fmt.Errorf("failed to insert person of the day: user %d not found", userID)
+	}
+
+	key := pickKey{chatID: chatID, nominationID: nominationID, date: date.Format(dateLayout)}
+	if _, ok := r.store.picks[key]; ok {
+		return false, nil
+	}
+	r.store.addPick(key, userID)
+
+	return true, nil
+}
+
+// ReplaceStale заменяет выбор на дату, если его участник больше не состоит в чате.
+// Возвращает false, если выбора нет или он указывает на участника чата.
+func (r *PersonOfTheDayRepositoryImpl) ReplaceStale(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[userID]; !ok {
+		return false, fmt.Errorf("failed to replace person of the day: user %d not found", userID)
+	}
+
+	key := pickKey{chatID: chatID, nominationID: nominationID, date: date.Format(dateLayout)}
+	pick, ok := r.store.picks[key]
+	if !ok {
+		return false, nil
+	}
+	if user, ok := r.store.users[pick.UserID]; ok && user.ChatID == chatID {
+		return false, nil
+	}
+	pick.UserID = userID
+	r.store.picks[key] = pick
+
+	return true, nil
+}
+
+// GetByDate возвращает человека дня в номинации на указанную дату
+func (r *PersonOfTheDayRepositoryImpl) GetByDate(chatID, nominationID int64, date time.Time) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	pick, ok := r.store.picks[pickKey{chatID: chatID, nominationID: nominationID, date: date.Format(dateLayout)}]
+	if !ok {
+		return nil, nil
+	}
+
+	// Как и в базе данных, участник должен быть сейчас в том же чате
+	user, ok := r.store.users[pick.UserID]
+	if !ok || user.ChatID != chatID {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// GetUserStats возвращает статистику пользователей в номинации
+func (r *PersonOfTheDayRepositoryImpl) GetUserStats(chatID, nominationID int64) ([]domain.UserStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int64]int)
+	for key, pick := range r.store.picks {
+		if key.chatID == chatID && key.nominationID == nominationID {
+			counts[pick.UserID]++
+		}
+	}
+
+	var stats []domain.UserStats
+	for _, user := range r.store.chatUsers(chatID) {
+		stats = append(stats, domain.UserStats{User: user, Count: counts[user.ID]})
+	}
+	// chatUsers уже упорядочены по имени, стабильная сортировка сохраняет этот порядок
+	slices.SortStableFunc(stats, func(a, b domain.UserStats) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+
+	return stats, nil
+}
+
+// GetHistory возвращает все выборы чата во всех номинациях по порядку дат
+func (r *PersonOfTheDayRepositoryImpl) GetHistory(chatID int64) ([]domain.PersonOfTheDayRecord, error) {
+	return r.history(func(pick domain.PersonOfTheDay) bool { return pick.ChatID == chatID }), nil
+}
+
+// GetAllHistory возвращает выборы всех чатов
+func (r *PersonOfTheDayRepositoryImpl) GetAllHistory() ([]domain.PersonOfTheDayRecord, error) {
+	return r.history(func(domain.PersonOfTheDay) bool { return true }), nil
+}
+
+// history возвращает выборы с участниками и командами номинаций.
+// Участник находится только по ID: запись пользователя хранит последний чат,
+// в котором он писал, а имя нужно во всех чатах.
+func (r *PersonOfTheDayRepositoryImpl) history(match func(domain.PersonOfTheDay) bool) []domain.PersonOfTheDayRecord {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var records []domain.PersonOfTheDayRecord
+	for _, pick := range r.store.picks {
+		if !match(pick) {
+			continue
+		}
+
+		record := domain.PersonOfTheDayRecord{PersonOfTheDay: pick}
+		user := r.store.users[pick.UserID]
+		record.User = domain.User{
+			ID:        pick.UserID,
+			Username:  user.Username,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			ChatID:    pick.ChatID,
+		}
+
+		record.Nomination = r.store.nominations[pick.NominationID].Command
+		if pick.NominationID == domain.DefaultNominationID {
+			record.Nomination = domain.DefaultNominationCommand
+		}
+
+		records = append(records, record)
+	}
+
+	slices.SortFunc(records, func(a, b domain.PersonOfTheDayRecord) int {
+		return cmp.Or(
+			cmp.Compare(a.ChatID, b.ChatID),
+			a.Date.Compare(b.Date),
+			cmp.Compare(a.NominationID, b.NominationID),
+		)
+	})
+
+	return records
+}
+
+// addPick сохраняет новый выбор. Вызывается под блокировкой.
+func (s *Store) addPick(key pickKey, userID int64) {
+	s.lastPickID++
+	s.picks[key] = domain.PersonOfTheDay{
+		ID:           s.lastPickID,
+		UserID:       userID,
+		ChatID:       key.chatID,
+		NominationID: key.nominationID,
+		Date:         day(key.date),
+		CreatedAt:    s.timestamp(),
+	}
+}
@@ -0,0 +1,75 @@
+// Package memory содержит потокобезопасные реализации репозиториев в памяти.
+// Они проходят тот же контракт, что и репозитории базы данных, и используются
+// в тестах обработчиков и в режиме --dry-run, когда бот не должен писать на диск.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// dateLayout формат даты выбора, как в колонке DATE базы данных
+const dateLayout = "2006-01-02"
+
+// pickKey уникальный ключ выбора: одна запись на дату в номинации чата
+type pickKey struct {
+	chatID       int64
+	nominationID int64
+	date         string
+}
+
+// aliasKey ключ алиаса команды в чате
+type aliasKey struct {
+	chatID int64
+	alias  string
+}
+
+// Store общее хранилище репозиториев в памяти. Репозитории одного хранилища
+// видят данные друг друга: история присоединяет участников и номинации,
+// а удаление номинации удаляет ее выборы.
+type Store struct {
+	mu               sync.RWMutex
+	users            map[int64]domain.User
+	picks            map[pickKey]domain.PersonOfTheDay
+	lastPickID       int
+	settings         map[int64]domain.ChatSettings
+	nominations      map[int64]domain.Nomination
+	lastNominationID int64
+	aliases          map[aliasKey]domain.CommandAlias
+	tokens           map[int64]domain.APIToken
+	clock            clock.Clock
+}
+
+// NewStore создает пустое хранилище в памяти. Время создания записей берется
+// из clk; nil — системные часы.
+func NewStore(clk clock.Clock) *Store {
+	return &Store{
+		users:       make(map[int64]domain.User),
+		picks:       make(map[pickKey]domain.PersonOfTheDay),
+		settings:    make(map[int64]domain.ChatSettings),
+		nominations: make(map[int64]domain.Nomination),
+		aliases:     make(map[aliasKey]domain.CommandAlias),
+		tokens:      make(map[int64]domain.APIToken),
+		clock:       clock.OrSystem(clk),
+	}
+}
+
+// Ping всегда успешен: хранилище в памяти доступно, пока работает процесс
+func (s *Store) Ping(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// timestamp возвращает время записи с точностью CURRENT_TIMESTAMP базы данных
+func (s *Store) timestamp() time.Time {
+	return s.clock.Now().UTC().Truncate(time.Second)
+}
+
+// day приводит дату выбора к дню без времени, как его возвращает база данных
+func day(date string) time.Time {
+	parsed, _ := time.Parse(dateLayout, date)
+	return parsed
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+)
+
+// UserRepositoryImpl реализует UserRepository в памяти
+type UserRepositoryImpl struct {
+	store *Store
+}
+
+// NewUserRepository создает новый экземпляр UserRepository в памяти
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &UserRepositoryImpl{store: store}
+}
+
+// Add добавляет или обновляет пользователя. Пользователь хранит последний чат,
+// в котором он писал.
+func (r *UserRepositoryImpl) Add(user domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user.CreatedAt = r.store.timestamp()
+	if existing, ok := r.store.users[user.ID]; ok {
+		user.CreatedAt = existing.CreatedAt
+	}
+	r.store.users[user.ID] = user
+
+	return nil
+}
+
+// GetByChatID возвращает всех пользователей в чате
+func (r *UserRepositoryImpl) GetByChatID(chatID int64) ([]domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.store.chatUsers(chatID), nil
+}
+
+// GetByID возвращает пользователя чата по ID или nil, если его нет
+func (r *UserRepositoryImpl) GetByID(userID, chatID int64) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[userID]
+	if !ok || user.ChatID != chatID {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// GetByUserID возвращает пользователя по ID в последнем чате, где он писал
+func (r *UserRepositoryImpl) GetByUserID(userID int64) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// chatUsers возвращает пользователей чата по имени. Вызывается под блокировкой.
+func (s *Store) chatUsers(chatID int64) []domain.User {
+	var users []domain.User
+	for _, user := range s.users {
+		if user.ChatID == chatID {
+			users = append(users, user)
+		}
+	}
+	slices.SortFunc(users, func(a, b domain.User) int {
+		return cmp.Or(cmp.Compare(a.FirstName, b.FirstName), cmp.Compare(a.ID, b.ID))
+	})
+	return users
+}
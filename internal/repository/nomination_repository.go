@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// NominationRepositoryImpl реализует NominationRepository
+type NominationRepositoryImpl struct {
+	db *Database
+}
+
+// NewNominationRepository создает новый экземпляр NominationRepository
+func NewNominationRepository(db *Database) NominationRepository {
+	return &NominationRepositoryImpl{db: db}
+}
+
+// Create добавляет номинацию и заполняет ее ID
+func (r *NominationRepositoryImpl) Create(nomination *domain.Nomination) error {
+	query := r.db.psql.Insert("nominations").
+		Columns("chat_id", "command", "title", "emoji", "strategy").
+		Values(nomination.ChatID, nomination.Command, nomination.Title, nomination.Emoji, string(nomination.Strategy)).
+		Suffix("RETURNING id")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build query: %w", err)
+	}
+
+	// LastInsertId не поддерживается драйвером PostgreSQL, поэтому ID читается через RETURNING
+	if err := r.db.conn.QueryRow(sqlStr, args...).Scan(&nomination.ID); err != nil {
+		return fmt.Errorf("failed to create nomination: %w", err)
+	}
+
+	return nil
+}
+
+// GetByChatID возвращает все номинации чата
+func (r *NominationRepositoryImpl) GetByChatID(chatID int64) ([]domain.Nomination, error) {
+	query := r.db.psql.Select("id", "chat_id", "command", "title", "emoji", "strategy", "created_at").
+		From("nominations").
+		Where(squirrel.Eq{"chat_id": chatID}).
+		OrderBy("command")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.conn.Query(sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nominations: %w", err)
+	}
+	defer r.db.closeRows(rows)
+
+	var nominations []domain.Nomination
+	for rows.Next() {
+		nomination, err := scanNomination(rows)
+		if err != nil {
+			return nil, err
+		}
+		nominations = append(nominations, *nomination)
+	}
+
+	return nominations, nil
+}
+
+// GetByCommand возвращает номинацию чата по команде или nil, если ее нет
+func (r *NominationRepositoryImpl) GetByCommand(chatID int64, command string) (*domain.Nomination, error) {
+	query := r.db.psql.Select("id", "chat_id", "command", "title", "emoji", "strategy", "created_at").
+		From("nominations").
+		Where(squirrel.Eq{"chat_id": chatID, "command": command})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	nomination, err := scanNomination(r.db.conn.QueryRow(sqlStr, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return nomination, nil
+}
+
+// Delete удаляет номинацию чата вместе с ее историей
+func (r *NominationRepositoryImpl) Delete(chatID int64, command string) error {
+	nomination, err := r.GetByCommand(chatID, command)
+	if err != nil {
+		return err
+	}
+	if nomination == nil {
+		return nil
+	}
+
+	tx, err := r.db.conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	queries := []squirrel.Sqlizer{
+		r.db.psql.Delete("person_of_the_day").Where(squirrel.Eq{"chat_id": chatID, "nomination_id": nomination.ID}),
+		r.db.psql.Delete("nominations").Where(squirrel.Eq{"id": nomination.ID}),
+	}
+	for _, query := range queries {
+		sqlStr, args, err := query.ToSql()
+		if err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to build query: %w", err)
+		}
+
+		if _, err := tx.Exec(sqlStr, args...); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("failed to delete nomination: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to delete nomination: %w", err)
+	}
+
+	return nil
+}
+
+// scanNomination считывает номинацию из строки результата
+func scanNomination(row squirrel.RowScanner) (*domain.Nomination, error) {
+	var nomination domain.Nomination
+	var emoji sql.NullString
+	var strategy string
+
+	err := row.Scan(
+		&nomination.ID,
+		&nomination.ChatID,
+		&nomination.Command,
+		&nomination.Title,
+		&emoji,
+		&strategy,
+		&nomination.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to scan nomination: %w", err)
+	}
+
+	if emoji.Valid {
+		nomination.Emoji = emoji.String
+	}
+	nomination.Strategy = domain.SelectionStrategy(strategy)
+
+	return &nomination, nil
+}
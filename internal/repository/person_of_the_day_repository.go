@@ -19,13 +19,14 @@ func NewPersonOfTheDayRepository(db *Database) PersonOfTheDayRepository {
 	return &PersonOfTheDayRepositoryImpl{db: db}
 }
 
-// Set устанавливает человека дня
-func (r *PersonOfTheDayRepositoryImpl) Set(userID, chatID int64, date time.Time) error {
+// Set устанавливает человека дня в номинации
+func (r *PersonOfTheDayRepositoryImpl) Set(userID, chatID, nominationID int64, date time.Time) error {
 	dateStr := date.Format("2006-01-02")
 
-	query := r.db.psql.Replace("person_of_the_day").
-		Columns("user_id", "chat_id", "date").
-		Values(userID, chatID, dateStr)
+	query := r.db.psql.Insert("person_of_the_day").
+		Columns("user_id", "chat_id", "nomination_id", "date").
+		Values(userID, chatID, nominationID, dateStr).
+		Suffix("ON CONFLICT(chat_id, nomination_id, date) DO UPDATE SET user_id = excluded.user_id")
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
@@ -40,14 +41,71 @@ func (r *PersonOfTheDayRepositoryImpl) Set(userID, chatID int64, date time.Time)
 	return nil
 }
 
-// GetByDate возвращает человека дня на указанную дату
-func (r *PersonOfTheDayRepositoryImpl) GetByDate(chatID int64, date time.Time) (*domain.User, error) {
+// Insert добавляет выбор, если на дату в номинации его еще нет.
+// Возвращает false, если дата уже занята: существующий выбор не перезаписывается.
+func (r *PersonOfTheDayRepositoryImpl) Insert(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	dateStr := date.Format("2006-01-02")
+
+	query := r.db.psql.Insert("person_of_the_day").
+		Columns("user_id", "chat_id", "nomination_id", "date").
+		Values(userID, chatID, nominationID, dateStr).
+		Suffix("ON CONFLICT(chat_id, nomination_id, date) DO NOTHING")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to insert person of the day: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get inserted rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// ReplaceStale заменяет выбор на дату, если его участник больше не состоит в чате.
+// Возвращает false, если выбора нет или он указывает на участника чата: такой выбор
+// мог сохранить параллельный вызов, и он не перезаписывается.
+func (r *PersonOfTheDayRepositoryImpl) ReplaceStale(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	dateStr := date.Format("2006-01-02")
+
+	query := r.db.psql.Update("person_of_the_day").
+		Set("user_id", userID).
+		Where(squirrel.Eq{"chat_id": chatID, "nomination_id": nominationID, "date": dateStr}).
+		Where("NOT EXISTS (SELECT 1 FROM users u WHERE u.id = person_of_the_day.user_id AND u.chat_id = person_of_the_day.chat_id)")
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return false, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	result, err := r.db.conn.Exec(sqlStr, args...)
+	if err != nil {
+		return false, fmt.Errorf("failed to replace person of the day: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get replaced rows: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// GetByDate возвращает человека дня в номинации на указанную дату
+func (r *PersonOfTheDayRepositoryImpl) GetByDate(chatID, nominationID int64, date time.Time) (*domain.User, error) {
 	dateStr := date.Format("2006-01-02")
 
 	query := r.db.psql.Select("u.id", "u.username", "u.first_name", "u.last_name", "u.chat_id", "u.created_at").
 		From("users u").
 		Join("person_of_the_day p ON u.id = p.user_id AND u.chat_id = p.chat_id").
-		Where(squirrel.Eq{"p.chat_id": chatID, "p.date": dateStr})
+		Where(squirrel.Eq{"p.chat_id": chatID, "p.nomination_id": nominationID, "p.date": dateStr})
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
@@ -78,14 +136,14 @@ func (r *PersonOfTheDayRepositoryImpl) GetByDate(chatID int64, date time.Time) (
 	return &user, nil
 }
 
-// GetUserStats возвращает статистику пользователей
-func (r *PersonOfTheDayRepositoryImpl) GetUserStats(chatID int64) ([]domain.UserStats, error) {
+// GetUserStats возвращает статистику пользователей в номинации
+func (r *PersonOfTheDayRepositoryImpl) GetUserStats(chatID, nominationID int64) ([]domain.UserStats, error) {
 	query := r.db.psql.Select(
 		"u.id", "u.username", "u.first_name", "u.last_name", "u.chat_id", "u.created_at",
 		"COALESCE(COUNT(p.id), 0) as count",
 	).
 		From("users u").
-		LeftJoin("person_of_the_day p ON u.id = p.user_id AND u.chat_id = p.chat_id").
+		LeftJoin("person_of_the_day p ON u.id = p.user_id AND u.chat_id = p.chat_id AND p.nomination_id = ?", nominationID).
 		Where(squirrel.Eq{"u.chat_id": chatID}).
 		GroupBy("u.id", "u.username", "u.first_name", "u.last_name", "u.chat_id", "u.created_at").
 		OrderBy("count DESC", "u.first_name")
@@ -99,11 +157,7 @@ func (r *PersonOfTheDayRepositoryImpl) GetUserStats(chatID int64) ([]domain.User
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user stats: %w", err)
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			fmt.Printf("Ошибка закрытия rows: %v\n", err)
-		}
-	}()
+	defer r.db.closeRows(rows)
 
 	var stats []domain.UserStats
 	for rows.Next() {
@@ -136,3 +190,80 @@ func (r *PersonOfTheDayRepositoryImpl) GetUserStats(chatID int64) ([]domain.User
 
 	return stats, nil
 }
+
+// GetHistory возвращает все выборы чата во всех номинациях по порядку дат
+func (r *PersonOfTheDayRepositoryImpl) GetHistory(chatID int64) ([]domain.PersonOfTheDayRecord, error) {
+	return r.history(r.historyQuery().Where(squirrel.Eq{"p.chat_id": chatID}))
+}
+
+// GetAllHistory возвращает выборы всех чатов
+func (r *PersonOfTheDayRepositoryImpl) GetAllHistory() ([]domain.PersonOfTheDayRecord, error) {
+	return r.history(r.historyQuery())
+}
+
+// historyQuery строит запрос истории выборов с участниками и номинациями.
+// Участник присоединяется только по ID: запись пользователя хранит последний чат,
+// в котором он писал, а имя нужно во всех чатах.
+func (r *PersonOfTheDayRepositoryImpl) historyQuery() squirrel.SelectBuilder {
+	return r.db.psql.Select(
+		"p.id", "p.user_id", "p.chat_id", "p.nomination_id", "p.date", "p.created_at",
+		"u.username", "u.first_name", "u.last_name", "n.command",
+	).
+		From("person_of_the_day p").
+		LeftJoin("users u ON u.id = p.user_id").
+		LeftJoin("nominations n ON n.id = p.nomination_id").
+		OrderBy("p.chat_id", "p.date", "p.nomination_id")
+}
+
+// history выполняет запрос истории выборов
+func (r *PersonOfTheDayRepositoryImpl) history(query squirrel.SelectBuilder) ([]domain.PersonOfTheDayRecord, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.conn.Query(sqlStr, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get history: %w", err)
+	}
+	defer r.db.closeRows(rows)
+
+	var records []domain.PersonOfTheDayRecord
+	for rows.Next() {
+		var record domain.PersonOfTheDayRecord
+		var username, firstName, lastName, command sql.NullString
+
+		err := rows.Scan(
+			&record.ID,
+			&record.UserID,
+			&record.ChatID,
+			&record.NominationID,
+			&record.Date,
+			&record.CreatedAt,
+			&username,
+			&firstName,
+			&lastName,
+			&command,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan history: %w", err)
+		}
+
+		record.User = domain.User{
+			ID:        record.UserID,
+			Username:  username.String,
+			FirstName: firstName.String,
+			LastName:  lastName.String,
+			ChatID:    record.ChatID,
+		}
+
+		record.Nomination = command.String
+		if record.NominationID == domain.DefaultNominationID {
+			record.Nomination = domain.DefaultNominationCommand
+		}
+
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
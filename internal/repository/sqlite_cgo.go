@@ -0,0 +1,10 @@
+//go:build cgo && !purego
+
+package repository
+
+import (
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DefaultSQLiteDriver драйвер SQLite по умолчанию: в сборке с CGO — mattn/go-sqlite3
+const DefaultSQLiteDriver = DriverSQLite
@@ -0,0 +1,6 @@
+//go:build !cgo || purego
+
+package repository
+
+// DefaultSQLiteDriver драйвер SQLite по умолчанию: без CGO или с тегом purego — modernc.org/sqlite
+const DefaultSQLiteDriver = DriverSQLitePure
@@ -20,9 +20,11 @@ func NewUserRepository(db *Database) UserRepository {
 
 // Add добавляет или обновляет пользователя
 func (r *UserRepositoryImpl) Add(user domain.User) error {
-	query := r.db.psql.Replace("users").
+	query := r.db.psql.Insert("users").
 		Columns("id", "username", "first_name", "last_name", "chat_id").
-		Values(user.ID, user.Username, user.FirstName, user.LastName, user.ChatID)
+		Values(user.ID, user.Username, user.FirstName, user.LastName, user.ChatID).
+		Suffix("ON CONFLICT(id) DO UPDATE SET username = excluded.username, first_name = excluded.first_name, " +
+			"last_name = excluded.last_name, chat_id = excluded.chat_id")
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
@@ -53,12 +55,7 @@ func (r *UserRepositoryImpl) GetByChatID(chatID int64) ([]domain.User, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get chat users: %w", err)
 	}
-	defer func() {
-		if err := rows.Close(); err != nil {
-			// Логируем ошибку, но не прерываем выполнение
-			fmt.Printf("Ошибка закрытия rows: %v\n", err)
-		}
-	}()
+	defer r.db.closeRows(rows)
 
 	var users []domain.User
 	for rows.Next() {
@@ -118,3 +115,38 @@ func (r *UserRepositoryImpl) GetByID(userID, chatID int64) (*domain.User, error)
 
 	return &user, nil
 }
+
+// GetByUserID возвращает пользователя вместе с чатом, в котором он писал последним
+func (r *UserRepositoryImpl) GetByUserID(userID int64) (*domain.User, error) {
+	query := r.db.psql.Select("id", "username", "first_name", "last_name", "chat_id", "created_at").
+		From("users").
+		Where(squirrel.Eq{"id": userID})
+
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	row := r.db.conn.QueryRow(sqlStr, args...)
+
+	var user domain.User
+	var username sql.NullString
+	var lastName sql.NullString
+
+	err = row.Scan(&user.ID, &username, &user.FirstName, &lastName, &user.ChatID, &user.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if username.Valid {
+		user.Username = username.String
+	}
+	if lastName.Valid {
+		user.LastName = lastName.String
+	}
+
+	return &user, nil
+}
@@ -0,0 +1,453 @@
+// Package telegramtest локальный поддельный сервер Telegram Bot API для тестов.
+// telebot подключается к нему через Settings.URL: сервер отдает обновления
+// через getUpdates, запоминает отправленные ботом сообщения и вызовы методов,
+// отвечает на getChatMember заданными ролями и отдает загруженные файлы.
+package telegramtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/telebot.v3"
+)
+
+// maxPollTimeout ограничивает ожидание getUpdates, чтобы остановка бота не зависала
+const maxPollTimeout = 5 * time.Second
+
+// Call вызов метода Bot API: параметры запроса и загруженные файлы
+type Call struct {
+	Params map[string]any
+	Files  map[string][]byte
+}
+
+// Param возвращает параметр вызова строкой
+func (c Call) Param(name string) string {
+	switch value := c.Params[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+}
+
+// File возвращает загруженный файл. telebot отправляет файлы из io.Reader без имени,
+// и multipart считает такую часть обычным полем формы.
+func (c Call) File(name string) []byte {
+	if content, ok := c.Files[name]; ok {
+		return content
+	}
+	if value, ok := c.Params[name].(string); ok {
+		return []byte(value)
+	}
+	return nil
+}
+
+// Message сообщение, отправленное ботом через sendMessage, sendPhoto или sendDocument
+type Message struct {
+	Method   string
+	ChatID   int64
+	ThreadID int
+	ReplyTo  int
+	// Text текст сообщения или подпись к файлу
+	Text     string
+	FileName string
+	File     []byte
+}
+
+// apiError описание ошибки в ответе Bot API
+type apiError string
+
+func (e apiError) Error() string {
+	return string(e)
+}
+
+// memberKey участник конкретного чата
+type memberKey struct {
+	chatID int64
+	userID int64
+}
+
+// Server поддельный Telegram Bot API. Методы безопасны для вызова из нескольких горутин.
+type Server struct {
+	*httptest.Server
+
+	// Me пользователь бота, которого возвращает getMe
+	Me telebot.User
+
+	mu        sync.Mutex
+	updates   []telebot.Update
+	updateID  int
+	messageID int
+	fileID    int
+	wake      chan struct{}
+	closed    bool
+	calls     map[string][]Call
+	members   map[memberKey]telebot.MemberStatus
+	files     map[string][]byte
+	sent      chan Message
+}
+
+// NewServer запускает поддельный Bot API на свободном локальном порту.
+// Сервер нужно остановить вызовом Close.
+func NewServer() *Server {
+	s := &Server{
+		Me:      telebot.User{ID: 1, IsBot: true, FirstName: "Day", Username: "DayBot"},
+		wake:    make(chan struct{}),
+		calls:   make(map[string][]Call),
+		members: make(map[memberKey]telebot.MemberStatus),
+		files:   make(map[string][]byte),
+		sent:    make(chan Message, 100),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close будит ожидающие getUpdates и останавливает сервер
+func (s *Server) Close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.wake)
+	}
+	s.mu.Unlock()
+
+	s.Server.Close()
+}
+
+// Bot создает клиент telebot с long polling, подключенный к серверу.
+// Обновления обрабатываются последовательно, как их отправил сервер.
+func (s *Server) Bot() (*telebot.Bot, error) {
+	return telebot.NewBot(telebot.Settings{
+		URL:         s.URL,
+		Token:       "test",
+		Poller:      &telebot.LongPoller{Timeout: time.Second},
+		Synchronous: true,
+	})
+}
+
+// Calls возвращает все вызовы метода API
+func (s *Server) Calls(method string) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls[method]...)
+}
+
+// SetMemberStatus задает роль участника чата для getChatMember; по умолчанию участник — member
+func (s *Server) SetMemberStatus(chatID, userID int64, status telebot.MemberStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.members[memberKey{chatID: chatID, userID: userID}] = status
+}
+
+// AddUpdate ставит обновление в очередь getUpdates и возвращает его ID
+func (s *Server) AddUpdate(update telebot.Update) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.updateID++
+	update.ID = s.updateID
+	s.updates = append(s.updates, update)
+	s.notify()
+	return update.ID
+}
+
+// SendText отправляет боту текстовое сообщение от участника.
+// Команды размечаются сущностью bot_command, как это делает Telegram.
+func (s *Server) SendText(chat *telebot.Chat, from *telebot.User, text string) *telebot.Message {
+	message := s.newMessage(chat, from)
+	message.Text = text
+	if strings.HasPrefix(text, "/") {
+		command, _, _ := strings.Cut(text, " ")
+		message.Entities = telebot.Entities{{Type: telebot.EntityCommand, Length: len([]rune(command))}}
+	}
+
+	s.AddUpdate(telebot.Update{Message: message})
+	return message
+}
+
+// SendDocument отправляет боту файл с подписью от участника; бот может скачать его через getFile
+func (s *Server) SendDocument(chat *telebot.Chat, from *telebot.User, caption, fileName string, content []byte) *telebot.Message {
+	message := s.newMessage(chat, from)
+	message.Caption = caption
+	if strings.HasPrefix(caption, "/") {
+		command, _, _ := strings.Cut(caption, " ")
+		message.CaptionEntities = telebot.Entities{{Type: telebot.EntityCommand, Length: len([]rune(command))}}
+	}
+	message.Document = &telebot.Document{
+		File:     telebot.File{FileID: s.addFile(content), FileSize: int64(len(content))},
+		FileName: fileName,
+	}
+
+	s.AddUpdate(telebot.Update{Message: message})
+	return message
+}
+
+// Next ждет следующее отправленное ботом сообщение
+func (s *Server) Next(timeout time.Duration) (Message, error) {
+	select {
+	case message := <-s.sent:
+		return message, nil
+	case <-time.After(timeout):
+		return Message{}, fmt.Errorf("no message from bot in %s", timeout)
+	}
+}
+
+// newMessage создает входящее сообщение с очередным ID
+func (s *Server) newMessage(chat *telebot.Chat, from *telebot.User) *telebot.Message {
+	s.mu.Lock()
+	s.messageID++
+	id := s.messageID
+	s.mu.Unlock()
+
+	return &telebot.Message{ID: id, Chat: chat, Sender: from, Unixtime: time.Now().Unix()}
+}
+
+// addFile сохраняет содержимое файла и возвращает его file_id
+func (s *Server) addFile(content []byte) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fileID++
+	id := "file" + strconv.Itoa(s.fileID)
+	s.files[id] = content
+	return id
+}
+
+// notify будит ожидающие getUpdates; вызывается под s.mu
+func (s *Server) notify() {
+	if !s.closed {
+		close(s.wake)
+		s.wake = make(chan struct{})
+	}
+}
+
+// serveHTTP разбирает /bot<token>/<method> и /file/bot<token>/<file_id>
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, "/file/") {
+		s.serveFile(w, r)
+		return
+	}
+
+	method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	call, err := parseCall(r)
+	if err != nil {
+		reply(w, nil, apiError("Bad Request: "+err.Error()))
+		return
+	}
+
+	s.mu.Lock()
+	s.calls[method] = append(s.calls[method], call)
+	s.mu.Unlock()
+
+	result, err := s.handle(method, call)
+	reply(w, result, err)
+}
+
+// handle выполняет метод API и возвращает его результат
+func (s *Server) handle(method string, call Call) (any, error) {
+	switch method {
+	case "getMe":
+		return s.Me, nil
+	case "getUpdates":
+		return s.getUpdates(call), nil
+	case "sendMessage":
+		return s.send(method, call, "", nil), nil
+	case "sendPhoto":
+		return s.send(method, call, "photo", map[string]any{"photo": []map[string]any{{"file_id": s.addFile(call.File("photo")), "width": 1, "height": 1}}}), nil
+	case "sendDocument":
+		return s.send(method, call, "document", map[string]any{"document": map[string]any{"file_id": s.addFile(call.File("document")), "file_name": call.Param("file_name")}}), nil
+	case "getChatMember":
+		return s.getChatMember(call)
+	case "getFile":
+		return s.getFile(call)
+	default:
+		return true, nil
+	}
+}
+
+// getUpdates отдает обновления начиная с offset, при пустой очереди ждет timeout секунд.
+// Обновления до offset считаются полученными и удаляются.
+func (s *Server) getUpdates(call Call) []telebot.Update {
+	offset, _ := strconv.Atoi(call.Param("offset"))
+	timeout, _ := strconv.Atoi(call.Param("timeout"))
+	deadline := time.After(min(time.Duration(timeout)*time.Second, maxPollTimeout))
+
+	for {
+		s.mu.Lock()
+		pending := s.updates[:0]
+		for _, update := range s.updates {
+			if update.ID >= offset {
+				pending = append(pending, update)
+			}
+		}
+		s.updates = pending
+		wake, closed := s.wake, s.closed
+		s.mu.Unlock()
+
+		if len(pending) > 0 || closed {
+			return append([]telebot.Update{}, pending...)
+		}
+
+		select {
+		case <-wake:
+		case <-deadline:
+			return []telebot.Update{}
+		}
+	}
+}
+
+// send запоминает сообщение бота и возвращает его так, как вернул бы Telegram
+func (s *Server) send(method string, call Call, fileField string, media map[string]any) map[string]any {
+	chatID, _ := strconv.ParseInt(call.Param("chat_id"), 10, 64)
+	threadID, _ := strconv.Atoi(call.Param("message_thread_id"))
+	replyTo, _ := strconv.Atoi(call.Param("reply_to_message_id"))
+
+	message := Message{
+		Method:   method,
+		ChatID:   chatID,
+		ThreadID: threadID,
+		ReplyTo:  replyTo,
+		Text:     call.Param("text"),
+		FileName: call.Param("file_name"),
+		File:     call.File(fileField),
+	}
+	if fileField != "" {
+		message.Text = call.Param("caption")
+	}
+
+	s.mu.Lock()
+	s.messageID++
+	id := s.messageID
+	s.mu.Unlock()
+
+	select {
+	case s.sent <- message:
+	default:
+	}
+
+	result := map[string]any{
+		"message_id": id,
+		"date":       time.Now().Unix(),
+		"chat":       map[string]any{"id": chatID},
+	}
+	if fileField == "" {
+		result["text"] = message.Text
+	} else {
+		result["caption"] = message.Text
+	}
+	for key, value := range media {
+		result[key] = value
+	}
+	return result
+}
+
+// getChatMember возвращает роль участника, заданную SetMemberStatus
+func (s *Server) getChatMember(call Call) (any, error) {
+	chatID, err := strconv.ParseInt(call.Param("chat_id"), 10, 64)
+	if err != nil {
+		return nil, apiError("Bad Request: chat not found")
+	}
+	userID, err := strconv.ParseInt(call.Param("user_id"), 10, 64)
+	if err != nil {
+		return nil, apiError("Bad Request: invalid user_id specified")
+	}
+
+	s.mu.Lock()
+	status, ok := s.members[memberKey{chatID: chatID, userID: userID}]
+	s.mu.Unlock()
+	if !ok {
+		status = telebot.Member
+	}
+
+	return map[string]any{"user": map[string]any{"id": userID}, "status": status}, nil
+}
+
+// getFile возвращает путь к файлу; путь совпадает с file_id
+func (s *Server) getFile(call Call) (any, error) {
+	id := call.Param("file_id")
+
+	s.mu.Lock()
+	content, ok := s.files[id]
+	s.mu.Unlock()
+	if !ok {
+		return nil, apiError("Bad Request: invalid file_id")
+	}
+
+	return map[string]any{"file_id": id, "file_size": len(content), "file_path": id}, nil
+}
+
+// serveFile отдает содержимое файла по пути из getFile
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	content, ok := s.files[id]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	_, _ = w.Write(content)
+}
+
+// parseCall читает параметры из JSON или multipart/form-data
+func parseCall(r *http.Request) (Call, error) {
+	call := Call{Params: make(map[string]any), Files: make(map[string][]byte)}
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := json.NewDecoder(r.Body).Decode(&call.Params); err != nil && err != io.EOF {
+			return call, err
+		}
+		return call, nil
+	}
+
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return call, err
+	}
+	for name, values := range r.MultipartForm.Value {
+		call.Params[name] = values[0]
+	}
+	for name, headers := range r.MultipartForm.File {
+		content, err := readFile(headers[0])
+		if err != nil {
+			return call, err
+		}
+		call.Files[name] = content
+		if call.Params["file_name"] == nil || call.Params["file_name"] == "" {
+			call.Params["file_name"] = headers[0].Filename
+		}
+	}
+	return call, nil
+}
+
+// readFile читает загруженный файл multipart-запроса
+func readFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// reply пишет ответ в формате Bot API: {"ok": true, "result": ...} или описание ошибки
+func reply(w http.ResponseWriter, result any, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error_code": http.StatusBadRequest, "description": err.Error()})
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": result})
+}
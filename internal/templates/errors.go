@@ -0,0 +1,41 @@
+package templates
+
+// ErrorContext описывает, при каком действии произошла ошибка. Текст контекста
+// берется из шаблонов языка чата, поэтому сообщение об ошибке не смешивает языки.
+type ErrorContext string
+
+const (
+	ErrorDatabase         ErrorContext = "database"
+	ErrorDraw             ErrorContext = "draw"
+	ErrorStats            ErrorContext = "stats"
+	ErrorStatsImage       ErrorContext = "stats_image"
+	ErrorInfo             ErrorContext = "info"
+	ErrorHistory          ErrorContext = "history"
+	ErrorExport           ErrorContext = "export"
+	ErrorFileDownload     ErrorContext = "file_download"
+	ErrorImport           ErrorContext = "import"
+	ErrorLanguage         ErrorContext = "language"
+	ErrorTitle            ErrorContext = "title"
+	ErrorNominations      ErrorContext = "nominations"
+	ErrorNomination       ErrorContext = "nomination"
+	ErrorNominationCreate ErrorContext = "nomination_create"
+	ErrorNominationDelete ErrorContext = "nomination_delete"
+	ErrorAliases          ErrorContext = "aliases"
+	ErrorAlias            ErrorContext = "alias"
+	ErrorAliasSave        ErrorContext = "alias_save"
+	ErrorAliasDelete      ErrorContext = "alias_delete"
+	ErrorTokenGet         ErrorContext = "token_get"
+	ErrorTokenCreate      ErrorContext = "token_create"
+	ErrorTokenSave        ErrorContext = "token_save"
+	ErrorTokenDelete      ErrorContext = "token_delete"
+)
+
+// ErrorContexts возвращает все контексты ошибок
+func ErrorContexts() []ErrorContext {
+	return []ErrorContext{
+		ErrorDatabase, ErrorDraw, ErrorStats, ErrorStatsImage, ErrorInfo, ErrorHistory, ErrorExport,
+		ErrorFileDownload, ErrorImport, ErrorLanguage, ErrorTitle, ErrorNominations, ErrorNomination,
+		ErrorNominationCreate, ErrorNominationDelete, ErrorAliases, ErrorAlias, ErrorAliasSave,
+		ErrorAliasDelete, ErrorTokenGet, ErrorTokenCreate, ErrorTokenSave, ErrorTokenDelete,
+	}
+}
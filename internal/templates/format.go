@@ -0,0 +1,184 @@
+package templates
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Типы плейсхолдеров шаблонов.
+// Синтаксис: {{имя}} или {{имя|тип:аргументы}}, например
+// {{count|plural:раз,раза,раз}}, {{date|date:short}}, {{count|number}}, {{avg|number:2}}.
+const (
+	placeholderText   = ""
+	placeholderPlural = "plural"
+	placeholderDate   = "date"
+	placeholderNumber = "number"
+)
+
+// LocaleFormat описывает правила форматирования значений для языка
+type LocaleFormat struct {
+	Plural             PluralRule
+	PluralForms        int
+	ThousandsSeparator string
+	DecimalSeparator   string
+	DateLayouts        map[string]string
+}
+
+// placeholder представляет разобранный плейсхолдер шаблона
+type placeholder struct {
+	name      string
+	kind      string
+	forms     []string
+	layout    string
+	precision int
+}
+
+// parsePlaceholder разбирает и проверяет плейсхолдер для указанного формата языка
+func parsePlaceholder(tag string, format LocaleFormat) (placeholder, error) {
+	name, spec, typed := strings.Cut(tag, "|")
+	p := placeholder{name: strings.TrimSpace(name)}
+	if p.name == "" {
+		return p, fmt.Errorf("placeholder {{%s}} has empty name", tag)
+	}
+	if !typed {
+		return p, nil
+	}
+
+	kind, args, _ := strings.Cut(spec, ":")
+	p.kind = strings.TrimSpace(kind)
+
+	switch p.kind {
+	case placeholderPlural:
+		for _, form := range strings.Split(args, ",") {
+			p.forms = append(p.forms, strings.TrimSpace(form))
+		}
+		if len(p.forms) != format.PluralForms {
+			return p, fmt.Errorf("placeholder {{%s}} needs %d plural forms, got %d", tag, format.PluralForms, len(p.forms))
+		}
+	case placeholderDate:
+		layout := args
+		if named, ok := format.DateLayouts[layout]; ok {
+			layout = named
+		}
+		if err := validateDateLayout(layout); err != nil {
+			return p, fmt.Errorf("placeholder {{%s}}: %w", tag, err)
+		}
+		p.layout = layout
+	case placeholderNumber:
+		if args != "" {
+			precision, err := strconv.Atoi(args)
+			if err != nil || precision < 0 || precision > 6 {
+				return p, fmt.Errorf("placeholder {{%s}} has invalid precision %q", tag, args)
+			}
+			p.precision = precision
+		}
+	default:
+		return p, fmt.Errorf("placeholder {{%s}} has unknown type %q", tag, p.kind)
+	}
+
+	return p, nil
+}
+
+// validateDateLayout проверяет, что layout содержит элементы даты и разбирается обратно
+func validateDateLayout(layout string) error {
+	if strings.TrimSpace(layout) == "" {
+		return fmt.Errorf("empty date layout")
+	}
+
+	reference := time.Date(2009, time.November, 17, 21, 34, 56, 0, time.UTC)
+	formatted := reference.Format(layout)
+	if formatted == layout {
+		return fmt.Errorf("date layout %q has no date elements", layout)
+	}
+	if _, err := time.Parse(layout, formatted); err != nil {
+		return fmt.Errorf("invalid date layout %q: %w", layout, err)
+	}
+
+	return nil
+}
+
+// render форматирует значение плейсхолдера
+func (p placeholder) render(data TemplateData, format LocaleFormat) string {
+	value, ok := data[p.name]
+	if !ok {
+		return ""
+	}
+
+	switch p.kind {
+	case placeholderPlural:
+		n, ok := toInt(value)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		return Plural(format.Plural, n, p.forms)
+	case placeholderDate:
+		date, ok := value.(time.Time)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		return date.Format(p.layout)
+	case placeholderNumber:
+		n, ok := toFloat(value)
+		if !ok {
+			return fmt.Sprint(value)
+		}
+		return formatNumber(n, p.precision, format)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// formatNumber форматирует число с разделителями разрядов языка
+func formatNumber(n float64, precision int, format LocaleFormat) string {
+	str := strconv.FormatFloat(math.Abs(n), 'f', precision, 64)
+	intPart, fracPart, _ := strings.Cut(str, ".")
+
+	var result strings.Builder
+	if n < 0 && strings.Trim(str, "0.") != "" {
+		result.WriteString("-")
+	}
+	for i, digit := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			result.WriteString(format.ThousandsSeparator)
+		}
+		result.WriteRune(digit)
+	}
+	if fracPart != "" {
+		result.WriteString(format.DecimalSeparator)
+		result.WriteString(fracPart)
+	}
+
+	return result.String()
+}
+
+// toInt приводит целочисленное значение к int
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int32:
+		return int(v), true
+	case int64:
+		return int(v), true
+	case uint:
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toFloat приводит числовое значение к float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	default:
+		n, ok := toInt(value)
+		return float64(n), ok
+	}
+}
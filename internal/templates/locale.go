@@ -0,0 +1,73 @@
+package templates
+
+import "strings"
+
+// Locale определяет язык сообщений бота
+type Locale string
+
+const (
+	LocaleRu Locale = "ru"
+	LocaleEn Locale = "en"
+	LocaleUk Locale = "uk"
+)
+
+// DefaultLocale используется, если язык чата не задан или не поддерживается
+const DefaultLocale = LocaleRu
+
+// localeDefinition описывает полный набор сообщений одного языка
+type localeDefinition struct {
+	templates map[string]string
+	commands  map[string]string
+	errors    map[ErrorContext]string
+	format    LocaleFormat
+}
+
+// slavicFormat содержит правила форматирования для русского и украинского языков
+var slavicFormat = LocaleFormat{
+	Plural:             pluralSlavic,
+	PluralForms:        3,
+	ThousandsSeparator: "\u00a0",
+	DecimalSeparator:   ",",
+	DateLayouts: map[string]string{
+		"short": "02.01.2006",
+		"long":  "02.01.2006 15:04",
+	},
+}
+
+// englishFormat содержит правила форматирования для английского языка
+var englishFormat = LocaleFormat{
+	Plural:             pluralEnglish,
+	PluralForms:        2,
+	ThousandsSeparator: ",",
+	DecimalSeparator:   ".",
+	DateLayouts: map[string]string{
+		"short": "Jan 2, 2006",
+		"long":  "Jan 2, 2006 3:04 PM",
+	},
+}
+
+// locales содержит все поддерживаемые языки
+var locales = map[Locale]localeDefinition{
+	LocaleRu: {templates: ruTemplates, commands: ruCommands, errors: ruErrorContexts, format: slavicFormat},
+	LocaleEn: {templates: enTemplates, commands: enCommands, errors: enErrorContexts, format: englishFormat},
+	LocaleUk: {templates: ukTemplates, commands: ukCommands, errors: ukErrorContexts, format: slavicFormat},
+}
+
+// SupportedLocales возвращает список поддерживаемых языков
+func SupportedLocales() []Locale {
+	return []Locale{LocaleRu, LocaleEn, LocaleUk}
+}
+
+// ParseLocale разбирает код языка ("en", "uk-UA", "RU") в поддерживаемую локаль
+func ParseLocale(code string) (Locale, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+
+	locale := Locale(code)
+	if _, ok := locales[locale]; !ok {
+		return "", false
+	}
+	return locale, true
+}
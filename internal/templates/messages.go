@@ -2,7 +2,7 @@ package templates
 
 import "fmt"
 
-// Messages содержит все шаблоны сообщений бота
+// Messages содержит все шаблоны сообщений бота на одном языке
 type Messages struct {
 	// Общие сообщения
 	BotGroupOnly   *MessageTemplate
@@ -10,7 +10,15 @@ type Messages struct {
 	ErrorOccurred  *MessageTemplate
 
 	// Справка
-	HelpText *MessageTemplate
+	HelpText              *MessageTemplate
+	HelpCommandEntry      *MessageTemplate
+	HelpAdminCommandEntry *MessageTemplate
+
+	// Описания команд для справки и меню Telegram
+	Commands map[string]*MessageTemplate
+
+	// Контексты сообщений об ошибках
+	ErrorContexts map[ErrorContext]*MessageTemplate
 
 	// Пидор дня
 	PersonAlreadySelected *MessageTemplate
@@ -19,15 +27,127 @@ type Messages struct {
 	PersonInfo            *MessageTemplate
 	NoPersonSelectedToday *MessageTemplate
 
+	// Информация о чате
+	ChatInfo         *MessageTemplate
+	ChatInfoToday    *MessageTemplate
+	ChatInfoNoPerson *MessageTemplate
+
 	// Статистика
 	StatsHeader *MessageTemplate
 	StatsEmpty  *MessageTemplate
 	StatsEntry  *MessageTemplate
+
+	// Язык
+	LanguageName    *MessageTemplate
+	LanguageChanged *MessageTemplate
+	LanguageUsage   *MessageTemplate
+
+	// Название роли
+	DefaultTitle *MessageTemplate
+	AdminOnly    *MessageTemplate
+	TitleChanged *MessageTemplate
+	TitleUsage   *MessageTemplate
+
+	// Номинации
+	NominationsHeader  *MessageTemplate
+	NominationsEmpty   *MessageTemplate
+	NominationEntry    *MessageTemplate
+	NominationUsage    *MessageTemplate
+	NominationAdded    *MessageTemplate
+	NominationRemoved  *MessageTemplate
+	NominationNotFound *MessageTemplate
+	NominationExists   *MessageTemplate
+	NominationLimit    *MessageTemplate
+
+	// Алиасы команд
+	AliasesHeader       *MessageTemplate
+	AliasesEmpty        *MessageTemplate
+	AliasEntry          *MessageTemplate
+	AliasUsage          *MessageTemplate
+	AliasAdded          *MessageTemplate
+	AliasRemoved        *MessageTemplate
+	AliasNotFound       *MessageTemplate
+	AliasExists         *MessageTemplate
+	AliasTargetNotFound *MessageTemplate
+
+	// Выгрузка истории
+	ExportUsage   *MessageTemplate
+	ExportEmpty   *MessageTemplate
+	ExportCaption *MessageTemplate
+
+	// Импорт истории
+	ImportUsage            *MessageTemplate
+	ImportFailed           *MessageTemplate
+	ImportResult           *MessageTemplate
+	ImportUnmatchedHeader  *MessageTemplate
+	ImportUnmatchedEntry   *MessageTemplate
+	ImportUnmatchedMore    *MessageTemplate
+	ImportReasonInvalid    *MessageTemplate
+	ImportReasonUser       *MessageTemplate
+	ImportReasonNomination *MessageTemplate
+
+	// Токены HTTP API
+	APIUsage              *MessageTemplate
+	APIDisabled           *MessageTemplate
+	APITokenStatus        *MessageTemplate
+	APITokenNone          *MessageTemplate
+	APITokenCreated       *MessageTemplate
+	APITokenSent          *MessageTemplate
+	APITokenPrivateFailed *MessageTemplate
+	APITokenRevoked       *MessageTemplate
+
+	// Веб-страница статистики
+	WebLink          *MessageTemplate
+	WebDisabled      *MessageTemplate
+	WebHeading       *MessageTemplate
+	WebLeaderboard   *MessageTemplate
+	WebColumnUser    *MessageTemplate
+	WebColumnWins    *MessageTemplate
+	WebColumnShare   *MessageTemplate
+	WebShare         *MessageTemplate
+	WebEmpty         *MessageTemplate
+	WebRecords       *MessageTemplate
+	WebRecordTotal   *MessageTemplate
+	WebRecordFirst   *MessageTemplate
+	WebRecordStreak  *MessageTemplate
+	WebRecordMonth   *MessageTemplate
+	WebCalendar      *MessageTemplate
+	WebCalendarMonth *MessageTemplate
+	WebWeekdays      *MessageTemplate
+	WebExpires       *MessageTemplate
+	WebLinkInvalid   *MessageTemplate
+
+	// Инлайн-запросы
+	InlineMyStatsTitle     *MessageTemplate
+	InlineMyStats          *MessageTemplate
+	InlineMyStatsNone      *MessageTemplate
+	InlineStatsTitle       *MessageTemplate
+	InlineStatsDescription *MessageTemplate
+	InlineTodayTitle       *MessageTemplate
+	InlineNoChat           *MessageTemplate
+
+	// Картинка статистики
+	StatsImageTitle   *MessageTemplate
+	StatsImageCaption *MessageTemplate
+	StatsImageRest    *MessageTemplate
+
+	locale Locale
 }
 
-// NewMessages создает новый набор сообщений
-func NewMessages() (*Messages, error) {
-	messages := &Messages{}
+// NewMessages создает набор сообщений для указанного языка.
+// Отсутствующие в языке шаблоны берутся из DefaultLocale.
+func NewMessages(locale Locale) (*Messages, error) {
+	definition, ok := locales[locale]
+	if !ok {
+		return nil, fmt.Errorf("locale %s is not supported", locale)
+	}
+	fallback := locales[DefaultLocale]
+
+	messages := &Messages{
+		Commands:      make(map[string]*MessageTemplate),
+		ErrorContexts: make(map[ErrorContext]*MessageTemplate),
+		locale:        locale,
+	}
 
 	// Инициализируем все шаблоны
 	templates := map[string]**MessageTemplate{
@@ -37,7 +157,9 @@ func NewMessages() (*Messages, error) {
 		"ErrorOccurred":  &messages.ErrorOccurred,
 
 		// Справка
-		"HelpText": &messages.HelpText,
+		"HelpText":              &messages.HelpText,
+		"HelpCommandEntry":      &messages.HelpCommandEntry,
+		"HelpAdminCommandEntry": &messages.HelpAdminCommandEntry,
 
 		// Пидор дня
 		"PersonAlreadySelected": &messages.PersonAlreadySelected,
@@ -46,64 +168,124 @@ func NewMessages() (*Messages, error) {
 		"PersonInfo":            &messages.PersonInfo,
 		"NoPersonSelectedToday": &messages.NoPersonSelectedToday,
 
+		// Информация о чате
+		"ChatInfo":         &messages.ChatInfo,
+		"ChatInfoToday":    &messages.ChatInfoToday,
+		"ChatInfoNoPerson": &messages.ChatInfoNoPerson,
+
 		// Статистика
 		"StatsHeader": &messages.StatsHeader,
 		"StatsEmpty":  &messages.StatsEmpty,
 		"StatsEntry":  &messages.StatsEntry,
-	}
-
-	// Шаблоны сообщений
-	messageTemplates := map[string]string{
-		"BotGroupOnly": "Этот бот работает только в группах!",
-
-		"UnknownCommand": "Неизвестная команда. Используйте /help для списка команд.",
-
-		"ErrorOccurred": "Произошла ошибка: {{error}}",
-
-		"HelpText": `🎯 Добро пожаловать в бота "Пидор дня"!
-
-Доступные команды:
-/pidor - Выбрать пидора дня
-/pidorstats - Показать статистику всех участников
-/pidorinfo - Информация о сегодняшнем пидоре дня
-/help - Показать эту справку
-
-Бот работает только в группах и выбирает случайного участника из числа активных пользователей.`,
-
-		"PersonAlreadySelected": `🎯 Пидор дня уже выбран!
-
-👤 {{person}}`,
-
-		"PersonSelected": `🎉 Пидор дня выбран!
-
-🎯 {{person}}
-
-Поздравляем! 🎊`,
-
-		"NoActiveUsers": "В группе нет активных участников для выбора.",
-
-		"PersonInfo": `ℹ️ Информация о сегодняшнем пидоре дня:
-
-👤 {{person}}
-📅 {{date}}`,
 
-		"NoPersonSelectedToday": "Сегодня пидор дня еще не выбран. Используйте /pidor для выбора!",
-
-		"StatsHeader": "📊 Статистика \"Пидор дня\":\n\n",
-
-		"StatsEmpty": "В этой группе пока нет статистики.",
-
-		"StatsEntry": "{{position}} {{person}} - {{count}} раз\n",
+		// Язык
+		"LanguageName":    &messages.LanguageName,
+		"LanguageChanged": &messages.LanguageChanged,
+		"LanguageUsage":   &messages.LanguageUsage,
+
+		// Название роли
+		"DefaultTitle": &messages.DefaultTitle,
+		"AdminOnly":    &messages.AdminOnly,
+		"TitleChanged": &messages.TitleChanged,
+		"TitleUsage":   &messages.TitleUsage,
+
+		// Номинации
+		"NominationsHeader":  &messages.NominationsHeader,
+		"NominationsEmpty":   &messages.NominationsEmpty,
+		"NominationEntry":    &messages.NominationEntry,
+		"NominationUsage":    &messages.NominationUsage,
+		"NominationAdded":    &messages.NominationAdded,
+		"NominationRemoved":  &messages.NominationRemoved,
+		"NominationNotFound": &messages.NominationNotFound,
+		"NominationExists":   &messages.NominationExists,
+		"NominationLimit":    &messages.NominationLimit,
+
+		// Алиасы команд
+		"AliasesHeader":       &messages.AliasesHeader,
+		"AliasesEmpty":        &messages.AliasesEmpty,
+		"AliasEntry":          &messages.AliasEntry,
+		"AliasUsage":          &messages.AliasUsage,
+		"AliasAdded":          &messages.AliasAdded,
+		"AliasRemoved":        &messages.AliasRemoved,
+		"AliasNotFound":       &messages.AliasNotFound,
+		"AliasExists":         &messages.AliasExists,
+		"AliasTargetNotFound": &messages.AliasTargetNotFound,
+
+		// Выгрузка истории
+		"ExportUsage":   &messages.ExportUsage,
+		"ExportEmpty":   &messages.ExportEmpty,
+		"ExportCaption": &messages.ExportCaption,
+
+		// Импорт истории
+		"ImportUsage":            &messages.ImportUsage,
+		"ImportFailed":           &messages.ImportFailed,
+		"ImportResult":           &messages.ImportResult,
+		"ImportUnmatchedHeader":  &messages.ImportUnmatchedHeader,
+		"ImportUnmatchedEntry":   &messages.ImportUnmatchedEntry,
+		"ImportUnmatchedMore":    &messages.ImportUnmatchedMore,
+		"ImportReasonInvalid":    &messages.ImportReasonInvalid,
+		"ImportReasonUser":       &messages.ImportReasonUser,
+		"ImportReasonNomination": &messages.ImportReasonNomination,
+
+		// Токены HTTP API
+		"APIUsage":              &messages.APIUsage,
+		"APIDisabled":           &messages.APIDisabled,
+		"APITokenStatus":        &messages.APITokenStatus,
+		"APITokenNone":          &messages.APITokenNone,
+		"APITokenCreated":       &messages.APITokenCreated,
+		"APITokenSent":          &messages.APITokenSent,
+		"APITokenPrivateFailed": &messages.APITokenPrivateFailed,
+		"APITokenRevoked":       &messages.APITokenRevoked,
+
+		// Веб-страница статистики
+		"WebLink":          &messages.WebLink,
+		"WebDisabled":      &messages.WebDisabled,
+		"WebHeading":       &messages.WebHeading,
+		"WebLeaderboard":   &messages.WebLeaderboard,
+		"WebColumnUser":    &messages.WebColumnUser,
+		"WebColumnWins":    &messages.WebColumnWins,
+		"WebColumnShare":   &messages.WebColumnShare,
+		"WebShare":         &messages.WebShare,
+		"WebEmpty":         &messages.WebEmpty,
+		"WebRecords":       &messages.WebRecords,
+		"WebRecordTotal":   &messages.WebRecordTotal,
+		"WebRecordFirst":   &messages.WebRecordFirst,
+		"WebRecordStreak":  &messages.WebRecordStreak,
+		"WebRecordMonth":   &messages.WebRecordMonth,
+		"WebCalendar":      &messages.WebCalendar,
+		"WebCalendarMonth": &messages.WebCalendarMonth,
+		"WebWeekdays":      &messages.WebWeekdays,
+		"WebExpires":       &messages.WebExpires,
+		"WebLinkInvalid":   &messages.WebLinkInvalid,
+
+		// Инлайн-запросы
+		"InlineMyStatsTitle":     &messages.InlineMyStatsTitle,
+		"InlineMyStats":          &messages.InlineMyStats,
+		"InlineMyStatsNone":      &messages.InlineMyStatsNone,
+		"InlineStatsTitle":       &messages.InlineStatsTitle,
+		"InlineStatsDescription": &messages.InlineStatsDescription,
+		"InlineTodayTitle":       &messages.InlineTodayTitle,
+		"InlineNoChat":           &messages.InlineNoChat,
+
+		// Картинка статистики
+		"StatsImageTitle":   &messages.StatsImageTitle,
+		"StatsImageCaption": &messages.StatsImageCaption,
+		"StatsImageRest":    &messages.StatsImageRest,
 	}
 
 	// Создаем шаблоны
 	for name, templatePtr := range templates {
-		templateStr, exists := messageTemplates[name]
+		templateLocale := locale
+		templateStr, exists := definition.templates[name]
+		if !exists {
+			templateLocale = DefaultLocale
+			templateStr, exists = fallback.templates[name]
+		}
 		if !exists {
 			return nil, fmt.Errorf("template %s not found", name)
 		}
 
-		template, err := NewTemplate(templateStr)
+		template, err := NewLocaleTemplate(templateStr, templateLocale)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create template %s: %w", name, err)
 		}
@@ -111,9 +293,48 @@ func NewMessages() (*Messages, error) {
 		*templatePtr = template
 	}
 
+	// Создаем описания команд
+	for name, descriptionStr := range fallback.commands {
+		templateLocale := DefaultLocale
+		if localized, exists := definition.commands[name]; exists {
+			templateLocale = locale
+			descriptionStr = localized
+		}
+
+		template, err := NewLocaleTemplate(descriptionStr, templateLocale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create command description %s: %w", name, err)
+		}
+
+		messages.Commands[name] = template
+	}
+
+	// Создаем контексты ошибок: каждый контекст обязан быть в языке по умолчанию
+	for _, context := range ErrorContexts() {
+		contextStr, templateLocale := definition.errors[context], locale
+		if contextStr == "" {
+			contextStr, templateLocale = fallback.errors[context], DefaultLocale
+		}
+		if contextStr == "" {
+			return nil, fmt.Errorf("error context %s not found", context)
+		}
+
+		template, err := NewLocaleTemplate(contextStr, templateLocale)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create error context %s: %w", context, err)
+		}
+
+		messages.ErrorContexts[context] = template
+	}
+
 	return messages, nil
 }
 
+// Locale возвращает язык набора сообщений
+func (m *Messages) Locale() Locale {
+	return m.locale
+}
+
 // GetPositionEmoji возвращает эмодзи для позиции в статистике
 func GetPositionEmoji(position int) string {
 	switch position {
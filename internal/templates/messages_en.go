@@ -0,0 +1,280 @@
+package templates
+
+// enTemplates содержит шаблоны сообщений на английском языке
+var enTemplates = map[string]string{
+	"BotGroupOnly": "This bot only works in groups!",
+
+	"UnknownCommand": "Unknown command. Use /help to see the list of commands.",
+
+	"ErrorOccurred": "An error occurred: {{error}}",
+
+	"HelpText": `{{emoji}} Welcome to the "{{title}}" bot!
+
+Available commands:
+{{commands}}
+The bot only works in groups and picks a random member among active users.`,
+
+	"HelpCommandEntry": "/{{command}} - {{description}}\n",
+
+	"HelpAdminCommandEntry": "/{{command}} - {{description}} (admins only)\n",
+
+	"PersonAlreadySelected": `{{emoji}} {{title}} has already been picked!
+
+👤 {{person}}`,
+
+	"PersonSelected": `🎉 {{title}} has been picked!
+
+{{emoji}} {{person}}
+
+Congratulations! 🎊`,
+
+	"NoActiveUsers": "There are no active members in this group to pick from.",
+
+	"PersonInfo": `ℹ️ {{title}} today:
+
+👤 {{person}}
+📅 {{date|date:short}}`,
+
+	"NoPersonSelectedToday": "{{title}}: nobody has been picked today yet. Use /pidor to pick one!",
+
+	"ChatInfo": `📊 Chat info:
+
+👥 Active users: {{users|number}}
+🏆 Stats records: {{records|number}}
+{{today}}`,
+
+	"ChatInfoToday": "{{emoji}} {{title}} today: {{person}}",
+
+	"ChatInfoNoPerson": "{{emoji}} {{title}} has not been picked today yet",
+
+	"StatsHeader": "📊 \"{{title}}\" statistics:\n\n",
+
+	"StatsEmpty": "There are no statistics in this group yet.",
+
+	"StatsEntry": "{{position}} {{person}} - {{count|number}} {{count|plural:time,times}}\n",
+
+	"LanguageName": "English",
+
+	"LanguageChanged": "✅ Bot language in this chat: {{language}}",
+
+	"LanguageUsage": `🌐 Current language: {{language}}
+
+Usage: /pidorlang {{languages}}`,
+
+	"DefaultTitle": "Pidor of the day",
+
+	"AdminOnly": "This command is only available to chat administrators.",
+
+	"TitleChanged": "✅ New role title: {{emoji}} {{title}}",
+
+	"TitleUsage": `Current role title: {{emoji}} {{title}}
+
+Usage: /pidortitle [emoji] title (up to {{max|number}} characters)
+Reset: /pidortitle reset`,
+
+	"NominationsHeader": "🏅 Chat nominations:\n\n",
+
+	"NominationsEmpty": "There are no additional nominations in this chat yet.\n",
+
+	"NominationEntry": "{{emoji}} {{title}} — /{{command}}, /{{command}}stats, /{{command}}info ({{strategy}})\n",
+
+	"NominationUsage": `Managing nominations (admins only):
+/pidornom add command strategy [emoji] title
+/pidornom remove command
+
+Strategies: {{strategies}}
+random — everyone has equal chances
+fair — members who won less often are picked more often
+rotation — pick only among members with the fewest wins`,
+
+	"NominationAdded": `✅ Nomination added: {{emoji}} {{title}}
+
+Commands: /{{command}}, /{{command}}stats, /{{command}}info`,
+
+	"NominationRemoved": "🗑 Nomination /{{command}} has been removed together with its history and aliases",
+
+	"NominationNotFound": "Nomination /{{command}} not found",
+
+	"NominationExists": "Command /{{command}} is already taken",
+
+	"NominationLimit": "A chat can have at most {{max|number}} {{max|plural:nomination,nominations}}",
+
+	"AliasesHeader": "🔀 Chat command aliases:\n\n",
+
+	"AliasesEmpty": "There are no command aliases in this chat yet.\n",
+
+	"AliasEntry": "/{{alias}} → /{{command}}\n",
+
+	"AliasUsage": `Managing aliases (admins only):
+/pidoralias add alias command
+/pidoralias remove alias
+
+Example: /pidoralias add hero pidor`,
+
+	"AliasAdded": "✅ Alias added: /{{alias}} → /{{command}}",
+
+	"AliasRemoved": "🗑 Alias /{{alias}} has been removed",
+
+	"AliasNotFound": "Alias /{{alias}} not found",
+
+	"AliasExists": "Command /{{alias}} is already taken",
+
+	"AliasTargetNotFound": "Command /{{command}} not found",
+
+	"ExportUsage": `Exporting the draw history (admins only):
+/pidorexport — CSV spreadsheet
+/pidorexport json — JSON file`,
+
+	"ExportEmpty": "There is no draw history in this chat yet.",
+
+	"ExportCaption": "📄 Draw history: {{count|number}} {{count|plural:record,records}}",
+
+	"ImportUsage": `Importing history from another bot (admins only):
+send a CSV or JSON file with the caption /pidorimport or reply /pidorimport to a message with the file.
+
+Columns: date (2024-01-31 or 31.01.2024), user_id or username, nomination (optional).
+Participants are matched with chat users, dates that are already taken are not overwritten.`,
+
+	"ImportFailed": "❌ Failed to read the file: {{error}}",
+
+	"ImportResult": "📥 Import finished: added {{imported|number}} of {{total|number}}, skipped taken dates: {{skipped|number}}",
+
+	"ImportUnmatchedHeader": "\n\n⚠️ Not imported:\n",
+
+	"ImportUnmatchedEntry": "line {{line}}: {{value}} — {{reason}}\n",
+
+	"ImportUnmatchedMore": "…and {{count|number}} more\n",
+
+	"ImportReasonInvalid": "invalid row",
+
+	"ImportReasonUser": "participant not found in the chat",
+
+	"ImportReasonNomination": "nomination not found",
+
+	"APIUsage": `Chat statistics HTTP API token (admins only):
+/pidorapi — token status
+/pidorapi new — create a new token, the previous one stops working
+/pidorapi revoke — revoke the token`,
+
+	"APIDisabled": "The HTTP API is disabled in the bot settings.",
+
+	"APITokenStatus": "🔑 API token created on {{date|date:long}}",
+
+	"APITokenNone": "🔑 This chat has no API token.",
+
+	"APITokenCreated": `🔑 API token for «{{chat}}»:
+
+{{token}}
+
+Pass it in the Authorization: Bearer <token> header.
+Requests: GET {{path}}/stats, {{path}}/history, {{path}}/today
+
+The token is shown only once, the previous token no longer works.`,
+
+	"APITokenSent": "🔑 A new API token has been sent to you in a private message.",
+
+	"APITokenPrivateFailed": "❌ Failed to send the token in a private message. Start a conversation with the bot and repeat /pidorapi new.",
+
+	"APITokenRevoked": "🗑 API token revoked.",
+
+	"WebLink": "🌐 Chat statistics page: {{url}}\nThe link is valid until {{date|date:long}}",
+
+	"WebDisabled": "The statistics web page is disabled in the bot settings.",
+
+	"WebHeading": "{{emoji}} {{title}}",
+
+	"WebLeaderboard": "Leaderboard",
+
+	"WebColumnUser": "Member",
+
+	"WebColumnWins": "Wins",
+
+	"WebColumnShare": "Share",
+
+	"WebShare": "{{share|number:1}}%",
+
+	"WebEmpty": "Nobody has been picked in this nomination yet.",
+
+	"WebRecords": "Records",
+
+	"WebRecordTotal": "Total picks: {{count|number}}",
+
+	"WebRecordFirst": "First pick: {{date|date:short}}",
+
+	"WebRecordStreak": "Longest streak: {{person}} — {{count|number}} {{count|plural:day,days}} in a row",
+
+	"WebRecordMonth": "Best month: {{person}} — {{count|number}} {{count|plural:win,wins}} in {{date|date:January 2006}}",
+
+	"WebCalendar": "Pick calendar",
+
+	"WebCalendarMonth": "{{date|date:January 2006}}",
+
+	"WebWeekdays": "Mo Tu We Th Fr Sa Su",
+
+	"WebExpires": "The link is valid until {{date|date:long}}",
+
+	"WebLinkInvalid": "The link is invalid or has expired. Request a new one with /pidorstats web in the chat.",
+
+	"InlineMyStatsTitle": "👤 My stats",
+
+	"InlineMyStats": "{{emoji}} {{person}}: {{title}} {{count|number}} {{count|plural:time,times}}, rank {{rank|number}} of {{total|number}}",
+
+	"InlineMyStatsNone": "{{emoji}} {{person}}: {{title}} — not yet",
+
+	"InlineStatsTitle": "📊 Leaderboard",
+
+	"InlineStatsDescription": "Leader: {{person}} — {{count|number}} {{count|plural:time,times}}",
+
+	"InlineTodayTitle": "{{emoji}} {{title}} today",
+
+	"InlineNoChat": "Write in a group with the bot to see statistics",
+
+	"StatsImageTitle": "\"{{title}}\" statistics",
+
+	"StatsImageCaption": "📊 \"{{title}}\" statistics",
+
+	"StatsImageRest": "and {{count|number}} more {{count|plural:member,members}}",
+}
+
+// enCommands содержит описания команд на английском языке
+var enCommands = map[string]string{
+	"start":       "Start using the bot",
+	"help":        "Show help",
+	"pidor":       "Pick: {{title}}",
+	"pidorstats":  "Show statistics for all members",
+	"pidorinfo":   "Chat info and today's pick",
+	"pidorlang":   "Change the bot language in this chat",
+	"pidortitle":  "Change the role title and emoji",
+	"pidornom":    "Additional chat nominations",
+	"pidoralias":  "Chat command aliases",
+	"pidorexport": "Export the draw history to CSV or JSON",
+	"pidorimport": "Import history from another bot",
+	"pidorapi":    "Chat statistics HTTP API token",
+}
+
+// enErrorContexts содержит контексты сообщений об ошибках на английском языке
+var enErrorContexts = map[ErrorContext]string{
+	ErrorDatabase:         "while connecting to the database",
+	ErrorDraw:             "while picking a participant",
+	ErrorStats:            "while loading statistics",
+	ErrorStatsImage:       "while drawing statistics",
+	ErrorInfo:             "while loading chat info",
+	ErrorHistory:          "while loading history",
+	ErrorExport:           "while exporting history",
+	ErrorFileDownload:     "while downloading the file",
+	ErrorImport:           "while importing history",
+	ErrorLanguage:         "while changing the language",
+	ErrorTitle:            "while changing the role title",
+	ErrorNominations:      "while loading nominations",
+	ErrorNomination:       "while loading the nomination",
+	ErrorNominationCreate: "while creating the nomination",
+	ErrorNominationDelete: "while deleting the nomination",
+	ErrorAliases:          "while loading aliases",
+	ErrorAlias:            "while loading the alias",
+	ErrorAliasSave:        "while saving the alias",
+	ErrorAliasDelete:      "while deleting the alias",
+	ErrorTokenGet:         "while loading the API token",
+	ErrorTokenCreate:      "while creating the API token",
+	ErrorTokenSave:        "while saving the API token",
+	ErrorTokenDelete:      "while deleting the API token",
+}
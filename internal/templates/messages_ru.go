@@ -0,0 +1,280 @@
+package templates
+
+// ruTemplates содержит шаблоны сообщений на русском языке
+var ruTemplates = map[string]string{
+	"BotGroupOnly": "Этот бот работает только в группах!",
+
+	"UnknownCommand": "Неизвестная команда. Используйте /help для списка команд.",
+
+	"ErrorOccurred": "Произошла ошибка: {{error}}",
+
+	"HelpText": `{{emoji}} Добро пожаловать в бота "{{title}}"!
+
+Доступные команды:
+{{commands}}
+Бот работает только в группах и выбирает случайного участника из числа активных пользователей.`,
+
+	"HelpCommandEntry": "/{{command}} - {{description}}\n",
+
+	"HelpAdminCommandEntry": "/{{command}} - {{description}} (для администраторов)\n",
+
+	"PersonAlreadySelected": `{{emoji}} {{title}} уже выбран!
+
+👤 {{person}}`,
+
+	"PersonSelected": `🎉 {{title}} выбран!
+
+{{emoji}} {{person}}
+
+Поздравляем! 🎊`,
+
+	"NoActiveUsers": "В группе нет активных участников для выбора.",
+
+	"PersonInfo": `ℹ️ {{title}} сегодня:
+
+👤 {{person}}
+📅 {{date|date:short}}`,
+
+	"NoPersonSelectedToday": "{{title}}: сегодня еще никто не выбран. Используйте /pidor для выбора!",
+
+	"ChatInfo": `📊 Информация о чате:
+
+👥 Активных пользователей: {{users|number}}
+🏆 Записей в статистике: {{records|number}}
+{{today}}`,
+
+	"ChatInfoToday": "{{emoji}} {{title}} сегодня: {{person}}",
+
+	"ChatInfoNoPerson": "{{emoji}} {{title}} сегодня еще не выбран",
+
+	"StatsHeader": "📊 Статистика \"{{title}}\":\n\n",
+
+	"StatsEmpty": "В этой группе пока нет статистики.",
+
+	"StatsEntry": "{{position}} {{person}} - {{count|number}} {{count|plural:раз,раза,раз}}\n",
+
+	"LanguageName": "Русский",
+
+	"LanguageChanged": "✅ Язык бота в этом чате: {{language}}",
+
+	"LanguageUsage": `🌐 Текущий язык: {{language}}
+
+Использование: /pidorlang {{languages}}`,
+
+	"DefaultTitle": "Пидор дня",
+
+	"AdminOnly": "Эта команда доступна только администраторам чата.",
+
+	"TitleChanged": "✅ Новое название роли: {{emoji}} {{title}}",
+
+	"TitleUsage": `Текущее название роли: {{emoji}} {{title}}
+
+Использование: /pidortitle [эмодзи] название (до {{max|number}} символов)
+Сброс: /pidortitle reset`,
+
+	"NominationsHeader": "🏅 Номинации чата:\n\n",
+
+	"NominationsEmpty": "В чате пока нет дополнительных номинаций.\n",
+
+	"NominationEntry": "{{emoji}} {{title}} — /{{command}}, /{{command}}stats, /{{command}}info ({{strategy}})\n",
+
+	"NominationUsage": `Управление номинациями (для администраторов):
+/pidornom add команда стратегия [эмодзи] название
+/pidornom remove команда
+
+Стратегии: {{strategies}}
+random — у всех равные шансы
+fair — чаще выбираются те, кто побеждал реже
+rotation — выбор только среди тех, у кого меньше всего побед`,
+
+	"NominationAdded": `✅ Номинация добавлена: {{emoji}} {{title}}
+
+Команды: /{{command}}, /{{command}}stats, /{{command}}info`,
+
+	"NominationRemoved": "🗑 Номинация /{{command}} удалена вместе с историей и алиасами",
+
+	"NominationNotFound": "Номинация /{{command}} не найдена",
+
+	"NominationExists": "Команда /{{command}} уже занята",
+
+	"NominationLimit": "В чате может быть не больше {{max|number}} {{max|plural:номинации,номинаций,номинаций}}",
+
+	"AliasesHeader": "🔀 Алиасы команд чата:\n\n",
+
+	"AliasesEmpty": "В чате пока нет алиасов команд.\n",
+
+	"AliasEntry": "/{{alias}} → /{{command}}\n",
+
+	"AliasUsage": `Управление алиасами (для администраторов):
+/pidoralias add алиас команда
+/pidoralias remove алиас
+
+Например: /pidoralias add hero pidor`,
+
+	"AliasAdded": "✅ Алиас добавлен: /{{alias}} → /{{command}}",
+
+	"AliasRemoved": "🗑 Алиас /{{alias}} удален",
+
+	"AliasNotFound": "Алиас /{{alias}} не найден",
+
+	"AliasExists": "Команда /{{alias}} уже занята",
+
+	"AliasTargetNotFound": "Команда /{{command}} не найдена",
+
+	"ExportUsage": `Выгрузка истории выборов (для администраторов):
+/pidorexport — таблица CSV
+/pidorexport json — файл JSON`,
+
+	"ExportEmpty": "В чате пока нет истории выборов.",
+
+	"ExportCaption": "📄 История выборов: {{count|number}} {{count|plural:запись,записи,записей}}",
+
+	"ImportUsage": `Импорт истории из другого бота (для администраторов):
+отправьте файл CSV или JSON с подписью /pidorimport или ответьте /pidorimport на сообщение с файлом.
+
+Колонки: date (2024-01-31 или 31.01.2024), user_id или username, nomination (необязательно).
+Участники сопоставляются с пользователями чата, уже занятые даты не перезаписываются.`,
+
+	"ImportFailed": "❌ Не удалось прочитать файл: {{error}}",
+
+	"ImportResult": "📥 Импорт завершен: добавлено {{imported|number}} из {{total|number}}, пропущено занятых дат: {{skipped|number}}",
+
+	"ImportUnmatchedHeader": "\n\n⚠️ Не импортированы:\n",
+
+	"ImportUnmatchedEntry": "строка {{line}}: {{value}} — {{reason}}\n",
+
+	"ImportUnmatchedMore": "…и еще {{count|number}}\n",
+
+	"ImportReasonInvalid": "ошибка в строке",
+
+	"ImportReasonUser": "участник не найден в чате",
+
+	"ImportReasonNomination": "номинация не найдена",
+
+	"APIUsage": `Токен HTTP API статистики чата (для администраторов):
+/pidorapi — состояние токена
+/pidorapi new — создать новый токен, предыдущий перестанет действовать
+/pidorapi revoke — отозвать токен`,
+
+	"APIDisabled": "HTTP API выключено в настройках бота.",
+
+	"APITokenStatus": "🔑 Токен API создан {{date|date:long}}",
+
+	"APITokenNone": "🔑 У чата нет токена API.",
+
+	"APITokenCreated": `🔑 Токен API чата «{{chat}}»:
+
+{{token}}
+
+Передавайте его в заголовке Authorization: Bearer <токен>.
+Запросы: GET {{path}}/stats, {{path}}/history, {{path}}/today
+
+Токен показывается один раз, предыдущий токен больше не действует.`,
+
+	"APITokenSent": "🔑 Новый токен API отправлен в личные сообщения.",
+
+	"APITokenPrivateFailed": "❌ Не удалось отправить токен в личные сообщения. Начните диалог с ботом и повторите /pidorapi new.",
+
+	"APITokenRevoked": "🗑 Токен API отозван.",
+
+	"WebLink": "🌐 Статистика чата на странице: {{url}}\nСсылка действует до {{date|date:long}}",
+
+	"WebDisabled": "Веб-страница статистики выключена в настройках бота.",
+
+	"WebHeading": "{{emoji}} {{title}}",
+
+	"WebLeaderboard": "Таблица лидеров",
+
+	"WebColumnUser": "Участник",
+
+	"WebColumnWins": "Победы",
+
+	"WebColumnShare": "Доля",
+
+	"WebShare": "{{share|number:1}}%",
+
+	"WebEmpty": "В этой номинации еще никого не выбирали.",
+
+	"WebRecords": "Рекорды",
+
+	"WebRecordTotal": "Всего выборов: {{count|number}}",
+
+	"WebRecordFirst": "Первый выбор: {{date|date:short}}",
+
+	"WebRecordStreak": "Самая длинная серия: {{person}} — {{count|number}} {{count|plural:день,дня,дней}} подряд",
+
+	"WebRecordMonth": "Лучший месяц: {{person}} — {{count|number}} {{count|plural:победа,победы,побед}} за {{date|date:01.2006}}",
+
+	"WebCalendar": "Календарь выборов",
+
+	"WebCalendarMonth": "{{date|date:01.2006}}",
+
+	"WebWeekdays": "Пн Вт Ср Чт Пт Сб Вс",
+
+	"WebExpires": "Ссылка действует до {{date|date:long}}",
+
+	"WebLinkInvalid": "Ссылка недействительна или устарела. Запросите новую командой /pidorstats web в чате.",
+
+	"InlineMyStatsTitle": "👤 Моя статистика",
+
+	"InlineMyStats": "{{emoji}} {{person}}: {{title}} {{count|number}} {{count|plural:раз,раза,раз}}, {{rank|number}} место из {{total|number}}",
+
+	"InlineMyStatsNone": "{{emoji}} {{person}}: {{title}} — еще ни разу",
+
+	"InlineStatsTitle": "📊 Таблица лидеров",
+
+	"InlineStatsDescription": "Лидер: {{person}} — {{count|number}} {{count|plural:раз,раза,раз}}",
+
+	"InlineTodayTitle": "{{emoji}} {{title}} сегодня",
+
+	"InlineNoChat": "Напишите в группе с ботом, чтобы видеть статистику",
+
+	"StatsImageTitle": "Статистика \"{{title}}\"",
+
+	"StatsImageCaption": "📊 Статистика \"{{title}}\"",
+
+	"StatsImageRest": "и еще {{count|number}} {{count|plural:участник,участника,участников}}",
+}
+
+// ruCommands содержит описания команд на русском языке
+var ruCommands = map[string]string{
+	"start":       "Начать работу с ботом",
+	"help":        "Показать справку",
+	"pidor":       "Выбрать: {{title}}",
+	"pidorstats":  "Показать статистику всех участников",
+	"pidorinfo":   "Информация о чате и сегодняшнем выборе",
+	"pidorlang":   "Сменить язык бота в чате",
+	"pidortitle":  "Сменить название и эмодзи роли",
+	"pidornom":    "Дополнительные номинации чата",
+	"pidoralias":  "Алиасы команд чата",
+	"pidorexport": "Выгрузить историю выборов в CSV или JSON",
+	"pidorimport": "Импортировать историю из другого бота",
+	"pidorapi":    "Токен HTTP API статистики чата",
+}
+
+// ruErrorContexts содержит контексты сообщений об ошибках на русском языке
+var ruErrorContexts = map[ErrorContext]string{
+	ErrorDatabase:         "при подключении к базе данных",
+	ErrorDraw:             "при выборе участника",
+	ErrorStats:            "при получении статистики",
+	ErrorStatsImage:       "при отрисовке статистики",
+	ErrorInfo:             "при получении информации",
+	ErrorHistory:          "при получении истории",
+	ErrorExport:           "при выгрузке истории",
+	ErrorFileDownload:     "при загрузке файла",
+	ErrorImport:           "при импорте истории",
+	ErrorLanguage:         "при смене языка",
+	ErrorTitle:            "при смене названия роли",
+	ErrorNominations:      "при получении номинаций",
+	ErrorNomination:       "при получении номинации",
+	ErrorNominationCreate: "при создании номинации",
+	ErrorNominationDelete: "при удалении номинации",
+	ErrorAliases:          "при получении алиасов",
+	ErrorAlias:            "при получении алиаса",
+	ErrorAliasSave:        "при сохранении алиаса",
+	ErrorAliasDelete:      "при удалении алиаса",
+	ErrorTokenGet:         "при получении токена API",
+	ErrorTokenCreate:      "при создании токена API",
+	ErrorTokenSave:        "при сохранении токена API",
+	ErrorTokenDelete:      "при удалении токена API",
+}
@@ -0,0 +1,280 @@
+package templates
+
+// ukTemplates содержит шаблоны сообщений на украинском языке
+var ukTemplates = map[string]string{
+	"BotGroupOnly": "Цей бот працює лише в групах!",
+
+	"UnknownCommand": "Невідома команда. Використовуйте /help для списку команд.",
+
+	"ErrorOccurred": "Сталася помилка: {{error}}",
+
+	"HelpText": `{{emoji}} Ласкаво просимо до бота "{{title}}"!
+
+Доступні команди:
+{{commands}}
+Бот працює лише в групах і обирає випадкового учасника серед активних користувачів.`,
+
+	"HelpCommandEntry": "/{{command}} - {{description}}\n",
+
+	"HelpAdminCommandEntry": "/{{command}} - {{description}} (для адміністраторів)\n",
+
+	"PersonAlreadySelected": `{{emoji}} {{title}} вже обрано!
+
+👤 {{person}}`,
+
+	"PersonSelected": `🎉 {{title}} обрано!
+
+{{emoji}} {{person}}
+
+Вітаємо! 🎊`,
+
+	"NoActiveUsers": "У групі немає активних учасників для вибору.",
+
+	"PersonInfo": `ℹ️ {{title}} сьогодні:
+
+👤 {{person}}
+📅 {{date|date:short}}`,
+
+	"NoPersonSelectedToday": "{{title}}: сьогодні ще нікого не обрано. Використовуйте /pidor для вибору!",
+
+	"ChatInfo": `📊 Інформація про чат:
+
+👥 Активних користувачів: {{users|number}}
+🏆 Записів у статистиці: {{records|number}}
+{{today}}`,
+
+	"ChatInfoToday": "{{emoji}} {{title}} сьогодні: {{person}}",
+
+	"ChatInfoNoPerson": "{{emoji}} {{title}} сьогодні ще не обрано",
+
+	"StatsHeader": "📊 Статистика \"{{title}}\":\n\n",
+
+	"StatsEmpty": "У цій групі поки немає статистики.",
+
+	"StatsEntry": "{{position}} {{person}} - {{count|number}} {{count|plural:раз,рази,разів}}\n",
+
+	"LanguageName": "Українська",
+
+	"LanguageChanged": "✅ Мова бота в цьому чаті: {{language}}",
+
+	"LanguageUsage": `🌐 Поточна мова: {{language}}
+
+Використання: /pidorlang {{languages}}`,
+
+	"DefaultTitle": "Підор дня",
+
+	"AdminOnly": "Ця команда доступна лише адміністраторам чату.",
+
+	"TitleChanged": "✅ Нова назва ролі: {{emoji}} {{title}}",
+
+	"TitleUsage": `Поточна назва ролі: {{emoji}} {{title}}
+
+Використання: /pidortitle [емодзі] назва (до {{max|number}} символів)
+Скидання: /pidortitle reset`,
+
+	"NominationsHeader": "🏅 Номінації чату:\n\n",
+
+	"NominationsEmpty": "У чаті поки немає додаткових номінацій.\n",
+
+	"NominationEntry": "{{emoji}} {{title}} — /{{command}}, /{{command}}stats, /{{command}}info ({{strategy}})\n",
+
+	"NominationUsage": `Керування номінаціями (для адміністраторів):
+/pidornom add команда стратегія [емодзі] назва
+/pidornom remove команда
+
+Стратегії: {{strategies}}
+random — у всіх рівні шанси
+fair — частіше обираються ті, хто перемагав рідше
+rotation — вибір лише серед тих, у кого найменше перемог`,
+
+	"NominationAdded": `✅ Номінацію додано: {{emoji}} {{title}}
+
+Команди: /{{command}}, /{{command}}stats, /{{command}}info`,
+
+	"NominationRemoved": "🗑 Номінацію /{{command}} видалено разом з історією та аліасами",
+
+	"NominationNotFound": "Номінацію /{{command}} не знайдено",
+
+	"NominationExists": "Команда /{{command}} вже зайнята",
+
+	"NominationLimit": "У чаті може бути не більше {{max|number}} {{max|plural:номінації,номінацій,номінацій}}",
+
+	"AliasesHeader": "🔀 Аліаси команд чату:\n\n",
+
+	"AliasesEmpty": "У чаті поки немає аліасів команд.\n",
+
+	"AliasEntry": "/{{alias}} → /{{command}}\n",
+
+	"AliasUsage": `Керування аліасами (для адміністраторів):
+/pidoralias add аліас команда
+/pidoralias remove аліас
+
+Наприклад: /pidoralias add hero pidor`,
+
+	"AliasAdded": "✅ Аліас додано: /{{alias}} → /{{command}}",
+
+	"AliasRemoved": "🗑 Аліас /{{alias}} видалено",
+
+	"AliasNotFound": "Аліас /{{alias}} не знайдено",
+
+	"AliasExists": "Команда /{{alias}} вже зайнята",
+
+	"AliasTargetNotFound": "Команду /{{command}} не знайдено",
+
+	"ExportUsage": `Вивантаження історії виборів (для адміністраторів):
+/pidorexport — таблиця CSV
+/pidorexport json — файл JSON`,
+
+	"ExportEmpty": "У чаті поки немає історії виборів.",
+
+	"ExportCaption": "📄 Історія виборів: {{count|number}} {{count|plural:запис,записи,записів}}",
+
+	"ImportUsage": `Імпорт історії з іншого бота (для адміністраторів):
+надішліть файл CSV або JSON з підписом /pidorimport або дайте відповідь /pidorimport на повідомлення з файлом.
+
+Колонки: date (2024-01-31 або 31.01.2024), user_id або username, nomination (необов'язково).
+Учасники зіставляються з користувачами чату, вже зайняті дати не перезаписуються.`,
+
+	"ImportFailed": "❌ Не вдалося прочитати файл: {{error}}",
+
+	"ImportResult": "📥 Імпорт завершено: додано {{imported|number}} з {{total|number}}, пропущено зайнятих дат: {{skipped|number}}",
+
+	"ImportUnmatchedHeader": "\n\n⚠️ Не імпортовано:\n",
+
+	"ImportUnmatchedEntry": "рядок {{line}}: {{value}} — {{reason}}\n",
+
+	"ImportUnmatchedMore": "…і ще {{count|number}}\n",
+
+	"ImportReasonInvalid": "помилка в рядку",
+
+	"ImportReasonUser": "учасника не знайдено в чаті",
+
+	"ImportReasonNomination": "номінацію не знайдено",
+
+	"APIUsage": `Токен HTTP API статистики чату (для адміністраторів):
+/pidorapi — стан токена
+/pidorapi new — створити новий токен, попередній перестане діяти
+/pidorapi revoke — відкликати токен`,
+
+	"APIDisabled": "HTTP API вимкнено в налаштуваннях бота.",
+
+	"APITokenStatus": "🔑 Токен API створено {{date|date:long}}",
+
+	"APITokenNone": "🔑 У чату немає токена API.",
+
+	"APITokenCreated": `🔑 Токен API чату «{{chat}}»:
+
+{{token}}
+
+Передавайте його в заголовку Authorization: Bearer <токен>.
+Запити: GET {{path}}/stats, {{path}}/history, {{path}}/today
+
+Токен показується один раз, попередній токен більше не діє.`,
+
+	"APITokenSent": "🔑 Новий токен API надіслано в особисті повідомлення.",
+
+	"APITokenPrivateFailed": "❌ Не вдалося надіслати токен в особисті повідомлення. Почніть діалог з ботом і повторіть /pidorapi new.",
+
+	"APITokenRevoked": "🗑 Токен API відкликано.",
+
+	"WebLink": "🌐 Статистика чату на сторінці: {{url}}\nПосилання діє до {{date|date:long}}",
+
+	"WebDisabled": "Веб-сторінку статистики вимкнено в налаштуваннях бота.",
+
+	"WebHeading": "{{emoji}} {{title}}",
+
+	"WebLeaderboard": "Таблиця лідерів",
+
+	"WebColumnUser": "Учасник",
+
+	"WebColumnWins": "Перемоги",
+
+	"WebColumnShare": "Частка",
+
+	"WebShare": "{{share|number:1}}%",
+
+	"WebEmpty": "У цій номінації ще нікого не обирали.",
+
+	"WebRecords": "Рекорди",
+
+	"WebRecordTotal": "Усього виборів: {{count|number}}",
+
+	"WebRecordFirst": "Перший вибір: {{date|date:short}}",
+
+	"WebRecordStreak": "Найдовша серія: {{person}} — {{count|number}} {{count|plural:день,дні,днів}} поспіль",
+
+	"WebRecordMonth": "Найкращий місяць: {{person}} — {{count|number}} {{count|plural:перемога,перемоги,перемог}} за {{date|date:01.2006}}",
+
+	"WebCalendar": "Календар виборів",
+
+	"WebCalendarMonth": "{{date|date:01.2006}}",
+
+	"WebWeekdays": "Пн Вт Ср Чт Пт Сб Нд",
+
+	"WebExpires": "Посилання діє до {{date|date:long}}",
+
+	"WebLinkInvalid": "Посилання недійсне або застаріле. Запросіть нове командою /pidorstats web у чаті.",
+
+	"InlineMyStatsTitle": "👤 Моя статистика",
+
+	"InlineMyStats": "{{emoji}} {{person}}: {{title}} {{count|number}} {{count|plural:раз,рази,разів}}, {{rank|number}} місце з {{total|number}}",
+
+	"InlineMyStatsNone": "{{emoji}} {{person}}: {{title}} — ще жодного разу",
+
+	"InlineStatsTitle": "📊 Таблиця лідерів",
+
+	"InlineStatsDescription": "Лідер: {{person}} — {{count|number}} {{count|plural:раз,рази,разів}}",
+
+	"InlineTodayTitle": "{{emoji}} {{title}} сьогодні",
+
+	"InlineNoChat": "Напишіть у групі з ботом, щоб бачити статистику",
+
+	"StatsImageTitle": "Статистика \"{{title}}\"",
+
+	"StatsImageCaption": "📊 Статистика \"{{title}}\"",
+
+	"StatsImageRest": "і ще {{count|number}} {{count|plural:учасник,учасники,учасників}}",
+}
+
+// ukCommands содержит описания команд на украинском языке
+var ukCommands = map[string]string{
+	"start":       "Почати роботу з ботом",
+	"help":        "Показати довідку",
+	"pidor":       "Обрати: {{title}}",
+	"pidorstats":  "Показати статистику всіх учасників",
+	"pidorinfo":   "Інформація про чат і сьогоднішній вибір",
+	"pidorlang":   "Змінити мову бота в чаті",
+	"pidortitle":  "Змінити назву та емодзі ролі",
+	"pidornom":    "Додаткові номінації чату",
+	"pidoralias":  "Аліаси команд чату",
+	"pidorexport": "Вивантажити історію виборів у CSV або JSON",
+	"pidorimport": "Імпортувати історію з іншого бота",
+	"pidorapi":    "Токен HTTP API статистики чату",
+}
+
+// ukErrorContexts содержит контексты сообщений об ошибках на украинском языке
+var ukErrorContexts = map[ErrorContext]string{
+	ErrorDatabase:         "під час підключення до бази даних",
+	ErrorDraw:             "під час вибору учасника",
+	ErrorStats:            "під час отримання статистики",
+	ErrorStatsImage:       "під час малювання статистики",
+	ErrorInfo:             "під час отримання інформації",
+	ErrorHistory:          "під час отримання історії",
+	ErrorExport:           "під час вивантаження історії",
+	ErrorFileDownload:     "під час завантаження файлу",
+	ErrorImport:           "під час імпорту історії",
+	ErrorLanguage:         "під час зміни мови",
+	ErrorTitle:            "під час зміни назви ролі",
+	ErrorNominations:      "під час отримання номінацій",
+	ErrorNomination:       "під час отримання номінації",
+	ErrorNominationCreate: "під час створення номінації",
+	ErrorNominationDelete: "під час видалення номінації",
+	ErrorAliases:          "під час отримання аліасів",
+	ErrorAlias:            "під час отримання аліасу",
+	ErrorAliasSave:        "під час збереження аліасу",
+	ErrorAliasDelete:      "під час видалення аліасу",
+	ErrorTokenGet:         "під час отримання токена API",
+	ErrorTokenCreate:      "під час створення токена API",
+	ErrorTokenSave:        "під час збереження токена API",
+	ErrorTokenDelete:      "під час видалення токена API",
+}
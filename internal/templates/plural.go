@@ -0,0 +1,43 @@
+package templates
+
+// PluralRule возвращает индекс формы множественного числа для n
+type PluralRule func(n int) int
+
+// pluralSlavic реализует правило для русского и украинского языков:
+// 0 — "1 раз", 1 — "2 раза", 2 — "5 раз"
+func pluralSlavic(n int) int {
+	if n < 0 {
+		n = -n
+	}
+
+	mod10, mod100 := n%10, n%100
+	switch {
+	case mod10 == 1 && mod100 != 11:
+		return 0
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// pluralEnglish реализует правило для английского языка: 0 — "1 time", 1 — "2 times"
+func pluralEnglish(n int) int {
+	if n == 1 || n == -1 {
+		return 0
+	}
+	return 1
+}
+
+// Plural выбирает форму слова для числа n по правилу rule
+func Plural(rule PluralRule, n int, forms []string) string {
+	if len(forms) == 0 {
+		return ""
+	}
+
+	index := rule(n)
+	if index >= len(forms) {
+		index = len(forms) - 1
+	}
+	return forms[index]
+}
@@ -1,87 +1,241 @@
 package templates
 
 import (
-	"fmt"
 	"strings"
 	"time"
 
 	"github.com/pavel-one/day-of-the-bot/internal/domain"
 )
 
-// MessageService предоставляет методы для форматирования сообщений
+// DefaultEmoji используется, если для чата не задан эмодзи роли
+const DefaultEmoji = "🎯"
+
+// MaxTitleLength ограничивает длину названия роли в символах
+const MaxTitleLength = 64
+
+// MaxImportUnmatched ограничивает число строк без сопоставления в отчете об импорте
+const MaxImportUnmatched = 20
+
+// MessageService предоставляет методы для форматирования сообщений.
+// Во все сообщения подставляются общие плейсхолдеры {{title}} и {{emoji}}.
 type MessageService struct {
+	locales  map[Locale]*Messages
 	messages *Messages
+	fallback Locale
+	title    string
+	emoji    string
 }
 
-// NewMessageService создает новый сервис сообщений
+// NewMessageService создает новый сервис сообщений на языке по умолчанию
 func NewMessageService() (*MessageService, error) {
-	messages, err := NewMessages()
-	if err != nil {
-		return nil, err
+	loaded := make(map[Locale]*Messages, len(locales))
+	for _, locale := range SupportedLocales() {
+		messages, err := NewMessages(locale)
+		if err != nil {
+			return nil, err
+		}
+		loaded[locale] = messages
 	}
 
 	return &MessageService{
-		messages: messages,
+		locales:  loaded,
+		messages: loaded[DefaultLocale],
+		fallback: DefaultLocale,
 	}, nil
 }
 
+// WithDefaultLocale возвращает сервис сообщений, для которого указанный язык
+// используется по умолчанию. Неподдерживаемый язык игнорируется.
+func (ms *MessageService) WithDefaultLocale(locale Locale) *MessageService {
+	if _, ok := ms.locales[locale]; !ok {
+		return ms
+	}
+
+	return &MessageService{
+		locales:  ms.locales,
+		messages: ms.locales[locale],
+		fallback: locale,
+		title:    ms.title,
+		emoji:    ms.emoji,
+	}
+}
+
+// DefaultLocale возвращает язык сервиса по умолчанию
+func (ms *MessageService) DefaultLocale() Locale {
+	return ms.fallback
+}
+
+// WithLocale возвращает сервис сообщений для указанного языка.
+// Для неподдерживаемого языка используется язык по умолчанию.
+func (ms *MessageService) WithLocale(locale Locale) *MessageService {
+	messages, ok := ms.locales[locale]
+	if !ok {
+		messages = ms.locales[ms.fallback]
+	}
+
+	return &MessageService{
+		locales:  ms.locales,
+		messages: messages,
+		fallback: ms.fallback,
+		title:    ms.title,
+		emoji:    ms.emoji,
+	}
+}
+
+// WithTitle возвращает сервис сообщений с названием и эмодзи роли чата.
+// Пустые значения заменяются значениями по умолчанию.
+func (ms *MessageService) WithTitle(title, emoji string) *MessageService {
+	return &MessageService{
+		locales:  ms.locales,
+		messages: ms.messages,
+		fallback: ms.fallback,
+		title:    title,
+		emoji:    emoji,
+	}
+}
+
+// Locale возвращает текущий язык сервиса
+func (ms *MessageService) Locale() Locale {
+	return ms.messages.Locale()
+}
+
+// Title возвращает название роли
+func (ms *MessageService) Title() string {
+	if ms.title != "" {
+		return ms.title
+	}
+	return ms.messages.DefaultTitle.Execute(nil)
+}
+
+// Emoji возвращает эмодзи роли
+func (ms *MessageService) Emoji() string {
+	if ms.emoji != "" {
+		return ms.emoji
+	}
+	return DefaultEmoji
+}
+
+// execute выполняет шаблон, добавляя общие плейсхолдеры чата
+func (ms *MessageService) execute(template *MessageTemplate, data TemplateData) string {
+	merged := TemplateData{
+		"title": ms.Title(),
+		"emoji": ms.Emoji(),
+	}
+	for key, value := range data {
+		merged[key] = value
+	}
+
+	return template.Execute(merged)
+}
+
 // BotGroupOnly возвращает сообщение о работе только в группах
 func (ms *MessageService) BotGroupOnly() string {
-	return ms.messages.BotGroupOnly.Execute(nil)
+	return ms.execute(ms.messages.BotGroupOnly, nil)
 }
 
 // UnknownCommand возвращает сообщение о неизвестной команде
 func (ms *MessageService) UnknownCommand() string {
-	return ms.messages.UnknownCommand.Execute(nil)
+	return ms.execute(ms.messages.UnknownCommand, nil)
+}
+
+// ErrorOccurred возвращает сообщение об ошибке с описанием действия на языке чата
+func (ms *MessageService) ErrorOccurred(context ErrorContext) string {
+	description := string(context)
+	if template, ok := ms.messages.ErrorContexts[context]; ok {
+		description = ms.execute(template, nil)
+	}
+
+	return ms.execute(ms.messages.ErrorOccurred, TemplateData{
+		"error": description,
+	})
+}
+
+// CommandHelp описывает команду для справки
+type CommandHelp struct {
+	Name      string
+	AdminOnly bool
 }
 
-// ErrorOccurred возвращает сообщение об ошибке
-func (ms *MessageService) ErrorOccurred(errorMsg string) string {
-	return ms.messages.ErrorOccurred.Execute(TemplateData{
-		"error": errorMsg,
+// HelpText возвращает текст справки со списком команд
+func (ms *MessageService) HelpText(commands []CommandHelp) string {
+	var list strings.Builder
+	for _, command := range commands {
+		entry := ms.messages.HelpCommandEntry
+		if command.AdminOnly {
+			entry = ms.messages.HelpAdminCommandEntry
+		}
+
+		list.WriteString(ms.execute(entry, TemplateData{
+			"command":     command.Name,
+			"description": ms.CommandDescription(command.Name),
+		}))
+	}
+
+	return ms.execute(ms.messages.HelpText, TemplateData{
+		"commands": list.String(),
 	})
 }
 
-// HelpText возвращает текст справки
-func (ms *MessageService) HelpText() string {
-	return ms.messages.HelpText.Execute(nil)
+// CommandDescription возвращает описание команды или пустую строку, если описания нет
+func (ms *MessageService) CommandDescription(name string) string {
+	template, ok := ms.messages.Commands[name]
+	if !ok {
+		return ""
+	}
+	return ms.execute(template, nil)
 }
 
 // PersonAlreadySelected возвращает сообщение о том, что пидор дня уже выбран
 func (ms *MessageService) PersonAlreadySelected(person domain.User) string {
-	return ms.messages.PersonAlreadySelected.Execute(TemplateData{
+	return ms.execute(ms.messages.PersonAlreadySelected, TemplateData{
 		"person": person.DisplayName(),
 	})
 }
 
 // PersonSelected возвращает сообщение о выборе пидора дня
 func (ms *MessageService) PersonSelected(person domain.User) string {
-	return ms.messages.PersonSelected.Execute(TemplateData{
+	return ms.execute(ms.messages.PersonSelected, TemplateData{
 		"person": person.DisplayName(),
 	})
 }
 
 // NoActiveUsers возвращает сообщение об отсутствии активных пользователей
 func (ms *MessageService) NoActiveUsers() string {
-	return ms.messages.NoActiveUsers.Execute(nil)
+	return ms.execute(ms.messages.NoActiveUsers, nil)
 }
 
 // PersonInfo возвращает информацию о пидоре дня
 func (ms *MessageService) PersonInfo(person domain.User, date time.Time) string {
-	return ms.messages.PersonInfo.Execute(TemplateData{
+	return ms.execute(ms.messages.PersonInfo, TemplateData{
 		"person": person.DisplayName(),
-		"date":   date.Format("02.01.2006"),
+		"date":   date,
 	})
 }
 
 // NoPersonSelectedToday возвращает сообщение о том, что сегодня пидор не выбран
 func (ms *MessageService) NoPersonSelectedToday() string {
-	return ms.messages.NoPersonSelectedToday.Execute(nil)
+	return ms.execute(ms.messages.NoPersonSelectedToday, nil)
+}
+
+// ChatInfo возвращает информацию о чате
+func (ms *MessageService) ChatInfo(usersCount, recordsCount int, todayPerson *domain.User) string {
+	today := ms.execute(ms.messages.ChatInfoNoPerson, nil)
+	if todayPerson != nil {
+		today = ms.execute(ms.messages.ChatInfoToday, TemplateData{
+			"person": todayPerson.FullName(),
+		})
+	}
+
+	return ms.execute(ms.messages.ChatInfo, TemplateData{
+		"users":   usersCount,
+		"records": recordsCount,
+		"today":   today,
+	})
 }
 
 // StatsEmpty возвращает сообщение об отсутствии статистики
 func (ms *MessageService) StatsEmpty() string {
-	return ms.messages.StatsEmpty.Execute(nil)
+	return ms.execute(ms.messages.StatsEmpty, nil)
 }
 
 // NoStatsAvailable возвращает сообщение об отсутствии статистики (алиас для совместимости)
@@ -99,18 +253,514 @@ func (ms *MessageService) BuildStatsMessage(stats []domain.UserStats) string {
 	var result strings.Builder
 
 	// Добавляем заголовок
-	result.WriteString(ms.messages.StatsHeader.Execute(nil))
+	result.WriteString(ms.execute(ms.messages.StatsHeader, nil))
 
 	// Добавляем записи статистики
 	for i, stat := range stats {
 		position := GetPositionEmoji(i + 1)
-		entry := ms.messages.StatsEntry.Execute(TemplateData{
+		entry := ms.execute(ms.messages.StatsEntry, TemplateData{
 			"position": position,
 			"person":   stat.User.DisplayName(),
-			"count":    fmt.Sprintf("%d", stat.Count),
+			"count":    stat.Count,
 		})
 		result.WriteString(entry)
 	}
 
 	return result.String()
 }
+
+// LanguageName возвращает название текущего языка
+func (ms *MessageService) LanguageName() string {
+	return ms.execute(ms.messages.LanguageName, nil)
+}
+
+// LanguageChanged возвращает сообщение о смене языка чата
+func (ms *MessageService) LanguageChanged() string {
+	return ms.execute(ms.messages.LanguageChanged, TemplateData{
+		"language": ms.LanguageName(),
+	})
+}
+
+// LanguageUsage возвращает подсказку по команде смены языка
+func (ms *MessageService) LanguageUsage() string {
+	codes := make([]string, 0, len(ms.locales))
+	for _, locale := range SupportedLocales() {
+		codes = append(codes, string(locale))
+	}
+
+	return ms.execute(ms.messages.LanguageUsage, TemplateData{
+		"language":  ms.LanguageName(),
+		"languages": strings.Join(codes, "|"),
+	})
+}
+
+// AdminOnly возвращает сообщение о команде только для администраторов
+func (ms *MessageService) AdminOnly() string {
+	return ms.execute(ms.messages.AdminOnly, nil)
+}
+
+// TitleChanged возвращает сообщение о смене названия роли
+func (ms *MessageService) TitleChanged() string {
+	return ms.execute(ms.messages.TitleChanged, nil)
+}
+
+// TitleUsage возвращает подсказку по команде смены названия роли
+func (ms *MessageService) TitleUsage() string {
+	return ms.execute(ms.messages.TitleUsage, TemplateData{
+		"max": MaxTitleLength,
+	})
+}
+
+// BuildNominationsMessage строит сообщение со списком номинаций чата и подсказкой по управлению
+func (ms *MessageService) BuildNominationsMessage(nominations []domain.Nomination) string {
+	var result strings.Builder
+
+	if len(nominations) == 0 {
+		result.WriteString(ms.execute(ms.messages.NominationsEmpty, nil))
+	} else {
+		result.WriteString(ms.execute(ms.messages.NominationsHeader, nil))
+		for _, nomination := range nominations {
+			result.WriteString(ms.ForNomination(nomination).execute(ms.messages.NominationEntry, TemplateData{
+				"command":  nomination.Command,
+				"strategy": string(nomination.Strategy),
+			}))
+		}
+	}
+
+	result.WriteString("\n")
+	result.WriteString(ms.NominationUsage())
+
+	return result.String()
+}
+
+// NominationUsage возвращает подсказку по управлению номинациями
+func (ms *MessageService) NominationUsage() string {
+	strategies := make([]string, 0, len(domain.SelectionStrategies()))
+	for _, strategy := range domain.SelectionStrategies() {
+		strategies = append(strategies, string(strategy))
+	}
+
+	return ms.execute(ms.messages.NominationUsage, TemplateData{
+		"strategies": strings.Join(strategies, ", "),
+	})
+}
+
+// NominationAdded возвращает сообщение о добавлении номинации
+func (ms *MessageService) NominationAdded(nomination domain.Nomination) string {
+	return ms.ForNomination(nomination).execute(ms.messages.NominationAdded, TemplateData{
+		"command": nomination.Command,
+	})
+}
+
+// NominationRemoved возвращает сообщение об удалении номинации
+func (ms *MessageService) NominationRemoved(command string) string {
+	return ms.execute(ms.messages.NominationRemoved, TemplateData{
+		"command": command,
+	})
+}
+
+// NominationNotFound возвращает сообщение об отсутствии номинации
+func (ms *MessageService) NominationNotFound(command string) string {
+	return ms.execute(ms.messages.NominationNotFound, TemplateData{
+		"command": command,
+	})
+}
+
+// NominationExists возвращает сообщение о занятой команде
+func (ms *MessageService) NominationExists(command string) string {
+	return ms.execute(ms.messages.NominationExists, TemplateData{
+		"command": command,
+	})
+}
+
+// NominationLimit возвращает сообщение о превышении числа номинаций
+func (ms *MessageService) NominationLimit(max int) string {
+	return ms.execute(ms.messages.NominationLimit, TemplateData{
+		"max": max,
+	})
+}
+
+// ForNomination возвращает сервис сообщений с названием и эмодзи номинации.
+// Для основной номинации сохраняются настройки чата.
+func (ms *MessageService) ForNomination(nomination domain.Nomination) *MessageService {
+	if nomination.IsDefault() {
+		return ms
+	}
+	return ms.WithTitle(nomination.Title, nomination.Emoji)
+}
+
+// BuildAliasesMessage строит сообщение со списком алиасов чата и подсказкой по управлению
+func (ms *MessageService) BuildAliasesMessage(aliases []domain.CommandAlias) string {
+	var result strings.Builder
+
+	if len(aliases) == 0 {
+		result.WriteString(ms.execute(ms.messages.AliasesEmpty, nil))
+	} else {
+		result.WriteString(ms.execute(ms.messages.AliasesHeader, nil))
+		for _, alias := range aliases {
+			result.WriteString(ms.execute(ms.messages.AliasEntry, TemplateData{
+				"alias":   alias.Alias,
+				"command": alias.Command,
+			}))
+		}
+	}
+
+	result.WriteString("\n")
+	result.WriteString(ms.AliasUsage())
+
+	return result.String()
+}
+
+// AliasUsage возвращает подсказку по управлению алиасами
+func (ms *MessageService) AliasUsage() string {
+	return ms.execute(ms.messages.AliasUsage, nil)
+}
+
+// AliasAdded возвращает сообщение о добавлении алиаса
+func (ms *MessageService) AliasAdded(alias, command string) string {
+	return ms.execute(ms.messages.AliasAdded, TemplateData{
+		"alias":   alias,
+		"command": command,
+	})
+}
+
+// AliasRemoved возвращает сообщение об удалении алиаса
+func (ms *MessageService) AliasRemoved(alias string) string {
+	return ms.execute(ms.messages.AliasRemoved, TemplateData{
+		"alias": alias,
+	})
+}
+
+// AliasNotFound возвращает сообщение об отсутствии алиаса
+func (ms *MessageService) AliasNotFound(alias string) string {
+	return ms.execute(ms.messages.AliasNotFound, TemplateData{
+		"alias": alias,
+	})
+}
+
+// AliasExists возвращает сообщение о занятой команде
+func (ms *MessageService) AliasExists(alias string) string {
+	return ms.execute(ms.messages.AliasExists, TemplateData{
+		"alias": alias,
+	})
+}
+
+// AliasTargetNotFound возвращает сообщение о несуществующей команде для алиаса
+func (ms *MessageService) AliasTargetNotFound(command string) string {
+	return ms.execute(ms.messages.AliasTargetNotFound, TemplateData{
+		"command": command,
+	})
+}
+
+// ExportUsage возвращает справку по выгрузке истории
+func (ms *MessageService) ExportUsage() string {
+	return ms.execute(ms.messages.ExportUsage, nil)
+}
+
+// ExportEmpty возвращает сообщение об отсутствии истории для выгрузки
+func (ms *MessageService) ExportEmpty() string {
+	return ms.execute(ms.messages.ExportEmpty, nil)
+}
+
+// ExportCaption возвращает подпись к файлу выгрузки истории
+func (ms *MessageService) ExportCaption(count int) string {
+	return ms.execute(ms.messages.ExportCaption, TemplateData{
+		"count": count,
+	})
+}
+
+// ImportUsage возвращает справку по импорту истории
+func (ms *MessageService) ImportUsage() string {
+	return ms.execute(ms.messages.ImportUsage, nil)
+}
+
+// ImportFailed возвращает сообщение о файле, который не удалось прочитать
+func (ms *MessageService) ImportFailed(err error) string {
+	return ms.execute(ms.messages.ImportFailed, TemplateData{
+		"error": err.Error(),
+	})
+}
+
+// BuildImportReport строит отчет об импорте истории со списком строк, которые не удалось импортировать
+func (ms *MessageService) BuildImportReport(result domain.ImportResult) string {
+	var report strings.Builder
+
+	report.WriteString(ms.execute(ms.messages.ImportResult, TemplateData{
+		"imported": result.Imported,
+		"skipped":  result.Skipped,
+		"total":    result.Total,
+	}))
+
+	if len(result.Unmatched) == 0 {
+		return report.String()
+	}
+
+	report.WriteString(ms.execute(ms.messages.ImportUnmatchedHeader, nil))
+	for i, unmatched := range result.Unmatched {
+		if i == MaxImportUnmatched {
+			report.WriteString(ms.execute(ms.messages.ImportUnmatchedMore, TemplateData{
+				"count": len(result.Unmatched) - MaxImportUnmatched,
+			}))
+			break
+		}
+
+		report.WriteString(ms.execute(ms.messages.ImportUnmatchedEntry, TemplateData{
+			"line":   unmatched.Line,
+			"value":  unmatched.Value,
+			"reason": ms.importReason(unmatched.Reason),
+		}))
+	}
+
+	return strings.TrimRight(report.String(), "\n")
+}
+
+// importReason возвращает описание причины, по которой строка не импортирована
+func (ms *MessageService) importReason(reason domain.ImportReason) string {
+	switch reason {
+	case domain.ReasonUserNotFound:
+		return ms.execute(ms.messages.ImportReasonUser, nil)
+	case domain.ReasonNominationNotFound:
+		return ms.execute(ms.messages.ImportReasonNomination, nil)
+	default:
+		return ms.execute(ms.messages.ImportReasonInvalid, nil)
+	}
+}
+
+// APIUsage возвращает справку по токену HTTP API
+func (ms *MessageService) APIUsage() string {
+	return ms.execute(ms.messages.APIUsage, nil)
+}
+
+// APIDisabled возвращает сообщение о выключенном HTTP API
+func (ms *MessageService) APIDisabled() string {
+	return ms.execute(ms.messages.APIDisabled, nil)
+}
+
+// BuildAPITokenStatus возвращает состояние токена чата со справкой; token nil, если токена нет
+func (ms *MessageService) BuildAPITokenStatus(token *domain.APIToken) string {
+	var result strings.Builder
+
+	if token == nil {
+		result.WriteString(ms.execute(ms.messages.APITokenNone, nil))
+	} else {
+		result.WriteString(ms.execute(ms.messages.APITokenStatus, TemplateData{
+			"date": token.CreatedAt,
+		}))
+	}
+
+	result.WriteString("\n\n")
+	result.WriteString(ms.APIUsage())
+
+	return result.String()
+}
+
+// APITokenCreated возвращает новый токен с подсказкой по запросам; path — путь API чата
+func (ms *MessageService) APITokenCreated(chat, token, path string) string {
+	return ms.execute(ms.messages.APITokenCreated, TemplateData{
+		"chat":  chat,
+		"token": token,
+		"path":  path,
+	})
+}
+
+// APITokenSent возвращает сообщение об отправке токена в личные сообщения
+func (ms *MessageService) APITokenSent() string {
+	return ms.execute(ms.messages.APITokenSent, nil)
+}
+
+// APITokenPrivateFailed возвращает сообщение о невозможности отправить токен в личные сообщения
+func (ms *MessageService) APITokenPrivateFailed() string {
+	return ms.execute(ms.messages.APITokenPrivateFailed, nil)
+}
+
+// APITokenRevoked возвращает сообщение об отзыве токена
+func (ms *MessageService) APITokenRevoked() string {
+	return ms.execute(ms.messages.APITokenRevoked, nil)
+}
+
+// WebLink возвращает сообщение со ссылкой на веб-страницу статистики чата
+func (ms *MessageService) WebLink(url string, expires time.Time) string {
+	return ms.execute(ms.messages.WebLink, TemplateData{
+		"url":  url,
+		"date": expires,
+	})
+}
+
+// WebDisabled возвращает сообщение о выключенной веб-странице статистики
+func (ms *MessageService) WebDisabled() string {
+	return ms.execute(ms.messages.WebDisabled, nil)
+}
+
+// WebHeading возвращает заголовок веб-страницы с названием и эмодзи роли
+func (ms *MessageService) WebHeading() string {
+	return ms.execute(ms.messages.WebHeading, nil)
+}
+
+// WebLeaderboard возвращает заголовок таблицы лидеров
+func (ms *MessageService) WebLeaderboard() string {
+	return ms.execute(ms.messages.WebLeaderboard, nil)
+}
+
+// WebColumnUser возвращает заголовок колонки участника
+func (ms *MessageService) WebColumnUser() string {
+	return ms.execute(ms.messages.WebColumnUser, nil)
+}
+
+// WebColumnWins возвращает заголовок колонки побед
+func (ms *MessageService) WebColumnWins() string {
+	return ms.execute(ms.messages.WebColumnWins, nil)
+}
+
+// WebColumnShare возвращает заголовок колонки доли побед
+func (ms *MessageService) WebColumnShare() string {
+	return ms.execute(ms.messages.WebColumnShare, nil)
+}
+
+// WebShare возвращает долю побед в процентах
+func (ms *MessageService) WebShare(share float64) string {
+	return ms.execute(ms.messages.WebShare, TemplateData{
+		"share": share,
+	})
+}
+
+// WebEmpty возвращает сообщение о номинации без выборов
+func (ms *MessageService) WebEmpty() string {
+	return ms.execute(ms.messages.WebEmpty, nil)
+}
+
+// WebRecords возвращает заголовок блока рекордов
+func (ms *MessageService) WebRecords() string {
+	return ms.execute(ms.messages.WebRecords, nil)
+}
+
+// WebRecordTotal возвращает число выборов в номинации
+func (ms *MessageService) WebRecordTotal(count int) string {
+	return ms.execute(ms.messages.WebRecordTotal, TemplateData{
+		"count": count,
+	})
+}
+
+// WebRecordFirst возвращает дату первого выбора
+func (ms *MessageService) WebRecordFirst(date time.Time) string {
+	return ms.execute(ms.messages.WebRecordFirst, TemplateData{
+		"date": date,
+	})
+}
+
+// WebRecordStreak возвращает самую длинную серию побед подряд
+func (ms *MessageService) WebRecordStreak(person domain.User, days int) string {
+	return ms.execute(ms.messages.WebRecordStreak, TemplateData{
+		"person": person.DisplayName(),
+		"count":  days,
+	})
+}
+
+// WebRecordMonth возвращает наибольшее число побед участника за месяц
+func (ms *MessageService) WebRecordMonth(person domain.User, wins int, month time.Time) string {
+	return ms.execute(ms.messages.WebRecordMonth, TemplateData{
+		"person": person.DisplayName(),
+		"count":  wins,
+		"date":   month,
+	})
+}
+
+// WebCalendar возвращает заголовок календаря выборов
+func (ms *MessageService) WebCalendar() string {
+	return ms.execute(ms.messages.WebCalendar, nil)
+}
+
+// WebCalendarMonth возвращает название месяца календаря
+func (ms *MessageService) WebCalendarMonth(month time.Time) string {
+	return ms.execute(ms.messages.WebCalendarMonth, TemplateData{
+		"date": month,
+	})
+}
+
+// WebWeekdays возвращает сокращенные названия дней недели, начиная с понедельника
+func (ms *MessageService) WebWeekdays() []string {
+	return strings.Fields(ms.execute(ms.messages.WebWeekdays, nil))
+}
+
+// WebExpires возвращает срок действия ссылки на страницу
+func (ms *MessageService) WebExpires(expires time.Time) string {
+	return ms.execute(ms.messages.WebExpires, TemplateData{
+		"date": expires,
+	})
+}
+
+// WebLinkInvalid возвращает сообщение о недействительной или устаревшей ссылке
+func (ms *MessageService) WebLinkInvalid() string {
+	return ms.execute(ms.messages.WebLinkInvalid, nil)
+}
+
+// InlineMyStatsTitle возвращает заголовок инлайн-результата со статистикой участника
+func (ms *MessageService) InlineMyStatsTitle() string {
+	return ms.execute(ms.messages.InlineMyStatsTitle, nil)
+}
+
+// InlineMyStats возвращает победы участника и его место в таблице чата
+func (ms *MessageService) InlineMyStats(person domain.User, count, rank, total int) string {
+	if count == 0 {
+		return ms.execute(ms.messages.InlineMyStatsNone, TemplateData{
+			"person": person.DisplayName(),
+		})
+	}
+
+	return ms.execute(ms.messages.InlineMyStats, TemplateData{
+		"person": person.DisplayName(),
+		"count":  count,
+		"rank":   rank,
+		"total":  total,
+	})
+}
+
+// InlineStatsTitle возвращает заголовок инлайн-результата с таблицей лидеров
+func (ms *MessageService) InlineStatsTitle() string {
+	return ms.execute(ms.messages.InlineStatsTitle, nil)
+}
+
+// InlineStatsDescription возвращает краткое описание таблицы лидеров: лидера и его победы
+func (ms *MessageService) InlineStatsDescription(leader domain.UserStats) string {
+	return ms.execute(ms.messages.InlineStatsDescription, TemplateData{
+		"person": leader.User.DisplayName(),
+		"count":  leader.Count,
+	})
+}
+
+// InlineTodayTitle возвращает заголовок инлайн-результата с сегодняшним выбором
+func (ms *MessageService) InlineTodayTitle() string {
+	return ms.execute(ms.messages.InlineTodayTitle, nil)
+}
+
+// InlineToday возвращает сегодняшний выбор; person nil, если никто еще не выбран
+func (ms *MessageService) InlineToday(person *domain.User) string {
+	if person == nil {
+		return ms.execute(ms.messages.ChatInfoNoPerson, nil)
+	}
+
+	return ms.execute(ms.messages.ChatInfoToday, TemplateData{
+		"person": person.DisplayName(),
+	})
+}
+
+// InlineNoChat возвращает подсказку для участника, которого бот еще не видел в группах
+func (ms *MessageService) InlineNoChat() string {
+	return ms.execute(ms.messages.InlineNoChat, nil)
+}
+
+// StatsImageTitle возвращает заголовок картинки со статистикой
+func (ms *MessageService) StatsImageTitle() string {
+	return ms.execute(ms.messages.StatsImageTitle, nil)
+}
+
+// StatsImageCaption возвращает подпись к картинке со статистикой
+func (ms *MessageService) StatsImageCaption() string {
+	return ms.execute(ms.messages.StatsImageCaption, nil)
+}
+
+// StatsImageRest возвращает подпись об участниках, не поместившихся на картинку
+func (ms *MessageService) StatsImageRest(count int) string {
+	return ms.execute(ms.messages.StatsImageRest, TemplateData{
+		"count": count,
+	})
+}
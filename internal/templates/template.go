@@ -2,33 +2,68 @@ package templates
 
 import (
 	"fmt"
+	"io"
 
 	"github.com/valyala/fasttemplate"
 )
 
 // MessageTemplate представляет шаблон сообщения
 type MessageTemplate struct {
-	template *fasttemplate.Template
+	template     *fasttemplate.Template
+	placeholders map[string]placeholder
+	format       LocaleFormat
 }
 
 // TemplateData содержит данные для подстановки в шаблон
 type TemplateData map[string]interface{}
 
-// NewTemplate создает новый шаблон из строки
+// NewTemplate создает новый шаблон из строки с правилами форматирования DefaultLocale
 func NewTemplate(templateStr string) (*MessageTemplate, error) {
+	return NewLocaleTemplate(templateStr, DefaultLocale)
+}
+
+// NewLocaleTemplate создает новый шаблон из строки с правилами форматирования языка.
+// Все типизированные плейсхолдеры проверяются при создании.
+func NewLocaleTemplate(templateStr string, locale Locale) (*MessageTemplate, error) {
+	definition, ok := locales[locale]
+	if !ok {
+		return nil, fmt.Errorf("locale %s is not supported", locale)
+	}
+
 	template, err := fasttemplate.NewTemplate(templateStr, "{{", "}}")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create template: %w", err)
 	}
 
+	placeholders := make(map[string]placeholder)
+	_, err = template.ExecuteFunc(io.Discard, func(w io.Writer, tag string) (int, error) {
+		if _, exists := placeholders[tag]; exists {
+			return 0, nil
+		}
+
+		p, err := parsePlaceholder(tag, definition.format)
+		if err != nil {
+			return 0, err
+		}
+		placeholders[tag] = p
+		return 0, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+
 	return &MessageTemplate{
-		template: template,
+		template:     template,
+		placeholders: placeholders,
+		format:       definition.format,
 	}, nil
 }
 
 // Execute выполняет шаблон с переданными данными
 func (mt *MessageTemplate) Execute(data TemplateData) string {
-	return mt.template.ExecuteString(data)
+	return mt.template.ExecuteFuncString(func(w io.Writer, tag string) (int, error) {
+		return w.Write([]byte(mt.placeholders[tag].render(data, mt.format)))
+	})
 }
 
 // ExecuteString выполняет шаблон напрямую из строки (для простых случаев)
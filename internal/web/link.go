@@ -0,0 +1,99 @@
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+)
+
+// Ошибки проверки ссылки
+var (
+	ErrInvalidSignature = errors.New("invalid link signature")
+	ErrLinkExpired      = errors.New("link expired")
+)
+
+// Параметры подписанной ссылки
+const (
+	expiresParam   = "expires"
+	signatureParam = "sig"
+)
+
+// Links выдает и проверяет ссылки на страницу чата. Ссылка подписана HMAC-SHA256
+// от ID чата и срока действия, поэтому ее нельзя переделать на другой чат или продлить.
+type Links struct {
+	baseURL string
+	secret  []byte
+	ttl     time.Duration
+	clock   clock.Clock
+}
+
+// NewLinks создает выдачу ссылок от внешнего адреса baseURL. Пустой secret
+// заменяется случайным ключом: ссылки перестанут действовать после перезапуска.
+// Срок действия отсчитывается по clk; nil — системные часы.
+func NewLinks(baseURL, secret string, ttl time.Duration, clk clock.Clock) (*Links, error) {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate web link secret: %w", err)
+		}
+	}
+
+	return &Links{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		secret:  key,
+		ttl:     ttl,
+		clock:   clock.OrSystem(clk),
+	}, nil
+}
+
+// Link возвращает ссылку на страницу чата и срок ее действия
+func (l *Links) Link(chatID int64) (string, time.Time) {
+	expires := l.clock.Now().Add(l.ttl).Truncate(time.Second)
+
+	query := url.Values{}
+	query.Set(expiresParam, strconv.FormatInt(expires.Unix(), 10))
+	query.Set(signatureParam, l.sign(chatID, expires.Unix()))
+
+	return l.baseURL + ChatPath(chatID) + "?" + query.Encode(), expires
+}
+
+// Verify проверяет подпись и срок действия ссылки на страницу чата
+func (l *Links) Verify(chatID int64, query url.Values) (time.Time, error) {
+	expiresUnix, err := strconv.ParseInt(query.Get(expiresParam), 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	signature := query.Get(signatureParam)
+	if !hmac.Equal([]byte(signature), []byte(l.sign(chatID, expiresUnix))) {
+		return time.Time{}, ErrInvalidSignature
+	}
+
+	expires := time.Unix(expiresUnix, 0)
+	if !l.clock.Now().Before(expires) {
+		return expires, ErrLinkExpired
+	}
+	return expires, nil
+}
+
+// sign возвращает подпись ссылки чата со сроком действия expires
+func (l *Links) sign(chatID, expires int64) string {
+	mac := hmac.New(sha256.New, l.secret)
+	fmt.Fprintf(mac, "%d:%d", chatID, expires)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// ChatPath возвращает путь страницы чата, например /web/chats/-100123
+func ChatPath(chatID int64) string {
+	return fmt.Sprintf("%s/chats/%d", BasePath, chatID)
+}
@@ -0,0 +1,175 @@
+package web
+
+import (
+	"time"
+
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+)
+
+// CalendarMonths сколько последних месяцев показывает календарь выборов
+const CalendarMonths = 12
+
+// paletteSize число цветов участников в календаре; остальные участники
+// и участники, покинувшие чат, показываются общим цветом 0
+const paletteSize = 8
+
+// leader строка таблицы лидеров
+type leader struct {
+	User  domain.User
+	Wins  int
+	Share float64
+	Color int
+}
+
+// records рекорды номинации; нулевые значения означают, что выборов еще не было
+type records struct {
+	Total      int
+	First      time.Time
+	StreakUser domain.User
+	Streak     int
+	MonthUser  domain.User
+	MonthWins  int
+	Month      time.Time
+}
+
+// calendarDay день календаря; Day 0 — пустая клетка до начала или после конца месяца
+type calendarDay struct {
+	Day    int
+	Date   time.Time
+	Winner *domain.User
+	Color  int
+}
+
+// calendarMonth месяц календаря по неделям с понедельника
+type calendarMonth struct {
+	Month time.Time
+	Weeks [][]calendarDay
+}
+
+// report данные страницы одной номинации
+type report struct {
+	Leaders  []leader
+	Records  records
+	Calendar []calendarMonth
+}
+
+// buildReport строит таблицу лидеров, рекорды и календарь номинации.
+// stats — участники с победами по убыванию, history — выборы чата по возрастанию даты.
+func buildReport(stats []domain.UserStats, history []domain.PersonOfTheDayRecord, nominationID int64, now time.Time) report {
+	var result report
+
+	var draws []domain.PersonOfTheDayRecord
+	for _, record := range history {
+		if record.NominationID == nominationID {
+			draws = append(draws, record)
+		}
+	}
+
+	colors := make(map[int64]int)
+	for i, stat := range stats {
+		color := 0
+		if i < paletteSize && stat.Count > 0 {
+			color = i + 1
+		}
+		colors[stat.User.ID] = color
+
+		var share float64
+		if len(draws) > 0 {
+			share = float64(stat.Count) * 100 / float64(len(draws))
+		}
+		result.Leaders = append(result.Leaders, leader{User: stat.User, Wins: stat.Count, Share: share, Color: color})
+	}
+
+	result.Records = buildRecords(draws)
+	result.Calendar = buildCalendar(draws, colors, now)
+	return result
+}
+
+// buildRecords считает самую длинную серию побед подряд и лучший месяц участника
+func buildRecords(draws []domain.PersonOfTheDayRecord) records {
+	var result records
+	if len(draws) == 0 {
+		return result
+	}
+	result.Total = len(draws)
+	result.First = day(draws[0].Date)
+
+	type monthKey struct {
+		userID int64
+		month  time.Time
+	}
+	monthWins := make(map[monthKey]int)
+
+	streak := 0
+	for i, draw := range draws {
+		date := day(draw.Date)
+		if i > 0 && draw.UserID == draws[i-1].UserID && day(draws[i-1].Date).AddDate(0, 0, 1).Equal(date) {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak > result.Streak {
+			result.Streak = streak
+			result.StreakUser = draw.User
+		}
+
+		key := monthKey{userID: draw.UserID, month: month(date)}
+		monthWins[key]++
+		// При равенстве рекордом остается более ранний месяц
+		if wins := monthWins[key]; wins > result.MonthWins {
+			result.MonthWins = wins
+			result.MonthUser = draw.User
+			result.Month = key.month
+		}
+	}
+
+	return result
+}
+
+// buildCalendar раскладывает выборы последних CalendarMonths месяцев по неделям
+func buildCalendar(draws []domain.PersonOfTheDayRecord, colors map[int64]int, now time.Time) []calendarMonth {
+	winners := make(map[time.Time]domain.User, len(draws))
+	for _, draw := range draws {
+		winners[day(draw.Date)] = draw.User
+	}
+
+	current := month(day(now))
+	months := make([]calendarMonth, 0, CalendarMonths)
+	for i := 0; i < CalendarMonths; i++ {
+		start := current.AddDate(0, -i, 0)
+		calendar := calendarMonth{Month: start}
+
+		// Неделя начинается с понедельника: Weekday воскресенья 0 становится 6
+		week := make([]calendarDay, (int(start.Weekday())+6)%7)
+		for date := start; date.Month() == start.Month(); date = date.AddDate(0, 0, 1) {
+			cell := calendarDay{Day: date.Day(), Date: date}
+			if winner, ok := winners[date]; ok {
+				cell.Winner = &winner
+				cell.Color = colors[winner.ID]
+			}
+			week = append(week, cell)
+			if len(week) == 7 {
+				calendar.Weeks = append(calendar.Weeks, week)
+				week = nil
+			}
+		}
+		if len(week) > 0 {
+			calendar.Weeks = append(calendar.Weeks, append(week, make([]calendarDay, 7-len(week))...))
+		}
+
+		months = append(months, calendar)
+	}
+
+	return months
+}
+
+// day возвращает календарную дату выбора в часовом поясе по умолчанию
+func day(date time.Time) time.Time {
+	year, month, dayOfMonth := date.Date()
+	return time.Date(year, month, dayOfMonth, 0, 0, 0, 0, time.Local)
+}
+
+// month возвращает первое число месяца даты
+func month(date time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,343 @@
+package web
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"html/template"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+)
+
+// BasePath префикс всех путей веб-страницы
+const BasePath = "/web"
+
+// StaticPath путь встроенных стилей страницы
+const StaticPath = BasePath + "/static/"
+
+// nominationParam параметр номинации страницы; в подпись ссылки не входит
+const nominationParam = "nomination"
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+//go:embed static
+var staticFS embed.FS
+
+// pageTemplates шаблоны страниц, разбираются один раз при запуске
+var pageTemplates = template.Must(template.ParseFS(templatesFS, "templates/*.html"))
+
+// Handler отдает страницу статистики чата по подписанной ссылке из /pidorstats web.
+// Все тексты страницы берутся из шаблонов сообщений на языке чата.
+type Handler struct {
+	persons        repository.PersonOfTheDayRepository
+	nominations    repository.NominationRepository
+	settings       repository.ChatSettingsRepository
+	messageService *templates.MessageService
+	links          *Links
+	clock          clock.Clock
+	logger         *slog.Logger
+	mux            *http.ServeMux
+}
+
+// NewHandler создает обработчик веб-страницы статистики
+func NewHandler(
+	persons repository.PersonOfTheDayRepository,
+	nominations repository.NominationRepository,
+	settings repository.ChatSettingsRepository,
+	messageService *templates.MessageService,
+	links *Links,
+	clk clock.Clock,
+	logger *slog.Logger,
+) *Handler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	h := &Handler{
+		persons:        persons,
+		nominations:    nominations,
+		settings:       settings,
+		messageService: messageService,
+		links:          links,
+		clock:          clock.OrSystem(clk),
+		logger:         logger.With("component", "web"),
+		mux:            http.NewServeMux(),
+	}
+
+	static, _ := fs.Sub(staticFS, "static")
+	h.mux.Handle("GET "+StaticPath, http.StripPrefix(StaticPath, cacheStatic(http.FileServerFS(static))))
+	h.mux.HandleFunc("GET "+BasePath+"/chats/{chat}", h.handleChat)
+
+	return h
+}
+
+// ServeHTTP направляет запрос обработчику пути
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(rw, r)
+}
+
+// tab ссылка на страницу номинации
+type tab struct {
+	Title  string
+	URL    string
+	Active bool
+}
+
+// leaderView строка таблицы лидеров на странице
+type leaderView struct {
+	Rank  int
+	Name  string
+	Wins  int
+	Share string
+	Color int
+}
+
+// dayView клетка календаря на странице
+type dayView struct {
+	Day   int
+	Title string
+	Color int
+	Won   bool
+}
+
+// monthView месяц календаря на странице
+type monthView struct {
+	Title string
+	Weeks [][]dayView
+}
+
+// pageData данные шаблона страницы чата
+type pageData struct {
+	Lang          string
+	StaticPath    string
+	Heading       string
+	Tabs          []tab
+	Leaderboard   string
+	ColumnUser    string
+	ColumnWins    string
+	ColumnShare   string
+	Leaders       []leaderView
+	Empty         string
+	RecordsTitle  string
+	Records       []string
+	CalendarTitle string
+	Weekdays      []string
+	Months        []monthView
+	Expires       string
+}
+
+// errorData данные шаблона страницы ошибки
+type errorData struct {
+	Lang       string
+	StaticPath string
+	Message    string
+}
+
+// handleChat отдает страницу статистики номинации чата
+func (h *Handler) handleChat(rw http.ResponseWriter, r *http.Request) {
+	chatID, err := strconv.ParseInt(r.PathValue("chat"), 10, 64)
+	if err != nil {
+		http.NotFound(rw, r)
+		return
+	}
+
+	messages := h.chatMessages(chatID)
+	query := r.URL.Query()
+	expires, err := h.links.Verify(chatID, query)
+	if err != nil {
+		h.logger.Debug("Отклонена ссылка на страницу", "chat_id", chatID, "error", err)
+		h.render(rw, http.StatusForbidden, "error.html", errorData{
+			Lang:       string(messages.Locale()),
+			StaticPath: StaticPath,
+			Message:    messages.WebLinkInvalid(),
+		})
+		return
+	}
+
+	data, err := h.page(chatID, query, messages)
+	if err != nil {
+		var notFound errNominationNotFound
+		if errors.As(err, &notFound) {
+			http.NotFound(rw, r)
+			return
+		}
+		h.logger.Error("Ошибка построения страницы", "chat_id", chatID, "error", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	data.Expires = messages.WebExpires(expires)
+
+	h.render(rw, http.StatusOK, "page.html", data)
+}
+
+// errNominationNotFound номинация из параметра страницы не найдена
+type errNominationNotFound struct {
+	command string
+}
+
+func (e errNominationNotFound) Error() string {
+	return "nomination " + e.command + " not found"
+}
+
+// page собирает данные страницы номинации из параметра nomination
+func (h *Handler) page(chatID int64, query url.Values, messages *templates.MessageService) (pageData, error) {
+	nominations, err := h.nominations.GetByChatID(chatID)
+	if err != nil {
+		return pageData{}, err
+	}
+
+	nomination := domain.Nomination{ID: domain.DefaultNominationID, ChatID: chatID, Command: domain.DefaultNominationCommand}
+	command := strings.ToLower(strings.TrimSpace(query.Get(nominationParam)))
+	if command != "" && command != domain.DefaultNominationCommand {
+		found := false
+		for _, candidate := range nominations {
+			if candidate.Command == command {
+				nomination, found = candidate, true
+				break
+			}
+		}
+		if !found {
+			return pageData{}, errNominationNotFound{command: command}
+		}
+	}
+
+	stats, err := h.persons.GetUserStats(chatID, nomination.ID)
+	if err != nil {
+		return pageData{}, err
+	}
+	history, err := h.persons.GetHistory(chatID)
+	if err != nil {
+		return pageData{}, err
+	}
+	result := buildReport(stats, history, nomination.ID, h.clock.Now())
+
+	nominationMessages := messages.ForNomination(nomination)
+	data := pageData{
+		Lang:          string(messages.Locale()),
+		StaticPath:    StaticPath,
+		Heading:       nominationMessages.WebHeading(),
+		Leaderboard:   messages.WebLeaderboard(),
+		ColumnUser:    messages.WebColumnUser(),
+		ColumnWins:    messages.WebColumnWins(),
+		ColumnShare:   messages.WebColumnShare(),
+		RecordsTitle:  messages.WebRecords(),
+		CalendarTitle: messages.WebCalendar(),
+		Weekdays:      messages.WebWeekdays(),
+	}
+
+	// Вкладки номинаций сохраняют подпись ссылки, меняется только параметр nomination
+	if len(nominations) > 0 {
+		all := append([]domain.Nomination{{ID: domain.DefaultNominationID, ChatID: chatID, Command: domain.DefaultNominationCommand}}, nominations...)
+		for _, candidate := range all {
+			tabQuery := url.Values{}
+			for _, name := range []string{expiresParam, signatureParam} {
+				tabQuery.Set(name, query.Get(name))
+			}
+			if !candidate.IsDefault() {
+				tabQuery.Set(nominationParam, candidate.Command)
+			}
+			data.Tabs = append(data.Tabs, tab{
+				Title:  messages.ForNomination(candidate).WebHeading(),
+				URL:    ChatPath(chatID) + "?" + tabQuery.Encode(),
+				Active: candidate.ID == nomination.ID,
+			})
+		}
+	}
+
+	for i, leader := range result.Leaders {
+		data.Leaders = append(data.Leaders, leaderView{
+			Rank:  i + 1,
+			Name:  leader.User.DisplayName(),
+			Wins:  leader.Wins,
+			Share: messages.WebShare(leader.Share),
+			Color: leader.Color,
+		})
+	}
+
+	if records := result.Records; records.Total > 0 {
+		data.Records = []string{
+			messages.WebRecordTotal(records.Total),
+			messages.WebRecordFirst(records.First),
+			messages.WebRecordStreak(records.StreakUser, records.Streak),
+			messages.WebRecordMonth(records.MonthUser, records.MonthWins, records.Month),
+		}
+	} else {
+		data.Empty = messages.WebEmpty()
+	}
+
+	for _, calendar := range result.Calendar {
+		view := monthView{Title: messages.WebCalendarMonth(calendar.Month)}
+		for _, week := range calendar.Weeks {
+			days := make([]dayView, 0, len(week))
+			for _, cell := range week {
+				item := dayView{Day: cell.Day, Color: cell.Color}
+				if cell.Winner != nil {
+					item.Won = true
+					item.Title = cell.Date.Format("2006-01-02") + " — " + cell.Winner.DisplayName()
+				}
+				days = append(days, item)
+			}
+			view.Weeks = append(view.Weeks, days)
+		}
+		data.Months = append(data.Months, view)
+	}
+
+	return data, nil
+}
+
+// chatMessages возвращает сервис сообщений с языком и названием роли чата
+func (h *Handler) chatMessages(chatID int64) *templates.MessageService {
+	messages := h.messageService.WithLocale(h.messageService.DefaultLocale())
+
+	settings, err := h.settings.Get(chatID)
+	if err != nil {
+		h.logger.Error("Ошибка получения настроек чата", "chat_id", chatID, "error", err)
+		return messages
+	}
+	if settings == nil {
+		return messages
+	}
+
+	if locale, ok := templates.ParseLocale(settings.Language); ok {
+		messages = h.messageService.WithLocale(locale)
+	}
+	return messages.WithTitle(settings.Title, settings.Emoji)
+}
+
+// render выполняет шаблон страницы. Страница открывается по секретной ссылке,
+// поэтому она не кешируется, не индексируется и не передает ссылку в Referer.
+func (h *Handler) render(rw http.ResponseWriter, code int, name string, data any) {
+	var buf bytes.Buffer
+	if err := pageTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		h.logger.Error("Ошибка шаблона страницы", "template", name, "error", err)
+		http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	header := rw.Header()
+	header.Set("Content-Type", "text/html; charset=utf-8")
+	header.Set("Cache-Control", "private, no-store")
+	header.Set("Referrer-Policy", "no-referrer")
+	header.Set("X-Robots-Tag", "noindex, nofollow")
+	header.Set("Content-Security-Policy", "default-src 'none'; style-src 'self'; img-src 'self'")
+	rw.WriteHeader(code)
+	_, _ = buf.WriteTo(rw)
+}
+
+// cacheStatic разрешает кешировать встроенные стили: они меняются только с новой версией бота
+func cacheStatic(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Cache-Control", "public, max-age=86400")
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -1,59 +1,316 @@
 package main
 
 import (
-	"log"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 	"time"
 
+	httpapi "github.com/pavel-one/day-of-the-bot/internal/api"
+	"github.com/pavel-one/day-of-the-bot/internal/backup"
 	"github.com/pavel-one/day-of-the-bot/internal/bot"
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
 	"github.com/pavel-one/day-of-the-bot/internal/config"
+	"github.com/pavel-one/day-of-the-bot/internal/handlers"
+	"github.com/pavel-one/day-of-the-bot/internal/health"
+	"github.com/pavel-one/day-of-the-bot/internal/httpserver"
+	"github.com/pavel-one/day-of-the-bot/internal/logging"
+	"github.com/pavel-one/day-of-the-bot/internal/metrics"
 	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/repository/memory"
 	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"github.com/pavel-one/day-of-the-bot/internal/web"
 	"gopkg.in/telebot.v3"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_PATH"), "путь к YAML файлу конфигурации")
+	printConfig := flag.Bool("print-config", false, "вывести итоговую конфигурацию без токена и выйти")
+	dryRun := flag.Bool("dry-run", false, "хранить данные в памяти, не открывая базу данных: после остановки все теряется")
+	flag.Parse()
+
+	if *printConfig {
+		os.Exit(runPrintConfig(*configPath))
+	}
+
+	switch command := flag.Arg(0); command {
+	case "":
+	case "backup":
+		os.Exit(runBackup(*configPath, flag.Args()[1:]))
+	case "restore":
+		os.Exit(runRestore(*configPath, flag.Args()[1:]))
+	case "export":
+		os.Exit(runExport(*configPath, flag.Args()[1:]))
+	case "import":
+		os.Exit(runImport(*configPath, flag.Args()[1:]))
+	case "healthcheck":
+		os.Exit(runHealthcheck(*configPath, flag.Args()[1:]))
+	default:
+		fmt.Fprintf(os.Stderr, "Неизвестная команда %q, доступны: backup, restore, export, import, healthcheck\n", command)
+		os.Exit(2)
+	}
+
+	os.Exit(runBot(*configPath, *dryRun))
+}
+
+// runBot запускает бота и возвращает код завершения процесса.
+// Процесс завершается только в main, чтобы отложенные вызовы успели закрыть хранилище.
+func runBot(configPath string, dryRun bool) int {
 	// Загружаем конфигурацию
-	cfg, err := config.Load()
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфигурации: %v\n", err)
+		return 1
+	}
+
+	logger, err := logging.New(os.Stderr, cfg.LogLevel(), cfg.Log.Format)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка создания логгера: %v\n", err)
+		return 1
+	}
+	slog.SetDefault(logger)
+
+	// Все даты выбора считаются в часовом поясе по умолчанию: системные часы
+	// возвращают время в time.Local
+	time.Local = cfg.Location()
+	systemClock := clock.System()
+
+	// Инициализируем хранилище: базу данных или, в режиме --dry-run, память процесса
+	storage, err := openRepositories(cfg, dryRun, systemClock, logger)
+	if err != nil {
+		logger.Error("Ошибка инициализации базы данных", "error", err)
+		return 1
+	}
+	defer storage.close(logger)
+
+	// Источник обновлений: long polling или вебхук
+	poller, err := bot.NewPoller(cfg.Telegram, logger)
 	if err != nil {
-		log.Fatalf("Ошибка загрузки конфигурации: %v", err)
+		logger.Error("Ошибка создания источника обновлений", "error", err)
+		return 1
 	}
 
 	// Создаем настройки для telebot
 	settings := telebot.Settings{
+		URL:    cfg.Telegram.APIURL,
 		Token:  cfg.BotToken,
-		Poller: &telebot.LongPoller{Timeout: 10 * time.Second},
+		Poller: poller,
+		// Подробный вывод запросов к Telegram API только в режиме отладки
+		Verbose: cfg.Debug,
+	}
+
+	// Проверки состояния: база данных и, в режиме long polling, успешные getUpdates.
+	// Клиент Telegram отмечает успешные getUpdates через обертку транспорта.
+	var httpServer *httpserver.Server
+	if cfg.HTTP.Enabled {
+		threshold := cfg.HTTP.UpdatesThreshold
+		if cfg.Telegram.Mode == config.ModeWebhook {
+			threshold = 0
+		}
+		checker := health.NewChecker(storage.pinger, threshold, systemClock)
+		// Таймаут как у клиента telebot по умолчанию
+		settings.Client = &http.Client{Timeout: time.Minute, Transport: checker.Transport(nil)}
+
+		httpServer = httpserver.New("http", cfg.HTTP.Listen, logger)
+		checker.Register(httpServer)
+		if err := httpServer.Listen(); err != nil {
+			logger.Error("Ошибка запуска HTTP сервера", "error", err)
+			return 1
+		}
 	}
 
 	// Инициализируем бота
 	api, err := telebot.NewBot(settings)
 	if err != nil {
-		log.Fatalf("Ошибка создания бота: %v", err)
+		logger.Error("Ошибка создания бота", "error", err)
+		return 1
 	}
 
-	log.Printf("Авторизован как %s", api.Me.Username)
+	logger.Info("Авторизован", "username", api.Me.Username)
 
-	// Инициализируем базу данных
-	db, err := repository.NewDatabase(cfg.DBPath)
-	if err != nil {
-		log.Fatalf("Ошибка инициализации базы данных: %v", err)
+	// Репозитории выбранного хранилища
+	userRepo := storage.users
+	personOfTheDayRepo := storage.persons
+	chatSettingsRepo := storage.settings
+	nominationRepo := storage.nominations
+	aliasRepo := storage.aliases
+	// Без HTTP API команда /pidorapi сообщает, что оно выключено
+	var apiTokenRepo repository.APITokenRepository
+	if cfg.API.Enabled {
+		apiTokenRepo = storage.tokens
 	}
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Printf("Ошибка закрытия базы данных: %v", err)
+
+	// Метрики замеряют время запросов к репозиториям и учитывают обновления
+	var botMetrics bot.Metrics
+	var metricsServer *httpserver.Server
+	if cfg.Metrics.Enabled {
+		collector := metrics.New(systemClock)
+		botMetrics = collector
+
+		userRepo = collector.WrapUserRepository(userRepo)
+		personOfTheDayRepo = collector.WrapPersonOfTheDayRepository(personOfTheDayRepo)
+		chatSettingsRepo = collector.WrapChatSettingsRepository(chatSettingsRepo)
+		nominationRepo = collector.WrapNominationRepository(nominationRepo)
+		aliasRepo = collector.WrapCommandAliasRepository(aliasRepo)
+		if apiTokenRepo != nil {
+			apiTokenRepo = collector.WrapAPITokenRepository(apiTokenRepo)
 		}
-	}()
 
-	// Создаем репозитории
-	userRepo := repository.NewUserRepository(db)
-	personOfTheDayRepo := repository.NewPersonOfTheDayRepository(db)
+		metricsServer = httpserver.New("metrics", cfg.Metrics.Listen, logger)
+		metricsServer.Handle(cfg.Metrics.Path, collector.Handler())
+		if err := metricsServer.Listen(); err != nil {
+			logger.Error("Ошибка запуска сервера метрик", "error", err)
+			return 1
+		}
+	}
+
+	// HTTP API статистики работает на HTTP сервере бота
+	if apiTokenRepo != nil {
+		httpServer.Handle(httpapi.BasePath+"/", httpapi.NewHandler(personOfTheDayRepo, nominationRepo, apiTokenRepo, systemClock, logger))
+	}
 
 	// Создаем сервис сообщений
 	messageService, err := templates.NewMessageService()
 	if err != nil {
-		log.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+		logger.Error("Ошибка создания сервиса сообщений", "error", err)
+		return 1
+	}
+	messageService = messageService.WithDefaultLocale(cfg.Locale())
+
+	// Веб-страница статистики по подписанным ссылкам из /pidorstats web
+	var webLinks handlers.WebLinks
+	if cfg.Web.Enabled {
+		links, err := web.NewLinks(cfg.Web.PublicURL, cfg.Web.Secret, cfg.Web.LinkTTL, systemClock)
+		if err != nil {
+			logger.Error("Ошибка создания ссылок на веб-страницу", "error", err)
+			return 1
+		}
+		if cfg.Web.Secret == "" {
+			logger.Warn("Ключ подписи ссылок web.secret не задан, ссылки перестанут действовать после перезапуска")
+		}
+		webLinks = links
+		httpServer.Handle(web.BasePath+"/", web.NewHandler(personOfTheDayRepo, nominationRepo, chatSettingsRepo, messageService, links, systemClock, logger))
 	}
 
 	// Создаем и запускаем бота
-	botInstance := bot.NewBot(api, userRepo, personOfTheDayRepo, messageService)
-	botInstance.Start()
+	botInstance := bot.NewBot(api, userRepo, personOfTheDayRepo, chatSettingsRepo, nominationRepo, aliasRepo, apiTokenRepo, webLinks, messageService, systemClock, botMetrics, logger)
+
+	// Фоновые задачи работают до остановки бота
+	ctx, cancel := context.WithCancel(context.Background())
+	var background sync.WaitGroup
+	if cfg.Backup.Enabled && storage.db == nil {
+		logger.Warn("Резервное копирование выключено в режиме --dry-run")
+	} else if cfg.Backup.Enabled {
+		manager := backup.NewManager(storage.db, cfg.Backup.Dir, cfg.Backup.Retention, systemClock, logger)
+		background.Go(func() { manager.Start(ctx, cfg.Backup.Interval) })
+	}
+	if metricsServer != nil {
+		background.Go(func() { metricsServer.Serve(ctx) })
+	}
+	if httpServer != nil {
+		background.Go(func() { httpServer.Serve(ctx) })
+	}
+	defer func() {
+		cancel()
+		background.Wait()
+	}()
+
+	// Останавливаемся по SIGINT/SIGTERM, чтобы удалить вебхук и закрыть базу данных
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		logger.Info("Получен сигнал остановки", "signal", sig.String())
+		botInstance.Stop()
+	}()
+
+	if err := botInstance.Start(); err != nil {
+		logger.Error("Ошибка запуска бота", "error", err)
+		return 1
+	}
+
+	return 0
+}
+
+// repositories репозитории хранилища, с которым работает бот
+type repositories struct {
+	// db база данных; nil в режиме --dry-run
+	db          *repository.Database
+	pinger      health.Pinger
+	users       repository.UserRepository
+	persons     repository.PersonOfTheDayRepository
+	settings    repository.ChatSettingsRepository
+	nominations repository.NominationRepository
+	aliases     repository.CommandAliasRepository
+	tokens      repository.APITokenRepository
+}
+
+// openRepositories открывает базу данных из конфигурации. В режиме dryRun база
+// не открывается: репозитории хранят данные в памяти и ничего не пишут на диск.
+func openRepositories(cfg *config.Config, dryRun bool, clk clock.Clock, logger *slog.Logger) (*repositories, error) {
+	if dryRun {
+		logger.Warn("Режим --dry-run: данные хранятся в памяти и будут потеряны после остановки")
+		store := memory.NewStore(clk)
+		return &repositories{
+			pinger:      store,
+			users:       memory.NewUserRepository(store),
+			persons:     memory.NewPersonOfTheDayRepository(store),
+			settings:    memory.NewChatSettingsRepository(store),
+			nominations: memory.NewNominationRepository(store),
+			aliases:     memory.NewCommandAliasRepository(store),
+			tokens:      memory.NewAPITokenRepository(store),
+		}, nil
+	}
+
+	db, err := repository.Open(cfg.Storage.Driver, cfg.Storage.DataSource(), cfg.Storage.Options(), logger)
+	if err != nil {
+		return nil, err
+	}
+	return &repositories{
+		db:          db,
+		pinger:      db,
+		users:       repository.NewUserRepository(db),
+		persons:     repository.NewPersonOfTheDayRepository(db),
+		settings:    repository.NewChatSettingsRepository(db),
+		nominations: repository.NewNominationRepository(db),
+		aliases:     repository.NewCommandAliasRepository(db),
+		tokens:      repository.NewAPITokenRepository(db),
+	}, nil
+}
+
+// close закрывает базу данных, если она открыта; ошибка только логируется
+func (r *repositories) close(logger *slog.Logger) {
+	if r.db != nil {
+		closeStorage(r.db, logger)
+	}
+}
+
+// runPrintConfig выводит итоговую конфигурацию со скрытым токеном.
+// Ошибки проверки выводятся в stderr, код возврата отличен от нуля.
+func runPrintConfig(path string) int {
+	cfg, err := config.Read(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка загрузки конфигурации: %v\n", err)
+		return 1
+	}
+
+	output, err := cfg.Redacted()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка вывода конфигурации: %v\n", err)
+		return 1
+	}
+	fmt.Print(output)
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибки конфигурации:\n%v\n", err)
+		return 1
+	}
+
+	return 0
 }
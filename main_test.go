@@ -1,12 +1,53 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+	"unicode"
+	// Тесты смены дня не зависят от базы часовых поясов системы
+	_ "time/tzdata"
 
+	"github.com/pavel-one/day-of-the-bot/internal/api"
+	"github.com/pavel-one/day-of-the-bot/internal/backup"
+	"github.com/pavel-one/day-of-the-bot/internal/bot"
+	"github.com/pavel-one/day-of-the-bot/internal/clock"
+	"github.com/pavel-one/day-of-the-bot/internal/config"
+	"github.com/pavel-one/day-of-the-bot/internal/core"
 	"github.com/pavel-one/day-of-the-bot/internal/domain"
+	"github.com/pavel-one/day-of-the-bot/internal/handlers"
+	"github.com/pavel-one/day-of-the-bot/internal/health"
+	"github.com/pavel-one/day-of-the-bot/internal/history"
+	"github.com/pavel-one/day-of-the-bot/internal/httpserver"
+	"github.com/pavel-one/day-of-the-bot/internal/logging"
+	"github.com/pavel-one/day-of-the-bot/internal/metrics"
+	"github.com/pavel-one/day-of-the-bot/internal/render"
 	"github.com/pavel-one/day-of-the-bot/internal/repository"
+	"github.com/pavel-one/day-of-the-bot/internal/repository/memory"
+	"github.com/pavel-one/day-of-the-bot/internal/telegramtest"
+	"github.com/pavel-one/day-of-the-bot/internal/templates"
+	"github.com/pavel-one/day-of-the-bot/internal/web"
+	"gopkg.in/telebot.v3"
 )
 
 func TestDatabase(t *testing.T) {
@@ -18,7 +59,7 @@ func TestDatabase(t *testing.T) {
 		}
 	}()
 
-	db, err := repository.NewDatabase(dbPath)
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
 	if err != nil {
 		t.Fatalf("Ошибка создания базы данных: %v", err)
 	}
@@ -62,13 +103,13 @@ func TestDatabase(t *testing.T) {
 
 	// Тестируем установку человека дня
 	now := time.Now()
-	err = personRepo.Set(user.ID, user.ChatID, now)
+	err = personRepo.Set(user.ID, user.ChatID, domain.DefaultNominationID, now)
 	if err != nil {
 		t.Fatalf("Ошибка установки человека дня: %v", err)
 	}
 
 	// Тестируем получение сегодняшнего человека дня
-	todayPerson, err := personRepo.GetByDate(user.ChatID, now)
+	todayPerson, err := personRepo.GetByDate(user.ChatID, domain.DefaultNominationID, now)
 	if err != nil {
 		t.Fatalf("Ошибка получения человека дня: %v", err)
 	}
@@ -82,7 +123,7 @@ func TestDatabase(t *testing.T) {
 	}
 
 	// Тестируем получение статистики
-	stats, err := personRepo.GetUserStats(user.ChatID)
+	stats, err := personRepo.GetUserStats(user.ChatID, domain.DefaultNominationID)
 	if err != nil {
 		t.Fatalf("Ошибка получения статистики: %v", err)
 	}
@@ -145,3 +186,2996 @@ func TestFormatUserName(t *testing.T) {
 		})
 	}
 }
+
+func TestChatSettings(t *testing.T) {
+	dbPath := "test_settings.db"
+	defer func() {
+		if err := os.Remove(dbPath); err != nil {
+			t.Logf("Не удалось удалить тестовую БД: %v", err)
+		}
+	}()
+
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка создания базы данных: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+
+	settingsRepo := repository.NewChatSettingsRepository(db)
+	chatID := int64(-123456789)
+
+	settings, err := settingsRepo.Get(chatID)
+	if err != nil {
+		t.Fatalf("Ошибка получения настроек чата: %v", err)
+	}
+	if settings != nil {
+		t.Fatalf("Настройки нового чата должны быть пустыми, получено %+v", settings)
+	}
+
+	for _, language := range []string{"en", "uk"} {
+		if err := settingsRepo.SetLanguage(chatID, language); err != nil {
+			t.Fatalf("Ошибка установки языка %s: %v", language, err)
+		}
+
+		settings, err = settingsRepo.Get(chatID)
+		if err != nil {
+			t.Fatalf("Ошибка получения настроек чата: %v", err)
+		}
+		if settings == nil || settings.Language != language {
+			t.Errorf("Ожидался язык %s, получено %+v", language, settings)
+		}
+	}
+
+	if err := settingsRepo.SetTitle(chatID, "Герой дня", "🦸"); err != nil {
+		t.Fatalf("Ошибка установки названия роли: %v", err)
+	}
+
+	settings, err = settingsRepo.Get(chatID)
+	if err != nil {
+		t.Fatalf("Ошибка получения настроек чата: %v", err)
+	}
+	if settings.Title != "Герой дня" || settings.Emoji != "🦸" || settings.Language != "uk" {
+		t.Errorf("Ожидались название 'Герой дня', эмодзи '🦸' и язык uk, получено %+v", settings)
+	}
+}
+
+func TestChatTitle(t *testing.T) {
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+
+	user := domain.User{FirstName: "Иван"}
+
+	expected := "🎉 Пидор дня выбран!\n\n🎯 Иван\n\nПоздравляем! 🎊"
+	if result := service.PersonSelected(user); result != expected {
+		t.Errorf("Ожидалось '%s', получено '%s'", expected, result)
+	}
+
+	hero := service.WithTitle("Герой дня", "🦸")
+	expected = "🎉 Герой дня выбран!\n\n🦸 Иван\n\nПоздравляем! 🎊"
+	if result := hero.PersonSelected(user); result != expected {
+		t.Errorf("Ожидалось '%s', получено '%s'", expected, result)
+	}
+
+	expected = "🦸 Hero of the day today: Иван"
+	if result := hero.WithLocale(templates.LocaleEn).WithTitle("Hero of the day", "🦸").ChatInfo(1, 1, &user); !strings.HasSuffix(result, expected) {
+		t.Errorf("Ожидалось окончание '%s', получено '%s'", expected, result)
+	}
+
+	if result := hero.WithLocale(templates.LocaleEn).StatsEmpty(); result == "" {
+		t.Error("Сообщение не должно быть пустым")
+	}
+}
+
+func TestLocalizedStats(t *testing.T) {
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+
+	user := domain.User{FirstName: "Иван"}
+	tests := []struct {
+		locale   templates.Locale
+		count    int
+		expected string
+	}{
+		{templates.LocaleRu, 1, "🥇 Иван - 1 раз\n"},
+		{templates.LocaleRu, 3, "🥇 Иван - 3 раза\n"},
+		{templates.LocaleRu, 12, "🥇 Иван - 12 раз\n"},
+		{templates.LocaleRu, 22, "🥇 Иван - 22 раза\n"},
+		{templates.LocaleUk, 5, "🥇 Иван - 5 разів\n"},
+		{templates.LocaleEn, 1, "🥇 Иван - 1 time\n"},
+		{templates.LocaleEn, 2, "🥇 Иван - 2 times\n"},
+		{templates.Locale("de"), 2, "🥇 Иван - 2 раза\n"},
+	}
+
+	for _, tt := range tests {
+		messages := service.WithLocale(tt.locale)
+		result := messages.BuildStatsMessage([]domain.UserStats{{User: user, Count: tt.count}})
+		header := messages.BuildStatsMessage(nil)
+
+		if result != header+tt.expected {
+			t.Errorf("%s/%d: ожидалось '%s', получено '%s'", tt.locale, tt.count, header+tt.expected, result)
+		}
+	}
+}
+
+func TestTypedPlaceholders(t *testing.T) {
+	date := time.Date(2024, time.March, 8, 12, 0, 0, 0, time.UTC)
+	data := templates.TemplateData{"count": 1234, "avg": 2.5, "date": date}
+
+	tests := []struct {
+		template string
+		locale   templates.Locale
+		expected string
+	}{
+		{"{{count|number}} {{count|plural:раз,раза,раз}}", templates.LocaleRu, "1\u00a0234 раза"},
+		{"{{count|number}} {{count|plural:time,times}}", templates.LocaleEn, "1,234 times"},
+		{"{{avg|number:2}}", templates.LocaleRu, "2,50"},
+		{"{{avg|number:1}}", templates.LocaleEn, "2.5"},
+		{"{{date|date:short}}", templates.LocaleRu, "08.03.2024"},
+		{"{{date|date:short}}", templates.LocaleEn, "Mar 8, 2024"},
+		{"{{date|date:2006-01-02}}", templates.LocaleUk, "2024-03-08"},
+		{"{{missing}}", templates.LocaleRu, ""},
+	}
+
+	for _, tt := range tests {
+		template, err := templates.NewLocaleTemplate(tt.template, tt.locale)
+		if err != nil {
+			t.Fatalf("Ошибка создания шаблона %s: %v", tt.template, err)
+		}
+
+		if result := template.Execute(data); result != tt.expected {
+			t.Errorf("%s: ожидалось '%s', получено '%s'", tt.template, tt.expected, result)
+		}
+	}
+
+	invalid := []string{
+		"{{count|plural:раз,раза}}",
+		"{{date|date:}}",
+		"{{date|date:сегодня}}",
+		"{{count|number:x}}",
+		"{{count|money}}",
+		"{{|number}}",
+	}
+
+	for _, templateStr := range invalid {
+		if _, err := templates.NewTemplate(templateStr); err == nil {
+			t.Errorf("Ожидалась ошибка валидации для %s", templateStr)
+		}
+	}
+}
+
+func TestNominations(t *testing.T) {
+	dbPath := "test_nominations.db"
+	defer func() {
+		if err := os.Remove(dbPath); err != nil {
+			t.Logf("Не удалось удалить тестовую БД: %v", err)
+		}
+	}()
+
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка создания базы данных: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	nominationRepo := repository.NewNominationRepository(db)
+
+	chatID := int64(-123456789)
+	alice := domain.User{ID: 1, FirstName: "Alice", ChatID: chatID}
+	bob := domain.User{ID: 2, FirstName: "Bob", ChatID: chatID}
+	for _, user := range []domain.User{alice, bob} {
+		if err := userRepo.Add(user); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+	}
+
+	coffee := domain.Nomination{ChatID: chatID, Command: "coffee", Title: "Кофевар дня", Emoji: "☕", Strategy: domain.StrategyRotation}
+	if err := nominationRepo.Create(&coffee); err != nil {
+		t.Fatalf("Ошибка создания номинации: %v", err)
+	}
+	if coffee.ID == domain.DefaultNominationID {
+		t.Fatal("Номинации должен быть присвоен ID")
+	}
+
+	found, err := nominationRepo.GetByCommand(chatID, "coffee")
+	if err != nil {
+		t.Fatalf("Ошибка получения номинации: %v", err)
+	}
+	if found == nil || found.ID != coffee.ID || found.Strategy != domain.StrategyRotation || found.Emoji != "☕" {
+		t.Errorf("Ожидалась номинация %+v, получено %+v", coffee, found)
+	}
+
+	// В один день в разных номинациях могут быть разные победители
+	now := time.Now()
+	if err := personRepo.Set(alice.ID, chatID, domain.DefaultNominationID, now); err != nil {
+		t.Fatalf("Ошибка установки человека дня: %v", err)
+	}
+	if err := personRepo.Set(bob.ID, chatID, coffee.ID, now); err != nil {
+		t.Fatalf("Ошибка установки человека дня в номинации: %v", err)
+	}
+
+	for nominationID, expected := range map[int64]int64{domain.DefaultNominationID: alice.ID, coffee.ID: bob.ID} {
+		person, err := personRepo.GetByDate(chatID, nominationID, now)
+		if err != nil {
+			t.Fatalf("Ошибка получения человека дня: %v", err)
+		}
+		if person == nil || person.ID != expected {
+			t.Errorf("Номинация %d: ожидался пользователь %d, получено %+v", nominationID, expected, person)
+		}
+
+		stats, err := personRepo.GetUserStats(chatID, nominationID)
+		if err != nil {
+			t.Fatalf("Ошибка получения статистики: %v", err)
+		}
+		if len(stats) != 2 || stats[0].User.ID != expected || stats[0].Count != 1 || stats[1].Count != 0 {
+			t.Errorf("Номинация %d: неожиданная статистика %+v", nominationID, stats)
+		}
+	}
+
+	if err := nominationRepo.Delete(chatID, "coffee"); err != nil {
+		t.Fatalf("Ошибка удаления номинации: %v", err)
+	}
+
+	nominations, err := nominationRepo.GetByChatID(chatID)
+	if err != nil {
+		t.Fatalf("Ошибка получения номинаций: %v", err)
+	}
+	if len(nominations) != 0 {
+		t.Errorf("Ожидалось 0 номинаций, получено %d", len(nominations))
+	}
+
+	person, err := personRepo.GetByDate(chatID, coffee.ID, now)
+	if err != nil {
+		t.Fatalf("Ошибка получения человека дня: %v", err)
+	}
+	if person != nil {
+		t.Error("История удаленной номинации должна быть удалена")
+	}
+}
+
+func TestPersonOfTheDayNominationMigration(t *testing.T) {
+	dbPath := "test_migration.db"
+	defer func() {
+		if err := os.Remove(dbPath); err != nil {
+			t.Logf("Не удалось удалить тестовую БД: %v", err)
+		}
+	}()
+
+	// Создаем базу со схемой до появления номинаций
+	conn, err := sql.Open(repository.DefaultSQLiteDriver, dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	for _, query := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY, username TEXT, first_name TEXT NOT NULL, last_name TEXT,
+			chat_id INTEGER NOT NULL, created_at DATETIME DEFAULT CURRENT_TIMESTAMP, UNIQUE(id, chat_id))`,
+		`CREATE TABLE person_of_the_day (id INTEGER PRIMARY KEY AUTOINCREMENT, user_id INTEGER NOT NULL,
+			chat_id INTEGER NOT NULL, date DATE NOT NULL, created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+			FOREIGN KEY (user_id) REFERENCES users(id), UNIQUE(chat_id, date))`,
+		`INSERT INTO users (id, first_name, chat_id) VALUES (1, 'Alice', -1)`,
+		`INSERT INTO person_of_the_day (user_id, chat_id, date) VALUES (1, -1, '2024-01-01')`,
+		// Запись без пользователя: до включения внешних ключей такие могли появиться
+		`INSERT INTO person_of_the_day (user_id, chat_id, date) VALUES (2, -1, '2024-01-02')`,
+	} {
+		if _, err := conn.Exec(query); err != nil {
+			t.Fatalf("Ошибка подготовки базы данных: %v", err)
+		}
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("Ошибка закрытия базы данных: %v", err)
+	}
+
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка миграции базы данных: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	date := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+	person, err := personRepo.GetByDate(-1, domain.DefaultNominationID, date)
+	if err != nil {
+		t.Fatalf("Ошибка получения человека дня: %v", err)
+	}
+	if person == nil || person.ID != 1 {
+		t.Errorf("Ожидалось сохранение истории после миграции, получено %+v", person)
+	}
+
+	conn, err = sql.Open(repository.DefaultSQLiteDriver, dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	var count int
+	if err := conn.QueryRow(`SELECT COUNT(*) FROM person_of_the_day`).Scan(&count); err != nil {
+		t.Fatalf("Ошибка подсчета записей: %v", err)
+	}
+	_ = conn.Close()
+	if count != 2 {
+		t.Errorf("Миграция не должна терять записи без пользователя, осталось %d из 2", count)
+	}
+
+	if err := personRepo.Set(1, -1, 42, date); err != nil {
+		t.Errorf("После миграции должна быть возможна запись в другой номинации: %v", err)
+	}
+}
+
+func TestSQLiteOptions(t *testing.T) {
+	for _, driver := range repository.Drivers() {
+		if !repository.IsSQLite(driver) {
+			continue
+		}
+
+		t.Run(driver, func(t *testing.T) {
+			dir := t.TempDir()
+			dbPath := filepath.Join(dir, "options.db")
+
+			opts := repository.DefaultOptions()
+			opts.IntegrityCheck = true
+			db, err := repository.Open(driver, dbPath, opts, logging.Discard())
+			if err != nil {
+				t.Fatalf("Ошибка открытия базы данных: %v", err)
+			}
+			defer func() {
+				if err := db.Close(); err != nil {
+					t.Logf("Ошибка закрытия БД: %v", err)
+				}
+			}()
+
+			userRepo := repository.NewUserRepository(db)
+			personRepo := repository.NewPersonOfTheDayRepository(db)
+			date := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+			if err := userRepo.Add(domain.User{ID: 1, FirstName: "Alice", ChatID: -1}); err != nil {
+				t.Fatalf("Ошибка добавления пользователя: %v", err)
+			}
+			if err := personRepo.Set(1, -1, domain.DefaultNominationID, date); err != nil {
+				t.Fatalf("Ошибка записи человека дня: %v", err)
+			}
+
+			// WAL журнал создает файл рядом с базой
+			if _, err := os.Stat(dbPath + "-wal"); err != nil {
+				t.Errorf("Ожидался WAL журнал: %v", err)
+			}
+
+			// Внешние ключи проверяются: нельзя выбрать несуществующего пользователя
+			if err := personRepo.Set(2, -1, domain.DefaultNominationID, date.AddDate(0, 0, 1)); err == nil {
+				t.Error("Ожидалась ошибка внешнего ключа для несуществующего пользователя")
+			}
+
+			// Параллельные записи ждут блокировку вместо ошибки "database is locked"
+			var wg sync.WaitGroup
+			errs := make(chan error, 20)
+			for i := 0; i < 20; i++ {
+				wg.Add(1)
+				go func(i int) {
+					defer wg.Done()
+					errs <- userRepo.Add(domain.User{ID: int64(100 + i), FirstName: "User", ChatID: -1})
+				}(i)
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Errorf("Ошибка параллельной записи: %v", err)
+				}
+			}
+
+			// Проверка целостности отклоняет файл, не являющийся базой SQLite
+			brokenPath := filepath.Join(dir, "broken.db")
+			if err := os.WriteFile(brokenPath, bytes.Repeat([]byte("broken"), 1024), 0o600); err != nil {
+				t.Fatalf("Ошибка записи файла: %v", err)
+			}
+			if broken, err := repository.Open(driver, brokenPath, opts, logging.Discard()); err == nil {
+				_ = broken.Close()
+				t.Error("Ожидалась ошибка открытия поврежденной базы")
+			}
+		})
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "bot.db")
+	backupDir := filepath.Join(dir, "backups")
+
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	date := time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC)
+	if err := userRepo.Add(domain.User{ID: 1, FirstName: "Alice", ChatID: -1}); err != nil {
+		t.Fatalf("Ошибка добавления пользователя: %v", err)
+	}
+	if err := personRepo.Set(1, -1, domain.DefaultNominationID, date); err != nil {
+		t.Fatalf("Ошибка записи человека дня: %v", err)
+	}
+
+	// Старые копии сверх лимита удаляются, посторонние файлы не трогаются
+	if err := os.MkdirAll(backupDir, 0o750); err != nil {
+		t.Fatalf("Ошибка создания каталога: %v", err)
+	}
+	for _, name := range []string{"bot-20200101-000000.db", "bot-20200102-000000.db", "notes.db"} {
+		if err := os.WriteFile(filepath.Join(backupDir, name), []byte("old"), 0o600); err != nil {
+			t.Fatalf("Ошибка записи файла: %v", err)
+		}
+	}
+
+	manager := backup.NewManager(db, backupDir, 2, clock.NewFake(time.Date(2024, 3, 15, 12, 30, 0, 0, time.Local)), logging.Discard())
+	backupPath, err := manager.Run()
+	if err != nil {
+		t.Fatalf("Ошибка резервного копирования: %v", err)
+	}
+	if want := filepath.Join(backupDir, "bot-20240315-123000.db"); backupPath != want {
+		t.Errorf("Имя копии должно содержать время часов: %s, ожидалось %s", backupPath, want)
+	}
+	backups, err := manager.List()
+	if err != nil {
+		t.Fatalf("Ошибка получения списка копий: %v", err)
+	}
+	if len(backups) != 2 || backups[0] != filepath.Join(backupDir, "bot-20200102-000000.db") || backups[1] != backupPath {
+		t.Errorf("Ожидались две последние копии, получено %v", backups)
+	}
+	if _, err := os.Stat(filepath.Join(backupDir, "notes.db")); err != nil {
+		t.Errorf("Посторонний файл не должен удаляться: %v", err)
+	}
+
+	version, err := repository.ValidateBackup(repository.DefaultSQLiteDriver, backupPath)
+	if err != nil || version != repository.SchemaVersion {
+		t.Fatalf("Копия должна пройти проверку с версией %d, получено %d, %v", repository.SchemaVersion, version, err)
+	}
+
+	// Изменения после копии пропадают после восстановления
+	if err := personRepo.Set(1, -1, domain.DefaultNominationID, date.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("Ошибка записи человека дня: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Ошибка закрытия БД: %v", err)
+	}
+
+	_, firstSaved, err := repository.Restore(repository.DefaultSQLiteDriver, backupPath, dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка восстановления: %v", err)
+	}
+	if _, err := repository.ValidateBackup(repository.DefaultSQLiteDriver, firstSaved); err != nil || !strings.HasPrefix(firstSaved, dbPath+".before-restore-") {
+		t.Errorf("Текущая база должна сохраниться перед восстановлением: %s, %v", firstSaved, err)
+	}
+
+	// Повторное восстановление не перезаписывает прошлое сохранение, а оставшийся
+	// файл журнала уходит вместе с базой и не применяется к копии
+	if err := os.WriteFile(dbPath+"-wal", []byte("stale"), 0o600); err != nil {
+		t.Fatalf("Ошибка записи файла: %v", err)
+	}
+	_, secondSaved, err := repository.Restore(repository.DefaultSQLiteDriver, backupPath, dbPath)
+	if err != nil {
+		t.Fatalf("Ошибка повторного восстановления: %v", err)
+	}
+	if secondSaved == "" || secondSaved == firstSaved {
+		t.Errorf("Повторное восстановление должно сохранить базу под новым именем: %q, первое %q", secondSaved, firstSaved)
+	}
+	for _, path := range []string{firstSaved, secondSaved, secondSaved + "-wal"} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Сохраненный файл должен остаться: %v", err)
+		}
+	}
+	if _, err := os.Stat(dbPath + "-wal"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Рядом с восстановленной базой не должно быть старого журнала: %v", err)
+	}
+
+	db, err = repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия восстановленной базы: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+	personRepo = repository.NewPersonOfTheDayRepository(db)
+	if person, err := personRepo.GetByDate(-1, domain.DefaultNominationID, date); err != nil || person == nil {
+		t.Errorf("Ожидалась запись из копии, получено %+v, %v", person, err)
+	}
+	if person, err := personRepo.GetByDate(-1, domain.DefaultNominationID, date.AddDate(0, 0, 1)); err != nil || person != nil {
+		t.Errorf("Запись после копии не должна восстановиться, получено %+v, %v", person, err)
+	}
+
+	// Копии с неподдерживаемой схемой и посторонние файлы отклоняются
+	newerPath := filepath.Join(dir, "newer.db")
+	conn, err := sql.Open(repository.DefaultSQLiteDriver, newerPath)
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	for _, query := range []string{
+		`CREATE TABLE users (id INTEGER PRIMARY KEY)`,
+		`CREATE TABLE person_of_the_day (id INTEGER PRIMARY KEY)`,
+		fmt.Sprintf(`PRAGMA user_version = %d`, repository.SchemaVersion+1),
+	} {
+		if _, err := conn.Exec(query); err != nil {
+			t.Fatalf("Ошибка подготовки базы данных: %v", err)
+		}
+	}
+	_ = conn.Close()
+
+	emptyPath := filepath.Join(dir, "empty.db")
+	conn, err = sql.Open(repository.DefaultSQLiteDriver, emptyPath)
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	if _, err := conn.Exec(`CREATE TABLE other (id INTEGER)`); err != nil {
+		t.Fatalf("Ошибка подготовки базы данных: %v", err)
+	}
+	_ = conn.Close()
+
+	brokenPath := filepath.Join(dir, "broken.db")
+	if err := os.WriteFile(brokenPath, bytes.Repeat([]byte("broken"), 1024), 0o600); err != nil {
+		t.Fatalf("Ошибка записи файла: %v", err)
+	}
+
+	for _, path := range []string{newerPath, emptyPath, brokenPath, filepath.Join(dir, "missing.db")} {
+		if _, _, err := repository.Restore(repository.DefaultSQLiteDriver, path, dbPath); err == nil {
+			t.Errorf("%s: ожидалась ошибка проверки копии", filepath.Base(path))
+		}
+	}
+	if version, err := repository.ValidateBackup(repository.DefaultSQLiteDriver, dbPath); err != nil || version != repository.SchemaVersion {
+		t.Errorf("Отклоненная копия не должна заменять базу: %d, %v", version, err)
+	}
+}
+
+func TestHistoryExport(t *testing.T) {
+	records := []domain.PersonOfTheDayRecord{
+		{
+			PersonOfTheDay: domain.PersonOfTheDay{UserID: 1, ChatID: -10, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+			User:           domain.User{ID: 1, Username: "ivan", FirstName: "Иван", LastName: "Петров, мл."},
+			Nomination:     domain.DefaultNominationCommand,
+		},
+		{
+			PersonOfTheDay: domain.PersonOfTheDay{UserID: 2, ChatID: -10, NominationID: 7, Date: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)},
+			User:           domain.User{ID: 2},
+			Nomination:     "hero",
+		},
+	}
+
+	if format, ok := history.ParseFormat(" JSON "); !ok || format != history.FormatJSON {
+		t.Errorf("Ожидался формат json, получено %q", format)
+	}
+	if _, ok := history.ParseFormat("xml"); ok {
+		t.Error("Формат xml не поддерживается")
+	}
+	if name := history.FormatCSV.FileName(-10, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)); name != "pidor--10-20240501.csv" {
+		t.Errorf("Неверное имя файла: %s", name)
+	}
+
+	var buf bytes.Buffer
+	if err := history.Write(&buf, history.FormatCSV, records); err != nil {
+		t.Fatalf("Ошибка выгрузки CSV: %v", err)
+	}
+	content := strings.TrimPrefix(buf.String(), "\ufeff")
+	if content == buf.String() {
+		t.Error("CSV должен начинаться с BOM для табличных редакторов")
+	}
+	table, err := csv.NewReader(strings.NewReader(content)).ReadAll()
+	if err != nil {
+		t.Fatalf("Ошибка чтения CSV: %v", err)
+	}
+	if len(table) != 3 || table[0][0] != "chat_id" || table[0][8] != "display_name" {
+		t.Fatalf("Неверная таблица: %v", table)
+	}
+	if table[1][1] != "2024-01-02" || table[1][3] != "pidor" || table[1][8] != "Иван Петров, мл. (@ivan)" {
+		t.Errorf("Неверная строка CSV: %v", table[1])
+	}
+	if table[2][2] != "7" || table[2][3] != "hero" || table[2][4] != "2" {
+		t.Errorf("Неверная строка CSV: %v", table[2])
+	}
+
+	buf.Reset()
+	if err := history.Write(&buf, history.FormatJSON, records); err != nil {
+		t.Fatalf("Ошибка выгрузки JSON: %v", err)
+	}
+	var rows []history.Row
+	if err := json.Unmarshal(buf.Bytes(), &rows); err != nil {
+		t.Fatalf("Ошибка чтения JSON: %v", err)
+	}
+	if len(rows) != 2 || rows[0].DisplayName != "Иван Петров, мл. (@ivan)" || rows[1].Date != "2024-01-03" {
+		t.Errorf("Неверный JSON: %+v", rows)
+	}
+
+	buf.Reset()
+	if err := history.Write(&buf, history.FormatJSON, nil); err != nil || strings.TrimSpace(buf.String()) != "[]" {
+		t.Errorf("Пустая история должна выгружаться как [], получено %q (%v)", buf.String(), err)
+	}
+
+	// Имена участников не должны становиться формулами в табличном редакторе,
+	// а импорт выгрузки должен вернуть исходные значения
+	formula := `=HYPERLINK("http://example.com","click")`
+	records = []domain.PersonOfTheDayRecord{{
+		PersonOfTheDay: domain.PersonOfTheDay{UserID: 3, ChatID: -10, Date: time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)},
+		User:           domain.User{ID: 3, Username: "+mallory", FirstName: formula, LastName: "'quoted"},
+		Nomination:     domain.DefaultNominationCommand,
+	}}
+	buf.Reset()
+	if err := history.Write(&buf, history.FormatCSV, records); err != nil {
+		t.Fatalf("Ошибка выгрузки CSV: %v", err)
+	}
+	table, err = csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatalf("Ошибка чтения CSV: %v", err)
+	}
+	for i, expected := range map[int]string{5: "'+mallory", 6: "'" + formula, 7: "''quoted", 8: "'" + formula + " 'quoted (@+mallory)"} {
+		if table[1][i] != expected {
+			t.Errorf("Колонка %s: ожидалось %q, получено %q", table[0][i], expected, table[1][i])
+		}
+	}
+	imported, err := history.Parse(&buf, history.FormatCSV)
+	if err != nil {
+		t.Fatalf("Ошибка импорта выгрузки: %v", err)
+	}
+	if len(imported) != 1 || imported[0].Username != "+mallory" || imported[0].UserID != 3 || imported[0].Err != nil {
+		t.Errorf("Импорт должен снять экранирование: %+v", imported)
+	}
+}
+
+func TestHistoryImport(t *testing.T) {
+	csvData := "\ufeffDate;ignored\n"
+	if _, err := history.Parse(strings.NewReader(csvData), history.FormatCSV); err == nil {
+		t.Error("Ожидалась ошибка для CSV без колонок участника")
+	}
+
+	csvData = "\ufeffDay,Username,Nomination,Extra\n" +
+		"2023-01-01,@Ivan,,x\n" +
+		"02.01.2023,anna,/coffee\n" +
+		"2023-01-03,ghost,\n" +
+		"2023-01-04,anna,tea\n" +
+		"31.02.2023,anna,\n" +
+		"2023-01-05,ivan,pidor\n"
+	rows, err := history.Parse(strings.NewReader(csvData), history.FormatCSV)
+	if err != nil {
+		t.Fatalf("Ошибка чтения CSV: %v", err)
+	}
+	if len(rows) != 6 || rows[0].Line != 2 || rows[0].Username != "Ivan" || rows[1].Nomination != "coffee" {
+		t.Fatalf("Неверный разбор CSV: %+v", rows)
+	}
+	if rows[1].Date.Format("2006-01-02") != "2023-01-02" || rows[4].Err == nil {
+		t.Errorf("Неверный разбор дат: %+v", rows)
+	}
+
+	jsonData := `[{"date": "2023-02-01", "user_id": "1"}, {"date": "2023-02-02T10:00:00Z", "user_id": 2}, {"date": "2023-02-03"}]`
+	jsonRows, err := history.Parse(strings.NewReader(jsonData), history.FormatJSON)
+	if err != nil {
+		t.Fatalf("Ошибка чтения JSON: %v", err)
+	}
+	if len(jsonRows) != 3 || jsonRows[0].UserID != 1 || jsonRows[1].UserID != 2 || jsonRows[2].Err == nil {
+		t.Errorf("Неверный разбор JSON: %+v", jsonRows)
+	}
+
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "import.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	nominationRepo := repository.NewNominationRepository(db)
+	const chatID int64 = -100
+
+	for _, user := range []domain.User{
+		{ID: 1, Username: "ivan", FirstName: "Иван", ChatID: chatID},
+		{ID: 2, Username: "Anna", FirstName: "Анна", ChatID: chatID},
+	} {
+		if err := userRepo.Add(user); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+	}
+	coffee := domain.Nomination{ChatID: chatID, Command: "coffee", Title: "Кофевар", Strategy: domain.StrategyRandom}
+	if err := nominationRepo.Create(&coffee); err != nil {
+		t.Fatalf("Ошибка создания номинации: %v", err)
+	}
+	// Дата уже занята: импорт не должен ее перезаписать
+	existing := time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC)
+	if err := personRepo.Set(2, chatID, domain.DefaultNominationID, existing); err != nil {
+		t.Fatalf("Ошибка записи человека дня: %v", err)
+	}
+
+	importer := history.NewImporter(userRepo, personRepo, nominationRepo)
+	result, err := importer.Import(chatID, append(rows, jsonRows...))
+	if err != nil {
+		t.Fatalf("Ошибка импорта: %v", err)
+	}
+	if result.Total != 9 || result.Imported != 4 || result.Skipped != 1 || len(result.Unmatched) != 4 {
+		t.Fatalf("Неверный отчет об импорте: %+v", result)
+	}
+	reasons := []domain.ImportReason{
+		domain.ReasonUserNotFound, domain.ReasonNominationNotFound, domain.ReasonInvalidRow, domain.ReasonInvalidRow,
+	}
+	for i, reason := range reasons {
+		if result.Unmatched[i].Reason != reason {
+			t.Errorf("Строка %d: ожидалась причина %s, получено %+v", i, reason, result.Unmatched[i])
+		}
+	}
+	if result.Unmatched[0].Value != "@ghost" || result.Unmatched[0].Line != 4 {
+		t.Errorf("Неверная строка без участника: %+v", result.Unmatched[0])
+	}
+
+	if person, err := personRepo.GetByDate(chatID, domain.DefaultNominationID, existing); err != nil || person == nil || person.ID != 2 {
+		t.Errorf("Занятая дата не должна перезаписываться, получено %+v (%v)", person, err)
+	}
+	if person, err := personRepo.GetByDate(chatID, coffee.ID, time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)); err != nil || person == nil || person.ID != 2 {
+		t.Errorf("Ожидался импорт в номинацию, получено %+v (%v)", person, err)
+	}
+
+	// Повторный импорт того же файла ничего не добавляет
+	again, err := importer.Import(chatID, rows)
+	if err != nil || again.Imported != 0 || again.Skipped != 3 {
+		t.Errorf("Повторный импорт не должен добавлять записи: %+v (%v)", again, err)
+	}
+
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	report := service.BuildImportReport(*result)
+	for _, expected := range []string{"добавлено 4 из 9", "строка 4: @ghost — участник не найден в чате", "номинация не найдена"} {
+		if !strings.Contains(report, expected) {
+			t.Errorf("Отчет должен содержать %q:\n%s", expected, report)
+		}
+	}
+}
+
+func TestCommandRouter(t *testing.T) {
+	dbPath := "test_router.db"
+	defer func() {
+		if err := os.Remove(dbPath); err != nil {
+			t.Logf("Не удалось удалить тестовую БД: %v", err)
+		}
+	}()
+
+	db, err := repository.NewDatabase(dbPath, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка создания базы данных: %v", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия БД: %v", err)
+		}
+	}()
+
+	aliasRepo := repository.NewCommandAliasRepository(db)
+	chatID := int64(-123456789)
+	if err := aliasRepo.Set(chatID, "hero", "pidor"); err != nil {
+		t.Fatalf("Ошибка добавления алиаса: %v", err)
+	}
+
+	api, err := telebot.NewBot(telebot.Settings{Offline: true})
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+
+	var called []string
+	router := handlers.NewCommandRouter("DayBot", aliasRepo, logging.Discard())
+	router.Add("pidor", func(c telebot.Context) error {
+		called = append(called, "pidor")
+		return nil
+	})
+	router.SetFallback(func(c telebot.Context, command string) (bool, error) {
+		if command == "coffee" {
+			called = append(called, "coffee")
+			return true, nil
+		}
+		return false, nil
+	})
+	router.SetUnknown(func(c telebot.Context) error {
+		called = append(called, "unknown")
+		return nil
+	})
+
+	tests := []struct {
+		text     string
+		chatType telebot.ChatType
+		handled  bool
+		expected string
+	}{
+		{"/pidor", telebot.ChatGroup, true, "pidor"},
+		{"/PIDOR@daybot", telebot.ChatGroup, true, "pidor"},
+		{"/hero@DayBot сегодня", telebot.ChatGroup, true, "pidor"},
+		{"/coffee", telebot.ChatGroup, true, "coffee"},
+		// Неизвестная команда в группе без @DayBot может быть адресована другому боту
+		{"/unknown", telebot.ChatGroup, false, ""},
+		{"/unknown", telebot.ChatSuperGroup, false, ""},
+		{"/unknown@DayBot", telebot.ChatGroup, true, "unknown"},
+		{"/unknown@daybot", telebot.ChatSuperGroup, true, "unknown"},
+		{"/pidor@OtherBot", telebot.ChatGroup, false, ""},
+		{"просто текст", telebot.ChatGroup, false, ""},
+	}
+
+	for _, tt := range tests {
+		called = nil
+		c := api.NewContext(telebot.Update{Message: &telebot.Message{
+			Text: tt.text,
+			Chat: &telebot.Chat{ID: chatID, Type: tt.chatType},
+		}})
+
+		handled, err := router.Route(c)
+		if err != nil {
+			t.Fatalf("%s: ошибка маршрутизации: %v", tt.text, err)
+		}
+		if handled != tt.handled {
+			t.Errorf("%s: ожидалось handled=%v, получено %v", tt.text, tt.handled, handled)
+		}
+		if tt.expected != "" && (len(called) != 1 || called[0] != tt.expected) {
+			t.Errorf("%s: ожидался вызов %s, получено %v", tt.text, tt.expected, called)
+		}
+		if tt.expected == "" && len(called) != 0 {
+			t.Errorf("%s (%s): обработчики не должны вызываться, получено %v", tt.text, tt.chatType, called)
+		}
+	}
+
+	aliases, err := aliasRepo.GetByChatID(chatID)
+	if err != nil {
+		t.Fatalf("Ошибка получения алиасов: %v", err)
+	}
+	if len(aliases) != 1 || aliases[0].Command != "pidor" {
+		t.Errorf("Ожидался алиас hero → pidor, получено %+v", aliases)
+	}
+
+	if err := aliasRepo.Delete(chatID, "hero"); err != nil {
+		t.Fatalf("Ошибка удаления алиаса: %v", err)
+	}
+	if alias, err := aliasRepo.Get(chatID, "hero"); err != nil || alias != nil {
+		t.Errorf("Алиас должен быть удален, получено %+v, %v", alias, err)
+	}
+}
+
+func TestCommandRegistry(t *testing.T) {
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+
+	commandHandler := handlers.NewCommandHandler(nil, nil, nil, nil, nil, nil, nil, nil, service, nil, nil, logging.Discard())
+
+	for _, locale := range templates.SupportedLocales() {
+		messages := service.WithLocale(locale)
+		help := messages.HelpText(commandHandler.HelpCommands())
+
+		for _, command := range commandHandler.Commands() {
+			description := messages.CommandDescription(command.Name)
+			if description == "" {
+				t.Errorf("%s: нет описания команды /%s", locale, command.Name)
+				continue
+			}
+
+			line := "/" + command.Name + " - " + description
+			if command.Hidden == strings.Contains(help, line) {
+				t.Errorf("%s: справка не согласована с реестром для /%s:\n%s", locale, command.Name, help)
+			}
+		}
+	}
+}
+
+// TestErrorContexts проверяет, что сообщения об ошибках не смешивают языки
+func TestErrorContexts(t *testing.T) {
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+
+	for _, locale := range templates.SupportedLocales() {
+		messages := service.WithLocale(locale)
+		seen := make(map[string]templates.ErrorContext)
+		for _, context := range templates.ErrorContexts() {
+			message := messages.ErrorOccurred(context)
+			if strings.HasSuffix(message, ": "+string(context)) {
+				t.Errorf("%s: нет текста контекста %s: %q", locale, context, message)
+			}
+			if locale == templates.LocaleEn && strings.ContainsFunc(message, func(r rune) bool { return unicode.Is(unicode.Cyrillic, r) }) {
+				t.Errorf("%s: сообщение об ошибке на другом языке: %q", locale, message)
+			}
+			if other, ok := seen[message]; ok {
+				t.Errorf("%s: одинаковый текст у контекстов %s и %s", locale, other, context)
+			}
+			seen[message] = context
+		}
+	}
+
+	if got := service.WithLocale(templates.LocaleUk).ErrorOccurred(templates.ErrorDraw); got != "Сталася помилка: під час вибору учасника" {
+		t.Errorf("Неверное сообщение об ошибке: %q", got)
+	}
+}
+
+func TestConfig(t *testing.T) {
+	for _, name := range []string{"BOT_TOKEN", "DEBUG", "TIME_ZONE", "LANGUAGE", "POLLER_TIMEOUT", "LOG_LEVEL", "LOG_FORMAT",
+		"DB_DRIVER", "DB_PATH", "TELEGRAM_MODE", "TELEGRAM_API_URL",
+		"WEBHOOK_LISTEN", "WEBHOOK_URL", "WEBHOOK_SECRET", "WEBHOOK_TLS_CERT", "WEBHOOK_TLS_KEY",
+		"DB_JOURNAL_MODE", "DB_BUSY_TIMEOUT", "DB_FOREIGN_KEYS", "DB_INTEGRITY_CHECK", "DB_MAX_OPEN_CONNS",
+		"DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "BACKUP_ENABLED", "BACKUP_DIR", "BACKUP_INTERVAL", "BACKUP_RETENTION",
+		"METRICS_ENABLED", "METRICS_LISTEN", "METRICS_PATH", "HTTP_ENABLED", "HTTP_LISTEN", "HTTP_UPDATES_THRESHOLD",
+		"API_ENABLED", "WEB_ENABLED", "WEB_PUBLIC_URL", "WEB_SECRET", "WEB_LINK_TTL"} {
+		t.Setenv(name, "")
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(content string) {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("Ошибка записи конфигурации: %v", err)
+		}
+	}
+
+	writeConfig(`bot_token: file-token
+time_zone: Europe/Moscow
+language: en
+telegram:
+  poller_timeout: 30s
+storage:
+  path: /data/bot.db
+`)
+	t.Setenv("LANGUAGE", "uk")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if cfg.BotToken != "file-token" || cfg.Telegram.PollerTimeout != 30*time.Second || cfg.Storage.Path != "/data/bot.db" {
+		t.Errorf("Значения из файла не применены: %+v", cfg)
+	}
+	if cfg.Locale() != templates.LocaleUk {
+		t.Errorf("Переменная окружения должна переопределять файл, получено %s", cfg.Locale())
+	}
+	if cfg.Location().String() != "Europe/Moscow" {
+		t.Errorf("Ожидался часовой пояс Europe/Moscow, получено %s", cfg.Location())
+	}
+	if cfg.Storage.Driver != repository.DefaultSQLiteDriver || cfg.Log.Level != "info" {
+		t.Errorf("Значения по умолчанию не сохранены: %+v", cfg)
+	}
+	if cfg.Storage.Options() != repository.DefaultOptions() {
+		t.Errorf("Ожидались настройки хранилища по умолчанию, получено %+v", cfg.Storage.Options())
+	}
+
+	output, err := cfg.Redacted()
+	if err != nil {
+		t.Fatalf("Ошибка вывода конфигурации: %v", err)
+	}
+	if strings.Contains(output, "file-token") || !strings.Contains(output, "<redacted>") {
+		t.Errorf("Токен не скрыт:\n%s", output)
+	}
+	if cfg.BotToken != "file-token" {
+		t.Error("Redacted не должен изменять исходную конфигурацию")
+	}
+
+	// Неизвестные поля запрещены
+	writeConfig("bot_token: x\nunknown_field: 1\n")
+	if _, err := config.Load(path); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного поля")
+	}
+
+	// Все ошибки проверки возвращаются вместе
+	writeConfig(`time_zone: Mars/Olympus
+language: de
+telegram:
+  poller_timeout: 0s
+log:
+  level: verbose
+`)
+	t.Setenv("LANGUAGE", "")
+	t.Setenv("DB_JOURNAL_MODE", "fast")
+	t.Setenv("DB_MAX_OPEN_CONNS", "2")
+	t.Setenv("DB_MAX_IDLE_CONNS", "4")
+	t.Setenv("BACKUP_RETENTION", "-1")
+	_, err = config.Load(path)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка проверки конфигурации")
+	}
+	for _, expected := range []string{"bot_token", "time_zone", "language", "poller_timeout", "log.level",
+		"storage.journal_mode", "storage.max_idle_conns", "backup.retention"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Ошибка должна упоминать %s: %v", expected, err)
+		}
+	}
+
+	// Режим вебхука требует публичный https адрес и корректный секрет
+	writeConfig(`bot_token: x
+telegram:
+  mode: webhook
+  webhook:
+    public_url: http://bot.example.com/hook
+    secret_token: "bad secret!"
+    tls_cert: cert.pem
+metrics:
+  enabled: true
+  listen: ":8443"
+  path: metrics
+http:
+  listen: ":8443"
+`)
+	_, err = config.Load(path)
+	if err == nil {
+		t.Fatal("Ожидалась ошибка проверки настроек вебхука")
+	}
+	for _, expected := range []string{"https", "secret_token", "tls_key", "metrics.listen", "metrics.path", "http.listen"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Ошибка должна упоминать %s: %v", expected, err)
+		}
+	}
+
+	t.Setenv("DB_JOURNAL_MODE", "")
+	t.Setenv("DB_MAX_OPEN_CONNS", "")
+	t.Setenv("DB_MAX_IDLE_CONNS", "")
+	t.Setenv("BACKUP_RETENTION", "")
+
+	// Порог готовности должен превышать длительность долгого опроса
+	t.Setenv("HTTP_UPDATES_THRESHOLD", "5s")
+	writeConfig("bot_token: x\n")
+	if _, err := config.Load(path); err == nil || !strings.Contains(err.Error(), "http.updates_threshold") {
+		t.Errorf("Ожидалась ошибка http.updates_threshold, получено %v", err)
+	}
+	t.Setenv("HTTP_UPDATES_THRESHOLD", "")
+
+	// HTTP API работает на HTTP сервере бота
+	t.Setenv("API_ENABLED", "true")
+	writeConfig("bot_token: x\nhttp:\n  enabled: false\n")
+	if _, err := config.Load(path); err == nil || !strings.Contains(err.Error(), "api.enabled") {
+		t.Errorf("Ожидалась ошибка api.enabled, получено %v", err)
+	}
+	t.Setenv("API_ENABLED", "")
+
+	t.Setenv("WEB_ENABLED", "true")
+	t.Setenv("WEB_PUBLIC_URL", "bot.example.com")
+	t.Setenv("WEB_SECRET", "short")
+	writeConfig("bot_token: x\n")
+	_, err = config.Load(path)
+	for _, expected := range []string{"web.public_url", "web.secret"} {
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Ожидалась ошибка %s, получено %v", expected, err)
+		}
+	}
+	t.Setenv("WEB_PUBLIC_URL", "https://bot.example.com")
+	t.Setenv("WEB_SECRET", "0123456789abcdef")
+	cfg, err = config.Load(path)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации веб-страницы: %v", err)
+	}
+	if output, _ := cfg.Redacted(); strings.Contains(output, "0123456789abcdef") {
+		t.Errorf("Ключ подписи ссылок не скрыт:\n%s", output)
+	}
+	t.Setenv("WEB_ENABLED", "")
+	t.Setenv("WEB_SECRET", "")
+
+	t.Setenv("WEBHOOK_URL", "https://bot.example.com/hook")
+	t.Setenv("WEBHOOK_SECRET", "secret-token")
+	writeConfig("bot_token: x\ntelegram:\n  mode: webhook\n")
+	cfg, err = config.Load(path)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации вебхука: %v", err)
+	}
+	output, err = cfg.Redacted()
+	if err != nil || strings.Contains(output, "secret-token") {
+		t.Errorf("Секрет вебхука не скрыт:\n%s", output)
+	}
+
+	t.Setenv("BOT_TOKEN", "env-token")
+	t.Setenv("DEBUG", "maybe")
+	if _, err := config.Load(""); err == nil || !strings.Contains(err.Error(), "DEBUG") {
+		t.Errorf("Ожидалась ошибка разбора DEBUG, получено %v", err)
+	}
+}
+
+func TestUpdateLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info", logging.FormatJSON)
+	if err != nil {
+		t.Fatalf("Ошибка создания логгера: %v", err)
+	}
+	if _, err := logging.New(&buf, "verbose", logging.FormatJSON); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного уровня")
+	}
+	if _, err := logging.New(&buf, "info", "xml"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного формата")
+	}
+
+	api, err := telebot.NewBot(telebot.Settings{Offline: true})
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+
+	handler := handlers.LoggerMiddleware(logger)(func(c telebot.Context) error {
+		handlers.UpdateLogger(c, nil).Debug("не должно попасть в лог")
+		handlers.UpdateLogger(c, nil).Info("обработка")
+		return nil
+	})
+
+	for _, updateID := range []int{1, 2} {
+		update := telebot.Update{
+			ID: updateID,
+			Message: &telebot.Message{
+				Text:   "/pidor",
+				Chat:   &telebot.Chat{ID: -100, Type: telebot.ChatSuperGroup},
+				Sender: &telebot.User{ID: 42},
+			},
+		}
+		if err := handler(api.NewContext(update)); err != nil {
+			t.Fatalf("Ошибка обработки обновления: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Ожидалось 2 записи уровня info, получено %d:\n%s", len(lines), buf.String())
+	}
+
+	correlationIDs := make(map[string]bool)
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Запись не в формате JSON: %v", err)
+		}
+		if record["update_id"] != float64(i+1) || record["chat_id"] != float64(-100) || record["user_id"] != float64(42) {
+			t.Errorf("Неверные атрибуты обновления: %v", record)
+		}
+		id, _ := record["correlation_id"].(string)
+		if id == "" {
+			t.Errorf("Нет идентификатора корреляции: %v", record)
+		}
+		correlationIDs[id] = true
+	}
+	if len(correlationIDs) != 2 {
+		t.Error("Идентификаторы корреляции разных обновлений должны различаться")
+	}
+}
+
+func TestWebhook(t *testing.T) {
+	fake := telegramtest.NewServer()
+	defer fake.Close()
+
+	cfg := config.Default()
+	cfg.Telegram.Mode = config.ModeWebhook
+	cfg.Telegram.Webhook = config.WebhookConfig{
+		Listen:      "127.0.0.1:0",
+		PublicURL:   "https://bot.example.com/telegram/hook",
+		SecretToken: "s3cret_token",
+	}
+
+	poller, err := bot.NewPoller(cfg.Telegram, logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка создания вебхука: %v", err)
+	}
+	webhook := poller.(*bot.Webhook)
+
+	api, err := telebot.NewBot(telebot.Settings{URL: fake.URL, Token: "test-token", Poller: poller})
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "webhook.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка создания базы данных: %v", err)
+	}
+	defer db.Close()
+
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+
+	collector := metrics.New(nil)
+	metricsServer := httpserver.New("metrics", "127.0.0.1:0", logging.Discard())
+	metricsServer.Handle("/metrics", collector.Handler())
+	if err := metricsServer.Listen(); err != nil {
+		t.Fatalf("Ошибка запуска сервера метрик: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go metricsServer.Serve(ctx)
+
+	botInstance := bot.NewBot(api,
+		collector.WrapUserRepository(repository.NewUserRepository(db)),
+		collector.WrapPersonOfTheDayRepository(repository.NewPersonOfTheDayRepository(db)),
+		collector.WrapChatSettingsRepository(repository.NewChatSettingsRepository(db)),
+		collector.WrapNominationRepository(repository.NewNominationRepository(db)),
+		collector.WrapCommandAliasRepository(repository.NewCommandAliasRepository(db)),
+		nil,
+		nil,
+		service,
+		nil,
+		collector,
+		logging.Discard(),
+	)
+
+	started := make(chan error, 1)
+	go func() { started <- botInstance.Start() }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(fake.Calls("setWebhook")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("Вебхук не зарегистрирован")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	registered := fake.Calls("setWebhook")[0]
+	if registered.Param("url") != cfg.Telegram.Webhook.PublicURL || registered.Param("secret_token") != "s3cret_token" {
+		t.Errorf("Неверные параметры setWebhook: %v", registered.Params)
+	}
+
+	endpoint := fmt.Sprintf("http://%s/telegram/hook", webhook.Addr())
+	update := `{"update_id": 1, "message": {"message_id": 10, "date": 0, "text": "/help",
+		"chat": {"id": -100, "type": "supergroup"}, "from": {"id": 42, "first_name": "Иван"}}}`
+
+	post := func(url, secret string) int {
+		req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(update))
+		if err != nil {
+			t.Fatalf("Ошибка создания запроса: %v", err)
+		}
+		if secret != "" {
+			req.Header.Set("X-Telegram-Bot-Api-Secret-Token", secret)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Ошибка запроса к вебхуку: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if code := post(endpoint, "wrong"); code != http.StatusUnauthorized {
+		t.Errorf("Ожидался статус 401 для неверного секрета, получено %d", code)
+	}
+	if code := post(endpoint, ""); code != http.StatusUnauthorized {
+		t.Errorf("Ожидался статус 401 без секрета, получено %d", code)
+	}
+	if code := post(fmt.Sprintf("http://%s/other", webhook.Addr()), "s3cret_token"); code != http.StatusNotFound {
+		t.Errorf("Ожидался статус 404 для другого пути, получено %d", code)
+	}
+	if code := post(endpoint, "s3cret_token"); code != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получено %d", code)
+	}
+
+	answer, err := fake.Next(5 * time.Second)
+	if err != nil {
+		t.Fatalf("Бот не ответил на обновление из вебхука: %v", err)
+	}
+	if !strings.Contains(answer.Text, "/pidor") || answer.ChatID != -100 || answer.ReplyTo != 10 {
+		t.Errorf("Ожидалась справка в ответ на /help, получено %+v", answer)
+	}
+
+	botInstance.Stop()
+	if err := <-started; err != nil {
+		t.Fatalf("Ошибка запуска бота: %v", err)
+	}
+	if len(fake.Calls("deleteWebhook")) != 1 {
+		t.Errorf("Ожидалось удаление вебхука при остановке, вызовов: %d", len(fake.Calls("deleteWebhook")))
+	}
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", metricsServer.Addr()))
+	if err != nil {
+		t.Fatalf("Ошибка запроса метрик: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Ошибка чтения метрик: %v", err)
+	}
+	for _, expected := range []string{
+		`dayofthebot_updates_total{type="message"} 1`,
+		`dayofthebot_commands_total{command="help"} 1`,
+		`dayofthebot_active_chats 1`,
+		`dayofthebot_repository_query_duration_seconds_count{method="Add",repository="users"}`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("Метрики должны содержать %q", expected)
+		}
+	}
+}
+
+func TestHealth(t *testing.T) {
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "health.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+
+	healthClock := clock.NewFake(time.Now())
+	checker := health.NewChecker(db, time.Minute, healthClock)
+	server := httpserver.New("http", "127.0.0.1:0", logging.Discard())
+	checker.Register(server)
+	if err := server.Listen(); err != nil {
+		t.Fatalf("Ошибка запуска HTTP сервера: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go server.Serve(ctx)
+
+	base := "http://" + server.Addr().String()
+	probe := func(path string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return health.Probe(ctx, base+path)
+	}
+
+	if err := probe(health.LivePath); err != nil {
+		t.Errorf("Проверка живости должна проходить: %v", err)
+	}
+	if err := probe(health.ReadyPath); err == nil || !strings.Contains(err.Error(), "getUpdates") {
+		t.Errorf("Бот без успешного getUpdates не должен быть готов, получено %v", err)
+	}
+
+	// Транспорт отмечает только успешные getUpdates
+	telegram := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/fail/getUpdates") {
+			w.WriteHeader(http.StatusConflict)
+		}
+	}))
+	defer telegram.Close()
+	client := &http.Client{Transport: checker.Transport(nil)}
+	for _, path := range []string{"/bottoken/getMe", "/fail/getUpdates"} {
+		resp, err := client.Post(telegram.URL+path, "application/json", nil)
+		if err != nil {
+			t.Fatalf("Ошибка запроса: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if err := probe(health.ReadyPath); err == nil {
+		t.Error("Неуспешный getUpdates не должен делать бота готовым")
+	}
+
+	resp, err := client.Post(telegram.URL+"/bottoken/getUpdates", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Ошибка запроса: %v", err)
+	}
+	resp.Body.Close()
+	if err := probe(health.ReadyPath); err != nil {
+		t.Errorf("Бот должен быть готов после getUpdates: %v", err)
+	}
+
+	healthClock.Advance(time.Minute + time.Second)
+	if err := probe(health.ReadyPath); err == nil || !strings.Contains(err.Error(), "ago") {
+		t.Errorf("Давний getUpdates должен делать бота неготовым, получено %v", err)
+	}
+
+	// Без проверки getUpdates готовность зависит только от базы данных
+	results, ok := health.NewChecker(db, 0, nil).Check(context.Background())
+	if !ok || results["database"] != "ok" || results["updates"] != "" {
+		t.Errorf("Неверный результат проверки: %v", results)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Ошибка закрытия БД: %v", err)
+	}
+	if results, ok := health.NewChecker(db, 0, nil).Check(context.Background()); ok || results["database"] == "ok" {
+		t.Errorf("Закрытая база данных не должна проходить проверку: %v", results)
+	}
+
+	for listen, expected := range map[string]string{
+		":8080":          "http://127.0.0.1:8080/readyz",
+		"0.0.0.0:9000":   "http://127.0.0.1:9000/readyz",
+		"10.0.0.5:8080":  "http://10.0.0.5:8080/readyz",
+		"[::]:8080":      "http://127.0.0.1:8080/readyz",
+		"localhost:8081": "http://localhost:8081/readyz",
+	} {
+		if url := (config.HTTPConfig{Listen: listen}).HealthURL(health.ReadyPath); url != expected {
+			t.Errorf("Адрес проверки для %s: ожидалось %s, получено %s", listen, expected, url)
+		}
+	}
+}
+
+func TestAPI(t *testing.T) {
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "api.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	defer db.Close()
+
+	const chatID, otherChatID int64 = -100, -200
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	nominationRepo := repository.NewNominationRepository(db)
+	tokenRepo := repository.NewAPITokenRepository(db)
+
+	ivan := domain.User{ID: 1, Username: "ivan", FirstName: "Иван", ChatID: chatID}
+	anna := domain.User{ID: 2, FirstName: "Анна", ChatID: chatID}
+	for _, user := range []domain.User{ivan, anna} {
+		if err := userRepo.Add(user); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+	}
+	hero := &domain.Nomination{ChatID: chatID, Command: "hero", Title: "Герой дня"}
+	if err := nominationRepo.Create(hero); err != nil {
+		t.Fatalf("Ошибка создания номинации: %v", err)
+	}
+
+	// Иван выигрывает 2024-01-01 и 2024-01-02, Анна — 2024-01-03 и сегодня
+	for _, draw := range []struct {
+		user int64
+		date time.Time
+	}{
+		{ivan.ID, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{ivan.ID, time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)},
+		{anna.ID, time.Date(2024, 1, 3, 0, 0, 0, 0, time.Local)},
+		{anna.ID, time.Now()},
+	} {
+		if err := personRepo.Set(draw.user, chatID, domain.DefaultNominationID, draw.date); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+	}
+	if err := personRepo.Set(ivan.ID, chatID, hero.ID, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("Ошибка сохранения выбора: %v", err)
+	}
+
+	token, hash, err := api.GenerateToken()
+	if err != nil {
+		t.Fatalf("Ошибка создания токена: %v", err)
+	}
+	if !strings.HasPrefix(token, "dotb_") || hash != api.HashToken(token) || strings.Contains(hash, token) {
+		t.Errorf("Неверный токен %q или хеш %q", token, hash)
+	}
+	if err := tokenRepo.Set(chatID, hash, ivan.ID); err != nil {
+		t.Fatalf("Ошибка сохранения токена: %v", err)
+	}
+	_, otherHash, _ := api.GenerateToken()
+	if err := tokenRepo.Set(otherChatID, otherHash, ivan.ID); err != nil {
+		t.Fatalf("Ошибка сохранения токена: %v", err)
+	}
+
+	server := httptest.NewServer(api.NewHandler(personRepo, nominationRepo, tokenRepo, nil, logging.Discard()))
+	defer server.Close()
+
+	get := func(path, token string, target any) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatalf("Ошибка создания запроса: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("Ошибка запроса %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		if target != nil && resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+				t.Fatalf("Ошибка чтения ответа %s: %v", path, err)
+			}
+		}
+		return resp.StatusCode
+	}
+
+	chatPath := api.ChatPath(chatID)
+	for _, tc := range []struct {
+		path, token string
+		code        int
+	}{
+		{chatPath + "/stats", "", http.StatusUnauthorized},
+		{chatPath + "/stats", "dotb_unknown", http.StatusUnauthorized},
+		{api.ChatPath(otherChatID) + "/stats", token, http.StatusForbidden},
+		{chatPath + "/stats?nomination=missing", token, http.StatusNotFound},
+		{chatPath + "/history?from=2024-13-01", token, http.StatusBadRequest},
+		{chatPath + "/history?from=2024-01-03&to=2024-01-01", token, http.StatusBadRequest},
+		{chatPath + "/history?limit=0", token, http.StatusBadRequest},
+		{chatPath + "/history?limit=501", token, http.StatusBadRequest},
+		{chatPath + "/history?offset=-1", token, http.StatusBadRequest},
+		{"/api/v1/chats/abc/stats", token, http.StatusNotFound},
+	} {
+		if code := get(tc.path, tc.token, nil); code != tc.code {
+			t.Errorf("%s: ожидался код %d, получено %d", tc.path, tc.code, code)
+		}
+	}
+
+	var stats api.Page[api.StatsEntry]
+	if code := get(chatPath+"/stats", token, &stats); code != http.StatusOK {
+		t.Fatalf("Ошибка статистики: %d", code)
+	}
+	if stats.Total != 2 || stats.Items[0].Wins != 2 || stats.Items[0].Rank != 1 || stats.Items[1].Wins != 2 {
+		t.Errorf("Неверная статистика: %+v", stats)
+	}
+
+	// За период победы считаются только по выборам в его границах
+	if code := get(chatPath+"/stats?from=2024-01-02&to=2024-01-03", token, &stats); code != http.StatusOK {
+		t.Fatalf("Ошибка статистики за период: %d", code)
+	}
+	if stats.From != "2024-01-02" || stats.To != "2024-01-03" || len(stats.Items) != 2 ||
+		stats.Items[0].Wins != 1 || stats.Items[1].Wins != 1 {
+		t.Errorf("Неверная статистика за период: %+v", stats)
+	}
+	if code := get(chatPath+"/stats?to=2024-01-02", token, &stats); code != http.StatusOK {
+		t.Fatalf("Ошибка статистики за период: %d", code)
+	}
+	if stats.Items[0].User.Username != "ivan" || stats.Items[0].Wins != 2 || stats.Items[1].Wins != 0 {
+		t.Errorf("Участник без побед за период должен остаться в таблице: %+v", stats)
+	}
+	if code := get(chatPath+"/stats?nomination=hero", token, &stats); code != http.StatusOK {
+		t.Fatalf("Ошибка статистики номинации: %d", code)
+	}
+	if stats.Items[0].User.ID != ivan.ID || stats.Items[0].Wins != 1 {
+		t.Errorf("Неверная статистика номинации: %+v", stats)
+	}
+
+	var historyPage api.Page[api.HistoryEntry]
+	if code := get(chatPath+"/history", token, &historyPage); code != http.StatusOK {
+		t.Fatalf("Ошибка истории: %d", code)
+	}
+	if historyPage.Total != 5 || historyPage.Limit != api.DefaultLimit || historyPage.Items[0].User.ID != anna.ID {
+		t.Errorf("История должна включать все номинации от новых к старым: %+v", historyPage)
+	}
+	if code := get(chatPath+"/history?nomination=pidor&limit=2&offset=1", token, &historyPage); code != http.StatusOK {
+		t.Fatalf("Ошибка истории: %d", code)
+	}
+	if historyPage.Total != 4 || len(historyPage.Items) != 2 || historyPage.Items[0].Date != "2024-01-03" ||
+		historyPage.Items[1].Date != "2024-01-02" || historyPage.Items[0].Nomination != "pidor" {
+		t.Errorf("Неверная страница истории: %+v", historyPage)
+	}
+	if code := get(chatPath+"/history?offset=100", token, &historyPage); code != http.StatusOK {
+		t.Fatalf("Ошибка истории: %d", code)
+	}
+	if historyPage.Total != 5 || historyPage.Items == nil || len(historyPage.Items) != 0 {
+		t.Errorf("Страница за пределами истории должна быть пустой: %+v", historyPage)
+	}
+
+	var today api.Today
+	if code := get(chatPath+"/today", token, &today); code != http.StatusOK {
+		t.Fatalf("Ошибка выбора дня: %d", code)
+	}
+	if today.User == nil || today.User.ID != anna.ID || today.Date != time.Now().Format("2006-01-02") {
+		t.Errorf("Неверный выбор дня: %+v", today)
+	}
+	if code := get(chatPath+"/today?nomination=hero", token, &today); code != http.StatusOK {
+		t.Fatalf("Ошибка выбора дня: %d", code)
+	}
+	if today.User != nil || today.Nomination != "hero" {
+		t.Errorf("В номинации сегодня еще никто не выбран: %+v", today)
+	}
+
+	// Отозванный токен перестает действовать
+	if err := tokenRepo.Delete(chatID); err != nil {
+		t.Fatalf("Ошибка удаления токена: %v", err)
+	}
+	if code := get(chatPath+"/today", token, nil); code != http.StatusUnauthorized {
+		t.Errorf("Отозванный токен должен отклоняться, получено %d", code)
+	}
+
+	// Шаблоны команды /pidorapi
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	for _, locale := range templates.SupportedLocales() {
+		messages := service.WithLocale(locale)
+		created := messages.APITokenCreated("Чат", token, chatPath)
+		if !strings.Contains(created, token) || !strings.Contains(created, chatPath) {
+			t.Errorf("%s: сообщение с токеном должно содержать токен и путь: %s", locale, created)
+		}
+		status := messages.BuildAPITokenStatus(&domain.APIToken{CreatedAt: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)})
+		if !strings.Contains(status, "2024") || !strings.Contains(status, "/pidorapi new") {
+			t.Errorf("%s: неверное состояние токена: %s", locale, status)
+		}
+	}
+}
+
+func TestWebLeaderboard(t *testing.T) {
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "web.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	defer db.Close()
+
+	const chatID int64 = -100
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	nominationRepo := repository.NewNominationRepository(db)
+	settingsRepo := repository.NewChatSettingsRepository(db)
+
+	ivan := domain.User{ID: 1, Username: "ivan", FirstName: "Иван", ChatID: chatID}
+	hacker := domain.User{ID: 2, FirstName: "<script>alert(1)</script>", ChatID: chatID}
+	for _, user := range []domain.User{ivan, hacker} {
+		if err := userRepo.Add(user); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+	}
+	hero := &domain.Nomination{ChatID: chatID, Command: "hero", Title: "Герой дня", Emoji: "🦸"}
+	if err := nominationRepo.Create(hero); err != nil {
+		t.Fatalf("Ошибка создания номинации: %v", err)
+	}
+
+	// Иван выигрывает три дня подряд в прошлом месяце, второй участник — сегодня
+	now := time.Now()
+	lastMonth := time.Date(now.Year(), now.Month()-1, 10, 0, 0, 0, 0, time.Local)
+	for i := 0; i < 3; i++ {
+		if err := personRepo.Set(ivan.ID, chatID, domain.DefaultNominationID, lastMonth.AddDate(0, 0, i)); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+	}
+	if err := personRepo.Set(hacker.ID, chatID, domain.DefaultNominationID, now); err != nil {
+		t.Fatalf("Ошибка сохранения выбора: %v", err)
+	}
+
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	linkClock := clock.NewFake(now)
+	links, err := web.NewLinks("https://bot.example.com/", "0123456789abcdef", time.Hour, linkClock)
+	if err != nil {
+		t.Fatalf("Ошибка создания ссылок: %v", err)
+	}
+	server := httptest.NewServer(web.NewHandler(personRepo, nominationRepo, settingsRepo, service, links, nil, logging.Discard()))
+	defer server.Close()
+
+	link, expires := links.Link(chatID)
+	if !strings.HasPrefix(link, "https://bot.example.com"+web.ChatPath(chatID)+"?") || expires.Before(now.Add(59*time.Minute)) {
+		t.Fatalf("Неверная ссылка %s до %s", link, expires)
+	}
+	path := strings.TrimPrefix(link, "https://bot.example.com")
+
+	get := func(path string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Ошибка запроса %s: %v", path, err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	code, page := get(path)
+	if code != http.StatusOK {
+		t.Fatalf("Ожидалась страница, получено %d: %s", code, page)
+	}
+	for _, expected := range []string{
+		"Таблица лидеров", "Иван (@ivan)", "75,0%", "Самая длинная серия: Иван (@ivan) — 3 дня подряд",
+		"Лучший месяц: Иван (@ivan) — 3 победы", "&lt;script&gt;", "Герой дня", "nomination=hero", web.StaticPath + "style.css",
+	} {
+		if !strings.Contains(page, expected) {
+			t.Errorf("Страница должна содержать %q", expected)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Error("Имя участника должно экранироваться")
+	}
+	if strings.Contains(page, `href="http`) || strings.Contains(page, "src=") {
+		t.Error("Страница не должна загружать внешние ресурсы")
+	}
+
+	// Язык страницы берется из настроек чата
+	if err := settingsRepo.SetLanguage(chatID, "en"); err != nil {
+		t.Fatalf("Ошибка смены языка: %v", err)
+	}
+	if _, page := get(path + "&nomination=hero"); !strings.Contains(page, "Nobody has been picked") || !strings.Contains(page, `lang="en"`) {
+		t.Errorf("Неверная страница номинации:\n%s", page)
+	}
+	if code, _ := get(path + "&nomination=missing"); code != http.StatusNotFound {
+		t.Errorf("Неизвестная номинация: ожидался 404, получено %d", code)
+	}
+
+	// Подпись привязана к чату и сроку действия
+	otherChat := strings.Replace(path, web.ChatPath(chatID), web.ChatPath(-200), 1)
+	extended := strings.Replace(path, "expires=", "expires=9", 1)
+	for _, invalid := range []string{web.ChatPath(chatID), otherChat, extended} {
+		code, page := get(invalid)
+		if code != http.StatusForbidden || !strings.Contains(page, "/pidorstats web") {
+			t.Errorf("%s: ожидался отказ 403, получено %d", invalid, code)
+		}
+	}
+
+	// Ссылка перестает действовать, когда часы доходят до срока действия
+	linkClock.Set(expires.Add(-time.Second))
+	if code, _ := get(path); code != http.StatusOK {
+		t.Errorf("Ссылка должна действовать до срока, получено %d", code)
+	}
+	linkClock.Set(expires)
+	if code, _ := get(path); code != http.StatusForbidden {
+		t.Errorf("Просроченная ссылка: ожидался отказ 403, получено %d", code)
+	}
+
+	resp, err := http.Get(server.URL + web.StaticPath + "style.css")
+	if err != nil {
+		t.Fatalf("Ошибка запроса стилей: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/css") {
+		t.Errorf("Стили должны отдаваться из бинарника, получено %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	// Ответ команды /pidorstats web
+	for _, locale := range templates.SupportedLocales() {
+		message := service.WithLocale(locale).WebLink(link, expires)
+		if !strings.Contains(message, link) || !strings.Contains(message, expires.Format("2006")) {
+			t.Errorf("%s: неверное сообщение со ссылкой: %s", locale, message)
+		}
+		if weekdays := service.WithLocale(locale).WebWeekdays(); len(weekdays) != 7 {
+			t.Errorf("%s: ожидалось 7 дней недели, получено %v", locale, weekdays)
+		}
+	}
+}
+
+func TestInlineQuery(t *testing.T) {
+	db, err := repository.NewDatabase(filepath.Join(t.TempDir(), "inline.db"), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка открытия базы данных: %v", err)
+	}
+	defer db.Close()
+
+	const chatID int64 = -100
+	userRepo := repository.NewUserRepository(db)
+	personRepo := repository.NewPersonOfTheDayRepository(db)
+	settingsRepo := repository.NewChatSettingsRepository(db)
+
+	ivan := domain.User{ID: 1, Username: "ivan", FirstName: "Иван", ChatID: chatID}
+	anna := domain.User{ID: 2, FirstName: "Анна", ChatID: chatID}
+	for _, user := range []domain.User{ivan, anna} {
+		if err := userRepo.Add(user); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+	}
+	for _, date := range []time.Time{time.Now().AddDate(0, 0, -2), time.Now().AddDate(0, 0, -1)} {
+		if err := personRepo.Set(anna.ID, chatID, domain.DefaultNominationID, date); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+	}
+	if err := settingsRepo.SetTitle(chatID, "Герой дня", "🦸"); err != nil {
+		t.Fatalf("Ошибка смены названия роли: %v", err)
+	}
+
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	fake := telegramtest.NewServer()
+	defer fake.Close()
+	api, err := telebot.NewBot(telebot.Settings{URL: fake.URL, Token: "test", Offline: true})
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+	handler := handlers.NewInlineHandler(userRepo, personRepo, settingsRepo, service, nil, logging.Discard())
+
+	type article struct {
+		ID          string `json:"id"`
+		Type        string `json:"type"`
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Content     struct {
+			Text string `json:"message_text"`
+		} `json:"input_message_content"`
+	}
+	type answer struct {
+		Results    []article         `json:"results"`
+		CacheTime  int               `json:"cache_time"`
+		IsPersonal bool              `json:"is_personal"`
+		Button     map[string]string `json:"button"`
+	}
+	query := func(user *telebot.User, text string) answer {
+		t.Helper()
+		update := telebot.Update{Query: &telebot.Query{ID: "q", Sender: user, Text: text}}
+		if err := handler.HandleQuery(api.NewContext(update)); err != nil {
+			t.Fatalf("Ошибка обработки инлайн-запроса: %v", err)
+		}
+		calls := fake.Calls("answerInlineQuery")
+		if len(calls) == 0 {
+			t.Fatal("Ожидался ответ answerInlineQuery")
+		}
+		data, _ := json.Marshal(calls[len(calls)-1].Params)
+		var result answer
+		if err := json.Unmarshal(data, &result); err != nil {
+			t.Fatalf("Ошибка разбора ответа: %v", err)
+		}
+		return result
+	}
+
+	result := query(&telebot.User{ID: ivan.ID, LanguageCode: "en"}, "")
+	if len(result.Results) != 3 || !result.IsPersonal || result.CacheTime != int(handlers.InlineCacheTTL.Seconds()) {
+		t.Fatalf("Неверный ответ: %+v", result)
+	}
+	me, stats, today := result.Results[0], result.Results[1], result.Results[2]
+	if me.Type != "article" || me.ID != "me:-100" || me.Title != "👤 My stats" || !strings.Contains(me.Content.Text, "not yet") {
+		t.Errorf("Неверная статистика участника: %+v", me)
+	}
+	if !strings.Contains(stats.Description, "Анна — 2 times") || !strings.Contains(stats.Content.Text, "Герой дня") {
+		t.Errorf("Неверная таблица лидеров: %+v", stats)
+	}
+	if today.Title != "🦸 Герой дня today" || !strings.Contains(today.Content.Text, "not") {
+		t.Errorf("Неверный выбор дня: %+v", today)
+	}
+
+	result = query(&telebot.User{ID: anna.ID, LanguageCode: "ru"}, "")
+	if me := result.Results[0]; me.Content.Text != "🦸 Анна: Герой дня 2 раза, 1 место из 2" {
+		t.Errorf("Неверная статистика участника: %q", me.Content.Text)
+	}
+
+	// Текст запроса отбирает результаты
+	result = query(&telebot.User{ID: anna.ID, LanguageCode: "ru"}, "лидер")
+	if len(result.Results) != 1 || result.Results[0].ID != "stats:-100" {
+		t.Errorf("Ожидалась только таблица лидеров, получено %+v", result.Results)
+	}
+
+	// Результаты берутся из кеша, пока не истечет InlineCacheTTL
+	if err := personRepo.Set(ivan.ID, chatID, domain.DefaultNominationID, time.Now()); err != nil {
+		t.Fatalf("Ошибка сохранения выбора: %v", err)
+	}
+	result = query(&telebot.User{ID: ivan.ID, LanguageCode: "en"}, "")
+	if !strings.Contains(result.Results[0].Content.Text, "not yet") {
+		t.Errorf("Ожидались результаты из кеша, получено %+v", result.Results[0])
+	}
+	result = query(&telebot.User{ID: ivan.ID, LanguageCode: "uk"}, "")
+	if !strings.Contains(result.Results[0].Content.Text, "1 раз") || !strings.Contains(result.Results[2].Content.Text, "Иван") {
+		t.Errorf("Новый язык должен строить результаты заново, получено %+v", result.Results)
+	}
+
+	// Участнику, которого бот не видел в группах, предлагается начать диалог
+	result = query(&telebot.User{ID: 99, LanguageCode: "en"}, "")
+	if len(result.Results) != 0 || result.Button["start_parameter"] != "inline" || !strings.Contains(result.Button["text"], "group") {
+		t.Errorf("Неверный ответ незнакомому участнику: %+v", result)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	reasons := []struct {
+		err    error
+		reason string
+	}{
+		{telebot.ErrBlockedByUser, metrics.ReasonBlocked},
+		{telebot.ErrKickedFromSuperGroup, metrics.ReasonKicked},
+		{telebot.ErrNoRightsToSend, metrics.ReasonNoRights},
+		{telebot.ErrNotFoundToReply, metrics.ReasonReplyNotFound},
+		{telebot.ErrChatNotFound, metrics.ReasonChatNotFound},
+		{telebot.FloodError{RetryAfter: 5}, metrics.ReasonFlood},
+		{telebot.NewError(429, "Too Many Requests"), metrics.ReasonFlood},
+		{telebot.ErrTooLongMessage, metrics.ReasonBadRequest},
+		{errors.New("telegram: Forbidden: bot is not a member of the supergroup chat (403)"), metrics.ReasonForbidden},
+		{errors.New("telegram: Bad Gateway (502)"), metrics.ReasonServer},
+		{fmt.Errorf("telebot: %w", errors.New("connection reset by peer")), metrics.ReasonNetwork},
+		{&net.OpError{Op: "dial", Err: errors.New("refused")}, metrics.ReasonNetwork},
+		{errors.New("something else"), metrics.ReasonOther},
+	}
+	for _, tc := range reasons {
+		if reason := metrics.SendErrorReason(tc.err); reason != tc.reason {
+			t.Errorf("Ошибка %v: ожидалась причина %s, получено %s", tc.err, tc.reason, reason)
+		}
+	}
+
+	metricsClock := clock.NewFake(time.Now())
+	collector := metrics.New(metricsClock)
+	updates := []telebot.Update{
+		{Message: &telebot.Message{Chat: &telebot.Chat{ID: 1}}},
+		{Message: &telebot.Message{Chat: &telebot.Chat{ID: 2}}},
+		{EditedMessage: &telebot.Message{Chat: &telebot.Chat{ID: 1}}},
+		{Query: &telebot.Query{}},
+	}
+	for i := range updates {
+		if !collector.ObserveUpdate(&updates[i]) {
+			t.Error("Метрики не должны отбрасывать обновления")
+		}
+	}
+	if active := collector.ActiveChats(); active != 2 {
+		t.Errorf("Ожидалось 2 активных чата, получено %d", active)
+	}
+	// Чат перестает быть активным через ActiveWindow после последнего обновления
+	metricsClock.Advance(metrics.ActiveWindow - time.Second)
+	collector.ObserveUpdate(&updates[1])
+	metricsClock.Advance(2 * time.Second)
+	if active := collector.ActiveChats(); active != 1 {
+		t.Errorf("Ожидался 1 активный чат после ActiveWindow, получено %d", active)
+	}
+	if kind := metrics.UpdateType(&updates[3]); kind != "inline_query" {
+		t.Errorf("Неверный тип обновления: %s", kind)
+	}
+}
+
+// repositorySet набор репозиториев одного хранилища для контрактных тестов
+type repositorySet struct {
+	users       repository.UserRepository
+	persons     repository.PersonOfTheDayRepository
+	settings    repository.ChatSettingsRepository
+	nominations repository.NominationRepository
+	aliases     repository.CommandAliasRepository
+	tokens      repository.APITokenRepository
+}
+
+func databaseRepositories(db *repository.Database) repositorySet {
+	return repositorySet{
+		users:       repository.NewUserRepository(db),
+		persons:     repository.NewPersonOfTheDayRepository(db),
+		settings:    repository.NewChatSettingsRepository(db),
+		nominations: repository.NewNominationRepository(db),
+		aliases:     repository.NewCommandAliasRepository(db),
+		tokens:      repository.NewAPITokenRepository(db),
+	}
+}
+
+// TestRepositoryContract проверяет одинаковое поведение репозиториев во всех драйверах,
+// включенных в сборку. PostgreSQL проверяется, если задана переменная TEST_POSTGRES_DSN.
+func TestRepositoryContract(t *testing.T) {
+	for _, driver := range repository.Drivers() {
+		t.Run(driver, func(t *testing.T) {
+			dsn := filepath.Join(t.TempDir(), "contract.db")
+			if driver == repository.DriverPostgres {
+				dsn = os.Getenv("TEST_POSTGRES_DSN")
+				if dsn == "" {
+					t.Skip("TEST_POSTGRES_DSN не задан")
+				}
+			}
+			runRepositoryContract(t, openContractDatabase(t, driver, dsn))
+		})
+	}
+
+	t.Run("memory", func(t *testing.T) {
+		runRepositoryContract(t, memoryRepositories(memory.NewStore(nil)))
+	})
+}
+
+func memoryRepositories(store *memory.Store) repositorySet {
+	return repositorySet{
+		users:       memory.NewUserRepository(store),
+		persons:     memory.NewPersonOfTheDayRepository(store),
+		settings:    memory.NewChatSettingsRepository(store),
+		nominations: memory.NewNominationRepository(store),
+		aliases:     memory.NewCommandAliasRepository(store),
+		tokens:      memory.NewAPITokenRepository(store),
+	}
+}
+
+// TestMemoryRepositoriesConcurrent проверяет, что одновременные выборы в памяти
+// сохраняют ровно одного участника дня, как уникальный индекс базы данных
+func TestMemoryRepositoriesConcurrent(t *testing.T) {
+	const chatID int64 = -100
+	store := memory.NewStore(nil)
+	users := memory.NewUserRepository(store)
+	persons := memory.NewPersonOfTheDayRepository(store)
+	today := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	var inserted atomic.Int32
+	for i := int64(1); i <= 20; i++ {
+		wg.Go(func() {
+			if err := users.Add(domain.User{ID: i, FirstName: fmt.Sprintf("Участник %d", i), ChatID: chatID}); err != nil {
+				t.Errorf("Ошибка добавления пользователя: %v", err)
+				return
+			}
+			ok, err := persons.Insert(i, chatID, domain.DefaultNominationID, today)
+			if err != nil {
+				t.Errorf("Ошибка сохранения выбора: %v", err)
+			}
+			if ok {
+				inserted.Add(1)
+			}
+			if _, err := persons.GetUserStats(chatID, domain.DefaultNominationID); err != nil {
+				t.Errorf("Ошибка статистики: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if inserted.Load() != 1 {
+		t.Errorf("Ожидался один сохраненный выбор, сохранено %d", inserted.Load())
+	}
+	if stored, err := users.GetByChatID(chatID); err != nil || len(stored) != 20 {
+		t.Errorf("Ожидалось 20 участников, получено %d, %v", len(stored), err)
+	}
+}
+
+func openContractDatabase(t *testing.T, driver, dsn string) repositorySet {
+	t.Helper()
+
+	db, err := repository.Open(driver, dsn, repository.DefaultOptions(), logging.Discard())
+	if err != nil {
+		t.Fatalf("Ошибка подключения к %s: %v", driver, err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Logf("Ошибка закрытия базы данных: %v", err)
+		}
+	})
+
+	return databaseRepositories(db)
+}
+
+// runRepositoryContract описывает контракт репозиториев. Идентификаторы уникальны
+// для каждого запуска, чтобы тесты не зависели от данных в общей базе.
+func runRepositoryContract(t *testing.T, repos repositorySet) {
+	base := time.Now().UnixNano() % 1_000_000_000_000
+	chatID := -base
+	otherChatID := -base - 1
+	today := time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+
+	t.Run("users", func(t *testing.T) {
+		ivan := domain.User{ID: base + 1, Username: "ivan", FirstName: "Иван", ChatID: chatID}
+		anna := domain.User{ID: base + 2, FirstName: "Анна", LastName: "Петрова", ChatID: chatID}
+		for _, user := range []domain.User{ivan, anna} {
+			if err := repos.users.Add(user); err != nil {
+				t.Fatalf("Ошибка добавления пользователя: %v", err)
+			}
+		}
+
+		ivan.FirstName = "Ваня"
+		if err := repos.users.Add(ivan); err != nil {
+			t.Fatalf("Ошибка обновления пользователя: %v", err)
+		}
+
+		users, err := repos.users.GetByChatID(chatID)
+		if err != nil {
+			t.Fatalf("Ошибка получения пользователей: %v", err)
+		}
+		if len(users) != 2 || users[0].FirstName != "Анна" || users[1].FirstName != "Ваня" {
+			t.Errorf("Ожидались Анна и Ваня по алфавиту, получено %+v", users)
+		}
+		if users[0].LastName != "Петрова" || users[1].Username != "ivan" || users[0].Username != "" {
+			t.Errorf("Неверные поля пользователей: %+v", users)
+		}
+
+		user, err := repos.users.GetByID(ivan.ID, chatID)
+		if err != nil || user == nil || user.FirstName != "Ваня" {
+			t.Errorf("Ожидался пользователь Ваня, получено %+v (%v)", user, err)
+		}
+		if user, err := repos.users.GetByID(base+99, chatID); err != nil || user != nil {
+			t.Errorf("Ожидалось отсутствие пользователя, получено %+v (%v)", user, err)
+		}
+
+		user, err = repos.users.GetByUserID(anna.ID)
+		if err != nil || user == nil || user.ChatID != chatID || user.LastName != "Петрова" {
+			t.Errorf("Ожидалась Анна с чатом %d, получено %+v (%v)", chatID, user, err)
+		}
+		if user, err := repos.users.GetByUserID(base + 99); err != nil || user != nil {
+			t.Errorf("Ожидалось отсутствие пользователя, получено %+v (%v)", user, err)
+		}
+	})
+
+	t.Run("person of the day", func(t *testing.T) {
+		if err := repos.persons.Set(base+1, chatID, domain.DefaultNominationID, today); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+		// Повторный выбор на ту же дату заменяет предыдущий
+		if err := repos.persons.Set(base+2, chatID, domain.DefaultNominationID, today); err != nil {
+			t.Fatalf("Ошибка замены выбора: %v", err)
+		}
+		if err := repos.persons.Set(base+2, chatID, domain.DefaultNominationID, yesterday); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+		if err := repos.persons.Set(base+1, chatID, base+500, today); err != nil {
+			t.Fatalf("Ошибка сохранения выбора в номинации: %v", err)
+		}
+
+		person, err := repos.persons.GetByDate(chatID, domain.DefaultNominationID, today)
+		if err != nil || person == nil || person.ID != base+2 {
+			t.Errorf("Ожидался пользователь %d, получено %+v (%v)", base+2, person, err)
+		}
+		person, err = repos.persons.GetByDate(chatID, base+500, today)
+		if err != nil || person == nil || person.ID != base+1 {
+			t.Errorf("Ожидался пользователь %d в номинации, получено %+v (%v)", base+1, person, err)
+		}
+		if person, err := repos.persons.GetByDate(chatID, domain.DefaultNominationID, today.AddDate(0, 0, 1)); err != nil || person != nil {
+			t.Errorf("Ожидалось отсутствие выбора, получено %+v (%v)", person, err)
+		}
+
+		stats, err := repos.persons.GetUserStats(chatID, domain.DefaultNominationID)
+		if err != nil {
+			t.Fatalf("Ошибка получения статистики: %v", err)
+		}
+		if len(stats) != 2 || stats[0].User.ID != base+2 || stats[0].Count != 2 || stats[1].Count != 0 {
+			t.Errorf("Неверная статистика: %+v", stats)
+		}
+
+		history, err := repos.persons.GetHistory(chatID)
+		if err != nil {
+			t.Fatalf("Ошибка получения истории: %v", err)
+		}
+		if len(history) != 3 {
+			t.Fatalf("Ожидалось 3 записи истории, получено %+v", history)
+		}
+		first, last := history[0], history[2]
+		if first.Date.Format("2006-01-02") != "2024-03-14" || first.User.FirstName != "Анна" ||
+			first.User.LastName != "Петрова" || first.Nomination != domain.DefaultNominationCommand {
+			t.Errorf("Неверная первая запись истории: %+v", first)
+		}
+		if last.Date.Format("2006-01-02") != "2024-03-15" || last.NominationID != base+500 ||
+			last.User.Username != "ivan" || last.Nomination != "" {
+			t.Errorf("Неверная последняя запись истории: %+v", last)
+		}
+
+		all, err := repos.persons.GetAllHistory()
+		if err != nil {
+			t.Fatalf("Ошибка получения истории всех чатов: %v", err)
+		}
+		count := 0
+		for _, record := range all {
+			if record.ChatID == chatID {
+				count++
+			}
+		}
+		if count != 3 {
+			t.Errorf("История всех чатов должна содержать 3 записи чата, получено %d", count)
+		}
+
+		// Insert не перезаписывает занятую дату, в отличие от Set
+		inserted, err := repos.persons.Insert(first.UserID, chatID, domain.DefaultNominationID, first.Date)
+		if err != nil || inserted {
+			t.Errorf("Insert не должен перезаписывать занятую дату: %v, %v", inserted, err)
+		}
+		inserted, err = repos.persons.Insert(first.UserID, chatID, domain.DefaultNominationID, first.Date.AddDate(0, 0, -1))
+		if err != nil || !inserted {
+			t.Errorf("Insert должен добавить свободную дату: %v, %v", inserted, err)
+		}
+
+		// ReplaceStale заменяет только выбор участника, который перешел в другой чат
+		staleNomination := base + 600
+		if replaced, err := repos.persons.ReplaceStale(base+1, chatID, staleNomination, today); err != nil || replaced {
+			t.Errorf("ReplaceStale не должен создавать выбор: %v, %v", replaced, err)
+		}
+		if err := repos.persons.Set(base+1, chatID, staleNomination, today); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+		if replaced, err := repos.persons.ReplaceStale(base+2, chatID, staleNomination, today); err != nil || replaced {
+			t.Errorf("ReplaceStale не должен заменять выбор участника чата: %v, %v", replaced, err)
+		}
+		if err := repos.users.Add(domain.User{ID: base + 3, FirstName: "Олег", ChatID: otherChatID}); err != nil {
+			t.Fatalf("Ошибка добавления пользователя: %v", err)
+		}
+		if err := repos.persons.Set(base+3, chatID, staleNomination, yesterday); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+		if replaced, err := repos.persons.ReplaceStale(base+2, chatID, staleNomination, yesterday); err != nil || !replaced {
+			t.Errorf("ReplaceStale должен заменить выбор участника другого чата: %v, %v", replaced, err)
+		}
+		if person, err := repos.persons.GetByDate(chatID, staleNomination, yesterday); err != nil || person == nil || person.ID != base+2 {
+			t.Errorf("Ожидался пользователь %d после замены, получено %+v (%v)", base+2, person, err)
+		}
+		if replaced, err := repos.persons.ReplaceStale(base+1, chatID, staleNomination, yesterday); err != nil || replaced {
+			t.Errorf("Повторная замена не должна перезаписывать новый выбор: %v, %v", replaced, err)
+		}
+	})
+
+	t.Run("chat settings", func(t *testing.T) {
+		settings, err := repos.settings.Get(otherChatID)
+		if err != nil || settings != nil {
+			t.Fatalf("Ожидалось отсутствие настроек, получено %+v (%v)", settings, err)
+		}
+
+		if err := repos.settings.SetLanguage(otherChatID, "en"); err != nil {
+			t.Fatalf("Ошибка сохранения языка: %v", err)
+		}
+		if err := repos.settings.SetTitle(otherChatID, "Герой дня", "🦸"); err != nil {
+			t.Fatalf("Ошибка сохранения названия: %v", err)
+		}
+		if err := repos.settings.SetLanguage(otherChatID, "uk"); err != nil {
+			t.Fatalf("Ошибка сохранения языка: %v", err)
+		}
+
+		settings, err = repos.settings.Get(otherChatID)
+		if err != nil || settings == nil {
+			t.Fatalf("Ожидались настройки, получено %+v (%v)", settings, err)
+		}
+		if settings.Language != "uk" || settings.Title != "Герой дня" || settings.Emoji != "🦸" {
+			t.Errorf("Неверные настройки: %+v", settings)
+		}
+	})
+
+	t.Run("nominations", func(t *testing.T) {
+		coffee := &domain.Nomination{ChatID: chatID, Command: "coffee", Title: "Кофе", Emoji: "☕", Strategy: domain.StrategyFair}
+		beer := &domain.Nomination{ChatID: chatID, Command: "beer", Title: "Пиво", Strategy: domain.StrategyRandom}
+		for _, nomination := range []*domain.Nomination{coffee, beer} {
+			if err := repos.nominations.Create(nomination); err != nil {
+				t.Fatalf("Ошибка создания номинации: %v", err)
+			}
+		}
+		if coffee.ID == domain.DefaultNominationID || coffee.ID == beer.ID {
+			t.Errorf("Номинациям должны назначаться разные ID, получено %d и %d", coffee.ID, beer.ID)
+		}
+		if err := repos.nominations.Create(&domain.Nomination{ChatID: chatID, Command: "coffee", Title: "Дубль"}); err == nil {
+			t.Error("Ожидалась ошибка при повторной команде номинации")
+		}
+
+		nominations, err := repos.nominations.GetByChatID(chatID)
+		if err != nil {
+			t.Fatalf("Ошибка получения номинаций: %v", err)
+		}
+		if len(nominations) != 2 || nominations[0].Command != "beer" || nominations[1].Strategy != domain.StrategyFair {
+			t.Errorf("Неверный список номинаций: %+v", nominations)
+		}
+
+		found, err := repos.nominations.GetByCommand(chatID, "coffee")
+		if err != nil || found == nil || found.ID != coffee.ID || found.Emoji != "☕" {
+			t.Errorf("Ожидалась номинация coffee, получено %+v (%v)", found, err)
+		}
+		if found, err := repos.nominations.GetByCommand(otherChatID, "coffee"); err != nil || found != nil {
+			t.Errorf("Номинация не должна находиться в другом чате, получено %+v (%v)", found, err)
+		}
+
+		if err := repos.persons.Set(base+1, chatID, coffee.ID, today); err != nil {
+			t.Fatalf("Ошибка сохранения выбора в номинации: %v", err)
+		}
+		if err := repos.nominations.Delete(chatID, "coffee"); err != nil {
+			t.Fatalf("Ошибка удаления номинации: %v", err)
+		}
+		if person, err := repos.persons.GetByDate(chatID, coffee.ID, today); err != nil || person != nil {
+			t.Errorf("История удаленной номинации должна удаляться, получено %+v (%v)", person, err)
+		}
+		if person, err := repos.persons.GetByDate(chatID, domain.DefaultNominationID, today); err != nil || person == nil {
+			t.Errorf("История основной номинации не должна удаляться (%v)", err)
+		}
+		if err := repos.nominations.Delete(chatID, "missing"); err != nil {
+			t.Errorf("Удаление несуществующей номинации не должно возвращать ошибку: %v", err)
+		}
+	})
+
+	t.Run("aliases", func(t *testing.T) {
+		if err := repos.aliases.Set(chatID, "hero", "pidor"); err != nil {
+			t.Fatalf("Ошибка сохранения алиаса: %v", err)
+		}
+		if err := repos.aliases.Set(chatID, "hero", "pidorstats"); err != nil {
+			t.Fatalf("Ошибка изменения алиаса: %v", err)
+		}
+		if err := repos.aliases.Set(chatID, "chart", "pidorstats"); err != nil {
+			t.Fatalf("Ошибка сохранения алиаса: %v", err)
+		}
+
+		alias, err := repos.aliases.Get(chatID, "hero")
+		if err != nil || alias == nil || alias.Command != "pidorstats" {
+			t.Errorf("Ожидался алиас hero → pidorstats, получено %+v (%v)", alias, err)
+		}
+
+		aliases, err := repos.aliases.GetByChatID(chatID)
+		if err != nil || len(aliases) != 2 || aliases[0].Alias != "chart" {
+			t.Errorf("Неверный список алиасов: %+v (%v)", aliases, err)
+		}
+
+		if err := repos.aliases.Delete(chatID, "hero"); err != nil {
+			t.Fatalf("Ошибка удаления алиаса: %v", err)
+		}
+		if alias, err := repos.aliases.Get(chatID, "hero"); err != nil || alias != nil {
+			t.Errorf("Алиас должен быть удален, получено %+v (%v)", alias, err)
+		}
+	})
+
+	t.Run("api tokens", func(t *testing.T) {
+		firstHash := fmt.Sprintf("first-%d", base)
+		secondHash := fmt.Sprintf("second-%d", base)
+		if err := repos.tokens.Set(chatID, firstHash, base+1); err != nil {
+			t.Fatalf("Ошибка сохранения токена: %v", err)
+		}
+		// Новый токен заменяет предыдущий
+		if err := repos.tokens.Set(chatID, secondHash, base+2); err != nil {
+			t.Fatalf("Ошибка замены токена: %v", err)
+		}
+
+		token, err := repos.tokens.GetByChatID(chatID)
+		if err != nil || token == nil || token.TokenHash != secondHash || token.CreatedBy != base+2 || token.CreatedAt.IsZero() {
+			t.Errorf("Неверный токен чата: %+v (%v)", token, err)
+		}
+		if token, err := repos.tokens.GetByHash(firstHash); err != nil || token != nil {
+			t.Errorf("Замененный токен не должен находиться, получено %+v (%v)", token, err)
+		}
+		if token, err := repos.tokens.GetByHash(secondHash); err != nil || token == nil || token.ChatID != chatID {
+			t.Errorf("Токен не найден по хешу: %+v (%v)", token, err)
+		}
+		if token, err := repos.tokens.GetByChatID(otherChatID); err != nil || token != nil {
+			t.Errorf("У другого чата не должно быть токена, получено %+v (%v)", token, err)
+		}
+
+		if err := repos.tokens.Delete(chatID); err != nil {
+			t.Fatalf("Ошибка удаления токена: %v", err)
+		}
+		if token, err := repos.tokens.GetByHash(secondHash); err != nil || token != nil {
+			t.Errorf("Токен должен быть удален, получено %+v (%v)", token, err)
+		}
+	})
+}
+
+func TestStatsImage(t *testing.T) {
+	const chatID int64 = -100
+	now := time.Date(2024, time.February, 20, 12, 0, 0, 0, time.Local)
+
+	var stats []domain.UserStats
+	var records []domain.PersonOfTheDayRecord
+	for i := 0; i < render.MaxRows+5; i++ {
+		user := domain.User{ID: int64(i + 1), FirstName: fmt.Sprintf("Участник 🎉 %d", i+1), ChatID: chatID}
+		stats = append(stats, domain.UserStats{User: user, Count: render.MaxRows + 5 - i})
+	}
+	// Лидер выигрывает 1 февраля, выбор другой номинации в календарь не попадает
+	records = append(records,
+		domain.PersonOfTheDayRecord{
+			PersonOfTheDay: domain.PersonOfTheDay{UserID: 1, NominationID: domain.DefaultNominationID, Date: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.Local)},
+			User:           stats[0].User,
+		},
+		domain.PersonOfTheDayRecord{
+			PersonOfTheDay: domain.PersonOfTheDay{UserID: 2, NominationID: 42, Date: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.Local)},
+			User:           stats[1].User,
+		},
+	)
+
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	labels := render.Labels{
+		Title:       service.StatsImageTitle(),
+		Leaderboard: service.WebLeaderboard(),
+		ColumnUser:  service.WebColumnUser(),
+		ColumnWins:  service.WebColumnWins(),
+		Calendar:    service.WebCalendar(),
+		Month:       service.WebCalendarMonth(now),
+		Weekdays:    service.WebWeekdays(),
+		Rest:        service.StatsImageRest(5),
+	}
+	if labels.Rest != "и еще 5 участников" {
+		t.Errorf("Неверная подпись об остальных участниках: %q", labels.Rest)
+	}
+
+	decode := func(stats []domain.UserStats, labels render.Labels) image.Image {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := render.Stats(&buf, stats, records, domain.DefaultNominationID, now, labels); err != nil {
+			t.Fatalf("Ошибка отрисовки: %v", err)
+		}
+		img, err := png.Decode(&buf)
+		if err != nil {
+			t.Fatalf("Ожидался PNG: %v", err)
+		}
+		return img
+	}
+
+	full := decode(stats, labels)
+	labels.Rest = ""
+	short := decode(stats[:3], labels)
+	if full.Bounds().Dx() != short.Bounds().Dx() {
+		t.Errorf("Ширина картинки не должна зависеть от числа участников: %v и %v", full.Bounds(), short.Bounds())
+	}
+	if full.Bounds().Dy() <= short.Bounds().Dy() {
+		t.Errorf("Таблица должна расти с числом участников: %v и %v", full.Bounds(), short.Bounds())
+	}
+
+	// Цвет лидера есть в таблице и в календаре, цвет второго места — только в таблице
+	count := func(img image.Image, r, g, b uint8) int {
+		var n int
+		bounds := img.Bounds()
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA); c.R == r && c.G == g && c.B == b {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	leader, second := count(short, 0xcf, 0x22, 0x2e), count(short, 0x2f, 0x81, 0xf7)
+	if leader <= second*2 || second == 0 {
+		t.Errorf("Ожидалась клетка календаря цвета лидера: лидер %d пикселей, второе место %d", leader, second)
+	}
+}
+
+// e2eBot настоящий бот с репозиториями в памяти и всеми обработчиками, подключенный
+// к поддельному Bot API: тест пишет в чат от имени участников и читает ответы бота.
+// Часы бота идут только по команде теста.
+type e2eBot struct {
+	t       *testing.T
+	server  *telegramtest.Server
+	clock   *clock.Fake
+	users   repository.UserRepository
+	persons repository.PersonOfTheDayRepository
+}
+
+// startE2EBot запускает бота через long polling; бот останавливается в t.Cleanup
+func startE2EBot(t *testing.T) *e2eBot {
+	t.Helper()
+
+	server := telegramtest.NewServer()
+	// Обработчикам не нужен диск: репозитории в памяти проходят тот же контракт, что и база данных
+	botClock := clock.NewFake(time.Now())
+	store := memory.NewStore(botClock)
+	service, err := templates.NewMessageService()
+	if err != nil {
+		t.Fatalf("Ошибка создания сервиса сообщений: %v", err)
+	}
+	api, err := server.Bot()
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+
+	e2e := &e2eBot{
+		t:       t,
+		server:  server,
+		clock:   botClock,
+		users:   memory.NewUserRepository(store),
+		persons: memory.NewPersonOfTheDayRepository(store),
+	}
+	botInstance := bot.NewBot(api,
+		e2e.users,
+		e2e.persons,
+		memory.NewChatSettingsRepository(store),
+		memory.NewNominationRepository(store),
+		memory.NewCommandAliasRepository(store),
+		memory.NewAPITokenRepository(store),
+		nil,
+		service,
+		e2e.clock,
+		nil,
+		logging.Discard(),
+	)
+
+	started := make(chan error, 1)
+	go func() { started <- botInstance.Start() }()
+	t.Cleanup(func() {
+		// Закрытый сервер сразу отвечает на getUpdates, поэтому остановка не ждет long polling
+		server.Close()
+		botInstance.Stop()
+		if err := <-started; err != nil {
+			t.Errorf("Ошибка запуска бота: %v", err)
+		}
+	})
+
+	return e2e
+}
+
+// send пишет сообщение в чат и ждет ответ бота на него
+func (e *e2eBot) send(chat *telebot.Chat, from *telebot.User, text string) telegramtest.Message {
+	e.t.Helper()
+	return e.reply(e.server.SendText(chat, from, text))
+}
+
+// reply ждет ответ бота на сообщение
+func (e *e2eBot) reply(message *telebot.Message) telegramtest.Message {
+	e.t.Helper()
+
+	answer, err := e.server.Next(5 * time.Second)
+	if err != nil {
+		e.t.Fatalf("Бот не ответил на %q: %v", message.Text+message.Caption, err)
+	}
+	if answer.ChatID != message.Chat.ID || answer.ReplyTo != message.ID {
+		e.t.Fatalf("Ответ на %q пришел не туда: %+v", message.Text+message.Caption, answer)
+	}
+	return answer
+}
+
+// TestEndToEndMidnight проверяет, что новый день в часах бота открывает новый выбор
+func TestEndToEndMidnight(t *testing.T) {
+	e2e := startE2EBot(t)
+	e2e.clock.Set(time.Date(2024, 3, 15, 23, 59, 59, 0, time.Local))
+
+	group := &telebot.Chat{ID: -100, Type: telebot.ChatSuperGroup, Title: "Тестовая группа"}
+	ivan := &telebot.User{ID: 1001, FirstName: "Иван", LanguageCode: "ru"}
+
+	if answer := e2e.send(group, ivan, "/pidor"); !strings.Contains(answer.Text, "выбран") || strings.Contains(answer.Text, "уже выбран") {
+		t.Fatalf("Ожидался новый выбор, получено %q", answer.Text)
+	}
+	if answer := e2e.send(group, ivan, "/pidor"); !strings.Contains(answer.Text, "уже выбран") {
+		t.Errorf("До полуночи выбор не должен повторяться, получено %q", answer.Text)
+	}
+
+	e2e.clock.Advance(time.Second)
+	if answer := e2e.send(group, ivan, "/pidor"); strings.Contains(answer.Text, "уже выбран") {
+		t.Errorf("После полуночи ожидался новый выбор, получено %q", answer.Text)
+	}
+
+	history, err := e2e.persons.GetHistory(group.ID)
+	if err != nil || len(history) != 2 {
+		t.Fatalf("Ожидалось два выбора, получено %d (%v)", len(history), err)
+	}
+	if first, second := history[0].Date.Format("2006-01-02"), history[1].Date.Format("2006-01-02"); first != "2024-03-15" || second != "2024-03-16" {
+		t.Errorf("Неверные даты выборов: %s, %s", first, second)
+	}
+}
+
+func TestEndToEnd(t *testing.T) {
+	e2e := startE2EBot(t)
+
+	group := &telebot.Chat{ID: -100, Type: telebot.ChatSuperGroup, Title: "Тестовая группа"}
+	ivan := &telebot.User{ID: 1001, FirstName: "Иван", Username: "ivan", LanguageCode: "ru"}
+	anna := &telebot.User{ID: 1002, FirstName: "Анна", LanguageCode: "ru"}
+
+	if answer := e2e.send(&telebot.Chat{ID: ivan.ID, Type: telebot.ChatPrivate}, ivan, "/pidor"); answer.Text != "Этот бот работает только в группах!" {
+		t.Errorf("Ожидалось предупреждение о группах, получено %q", answer.Text)
+	}
+
+	if answer := e2e.send(group, ivan, "/help"); !strings.Contains(answer.Text, "/pidorstats") {
+		t.Errorf("Ожидалась справка, получено %q", answer.Text)
+	}
+	if len(e2e.server.Calls("setMyCommands")) == 0 {
+		t.Error("Ожидалась публикация меню команд")
+	}
+
+	// Сообщение без команды только регистрирует участника; ответ на следующую команду
+	// проверяет, что бот промолчал
+	e2e.server.SendText(group, anna, "Всем привет")
+	answer := e2e.send(group, ivan, "/pidor")
+	if !strings.Contains(answer.Text, "выбран") {
+		t.Fatalf("Ожидался выбор участника, получено %q", answer.Text)
+	}
+	winner := "Иван"
+	if strings.Contains(answer.Text, "Анна") {
+		winner = "Анна"
+	} else if !strings.Contains(answer.Text, winner) {
+		t.Fatalf("Выбран незнакомый участник: %q", answer.Text)
+	}
+	if users, err := e2e.users.GetByChatID(group.ID); err != nil || len(users) != 2 {
+		t.Errorf("Ожидалось два участника чата, получено %d (%v)", len(users), err)
+	}
+
+	if answer := e2e.send(group, anna, "/pidor"); !strings.Contains(answer.Text, "уже выбран") || !strings.Contains(answer.Text, winner) {
+		t.Errorf("Повторный выбор должен вернуть того же участника %s, получено %q", winner, answer.Text)
+	}
+	if answer := e2e.send(group, anna, "/pidorstats@DayBot"); !strings.Contains(answer.Text, "🥇 "+winner) {
+		t.Errorf("Ожидалась статистика с победителем, получено %q", answer.Text)
+	}
+
+	photo := e2e.send(group, anna, "/pidorstats img")
+	if photo.Method != "sendPhoto" || !strings.Contains(photo.Text, "Статистика") {
+		t.Fatalf("Ожидалась картинка статистики, получено %+v", photo)
+	}
+	if _, err := png.Decode(bytes.NewReader(photo.File)); err != nil {
+		t.Errorf("Ожидался PNG: %v", err)
+	}
+
+	// Команды администраторов: роль участника берется из getChatMember
+	if answer := e2e.send(group, anna, "/pidortitle 🦸 Герой дня"); answer.Text != "Эта команда доступна только администраторам чата." {
+		t.Errorf("Ожидался отказ не администратору, получено %q", answer.Text)
+	}
+	e2e.server.SetMemberStatus(group.ID, ivan.ID, telebot.Administrator)
+	if answer := e2e.send(group, ivan, "/pidortitle 🦸 Герой дня"); answer.Text != "✅ Новое название роли: 🦸 Герой дня" {
+		t.Errorf("Ожидалась смена названия роли, получено %q", answer.Text)
+	}
+	if answer := e2e.send(group, anna, "/pidorinfo"); !strings.Contains(answer.Text, "🦸 Герой дня сегодня: "+winner) {
+		t.Errorf("Ожидалась информация с новым названием роли, получено %q", answer.Text)
+	}
+
+	export := e2e.send(group, ivan, "/pidorexport")
+	if export.Method != "sendDocument" || !strings.HasSuffix(export.FileName, ".csv") || !strings.Contains(string(export.File), winner) {
+		t.Fatalf("Ожидался CSV с историей, получено %+v", export)
+	}
+
+	// Импорт скачивает файл через getFile; выбор на ту же дату уже есть и пропускается
+	message := e2e.server.SendDocument(group, ivan, "/pidorimport", export.FileName, export.File)
+	if answer := e2e.reply(message); !strings.Contains(answer.Text, "добавлено 0 из 1, пропущено занятых дат: 1") {
+		t.Errorf("Ожидался отчет об импорте, получено %q", answer.Text)
+	}
+	if len(e2e.server.Calls("getFile")) != 1 {
+		t.Errorf("Ожидалась загрузка файла через getFile, вызовов: %d", len(e2e.server.Calls("getFile")))
+	}
+}
+
+// TestEndToEndNominations проверяет, что команды номинаций вместе с командами
+// статистики и информации не перекрывают друг друга
+func TestEndToEndNominations(t *testing.T) {
+	e2e := startE2EBot(t)
+
+	group := &telebot.Chat{ID: -100, Type: telebot.ChatSuperGroup, Title: "Тестовая группа"}
+	ivan := &telebot.User{ID: 1001, FirstName: "Иван", LanguageCode: "ru"}
+	e2e.server.SetMemberStatus(group.ID, ivan.ID, telebot.Administrator)
+
+	expect := func(text, expected string) {
+		t.Helper()
+		if answer := e2e.send(group, ivan, text); !strings.HasPrefix(answer.Text, expected) {
+			t.Errorf("%s: ожидалось %q, получено %q", text, expected, answer.Text)
+		}
+	}
+
+	// Новая команда совпадает с командой статистики существующей номинации
+	expect("/pidornom add coffee random ☕ Кофевар дня", "✅ Номинация добавлена")
+	expect("/pidornom add coffeestats random Статистик дня", "Команда /coffeestats уже занята")
+	expect("/pidornom add coffeeinfo random Информатор дня", "Команда /coffeeinfo уже занята")
+
+	// Команда статистики новой номинации совпадает с существующей номинацией
+	expect("/pidornom add teainfo random Чайный информатор", "✅ Номинация добавлена")
+	expect("/pidornom add tea random 🍵 Чаевар дня", "Команда /teainfo уже занята")
+
+	expect("/coffeestats", "📊 Статистика \"Кофевар дня\"")
+	expect("/teainfo", "🎉 Чайный информатор выбран!")
+
+	// Алиас с именем команды статистики перехватил бы ее у новой номинации
+	expect("/pidoralias add juicestats pidorstats", "✅ Алиас добавлен")
+	expect("/pidornom add juice random 🧃 Сок дня", "Команда /juicestats уже занята")
+
+	// Алиасы удаленной номинации удаляются вместе с ней и не переходят к новой номинации
+	expect("/pidoralias add brew coffee", "✅ Алиас добавлен")
+	expect("/pidoralias add brewtop coffeestats", "✅ Алиас добавлен")
+	expect("/pidornom remove coffee", "🗑 Номинация /coffee удалена")
+	expect("/pidoralias", "🔀 Алиасы команд чата:\n\n/juicestats → /pidorstats\n")
+	expect("/pidornom add coffee random 🍪 Печенька дня", "✅ Номинация добавлена")
+	expect("/brew@DayBot", "Неизвестная команда")
+}
+
+// failingPicks выборы, которые возвращают err из методов сервиса core, если она задана.
+// beforeReplace вызывается перед заменой выбора, чтобы тест мог собрать параллельные замены вместе.
+type failingPicks struct {
+	repository.PersonOfTheDayRepository
+	err           error
+	beforeReplace func()
+}
+
+func (r *failingPicks) Set(userID, chatID, nominationID int64, date time.Time) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.beforeReplace != nil {
+		r.beforeReplace()
+	}
+	return r.PersonOfTheDayRepository.Set(userID, chatID, nominationID, date)
+}
+
+func (r *failingPicks) Insert(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	return r.PersonOfTheDayRepository.Insert(userID, chatID, nominationID, date)
+}
+
+func (r *failingPicks) ReplaceStale(userID, chatID, nominationID int64, date time.Time) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	if r.beforeReplace != nil {
+		r.beforeReplace()
+	}
+	return r.PersonOfTheDayRepository.ReplaceStale(userID, chatID, nominationID, date)
+}
+
+func (r *failingPicks) GetByDate(chatID, nominationID int64, date time.Time) (*domain.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.PersonOfTheDayRepository.GetByDate(chatID, nominationID, date)
+}
+
+func (r *failingPicks) GetUserStats(chatID, nominationID int64) ([]domain.UserStats, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.PersonOfTheDayRepository.GetUserStats(chatID, nominationID)
+}
+
+func TestCoreService(t *testing.T) {
+	const chatID int64 = -100
+	ivan := domain.User{ID: 1, FirstName: "Иван", ChatID: chatID}
+	anna := domain.User{ID: 2, FirstName: "Анна", ChatID: chatID}
+	oleg := domain.User{ID: 3, FirstName: "Олег", ChatID: chatID}
+	yesterday := time.Now().AddDate(0, 0, -1)
+	errStorage := errors.New("storage is down")
+
+	// newService создает сервис с участниками и выборами основной номинации в прошлые дни
+	newService := func(t *testing.T, clk clock.Clock, users []domain.User, wins map[int64]int) (*core.Service, *failingPicks, repository.ChatSettingsRepository) {
+		t.Helper()
+		store := memory.NewStore(clk)
+		userRepo := memory.NewUserRepository(store)
+		picks := &failingPicks{PersonOfTheDayRepository: memory.NewPersonOfTheDayRepository(store)}
+		day := yesterday
+		for _, user := range users {
+			if err := userRepo.Add(user); err != nil {
+				t.Fatalf("Ошибка добавления участника: %v", err)
+			}
+			for i := 0; i < wins[user.ID]; i++ {
+				if err := picks.Set(user.ID, chatID, domain.DefaultNominationID, day); err != nil {
+					t.Fatalf("Ошибка сохранения выбора: %v", err)
+				}
+				day = day.AddDate(0, 0, -1)
+			}
+		}
+		settings := memory.NewChatSettingsRepository(store)
+		return core.NewService(userRepo, picks, settings, rand.New(rand.NewSource(1)), clk), picks, settings
+	}
+	// picked возвращает число сохраненных выборов чата
+	picked := func(t *testing.T, picks repository.PersonOfTheDayRepository) int {
+		t.Helper()
+		history, err := picks.GetHistory(chatID)
+		if err != nil {
+			t.Fatalf("Ошибка получения истории: %v", err)
+		}
+		return len(history)
+	}
+	// storedSettings возвращает сохраненные настройки чата
+	storedSettings := func(t *testing.T, settings repository.ChatSettingsRepository) domain.ChatSettings {
+		t.Helper()
+		stored, err := settings.Get(chatID)
+		if err != nil || stored == nil {
+			t.Fatalf("Ожидались сохраненные настройки: %+v, %v", stored, err)
+		}
+		return *stored
+	}
+
+	random := domain.Nomination{ChatID: chatID, Strategy: domain.StrategyRandom}
+	rotation := domain.Nomination{ChatID: chatID, Strategy: domain.StrategyRotation}
+
+	t.Run("draw", func(t *testing.T) {
+		for _, tc := range []struct {
+			name       string
+			users      []domain.User
+			wins       map[int64]int
+			today      *domain.User
+			nomination domain.Nomination
+			err        error
+			want       []int64
+			already    bool
+		}{
+			{name: "нет участников", nomination: random, err: core.ErrNoActiveUsers},
+			{name: "единственный участник", users: []domain.User{ivan}, nomination: random, want: []int64{ivan.ID}},
+			{name: "уже выбран сегодня", users: []domain.User{ivan, anna}, today: &anna, nomination: random, want: []int64{anna.ID}, already: true},
+			{name: "очередь выбирает без побед", users: []domain.User{ivan, anna, oleg}, wins: map[int64]int{ivan.ID: 2, oleg.ID: 1}, nomination: rotation, want: []int64{anna.ID}},
+			{name: "случайный выбор среди всех", users: []domain.User{ivan, anna}, wins: map[int64]int{ivan.ID: 5}, nomination: random, want: []int64{ivan.ID, anna.ID}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				service, picks, _ := newService(t, nil, tc.users, tc.wins)
+				if tc.today != nil {
+					if err := picks.Set(tc.today.ID, chatID, domain.DefaultNominationID, time.Now()); err != nil {
+						t.Fatalf("Ошибка сохранения выбора: %v", err)
+					}
+				}
+				before := picked(t, picks)
+
+				result, err := service.Draw(tc.nomination)
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("Ожидалась ошибка %v, получено %v", tc.err, err)
+				}
+				if tc.err != nil {
+					return
+				}
+				if !slices.Contains(tc.want, result.User.ID) || result.AlreadySelected != tc.already {
+					t.Errorf("Неверный выбор: %+v, ожидался один из %v", result, tc.want)
+				}
+
+				saved := picked(t, picks) - before
+				if tc.already && saved != 0 || !tc.already && saved != 1 {
+					t.Errorf("Сохранено выборов: %d", saved)
+				}
+				if again, err := service.Draw(tc.nomination); err != nil || !again.AlreadySelected || again.User.ID != result.User.ID {
+					t.Errorf("Повторный выбор должен вернуть того же участника: %+v, %v", again, err)
+				}
+			})
+		}
+	})
+
+	// Одновременные /pidor объявляют одного участника: выбор не перезаписывается,
+	// в том числе когда сегодняшний выбор принадлежит участнику другого чата
+	for _, stale := range []bool{false, true} {
+		name := "одновременный выбор"
+		if stale {
+			name += " вместо выбора участника другого чата"
+		}
+		t.Run(name, func(t *testing.T) {
+			petr := domain.User{ID: 4, FirstName: "Петр", ChatID: chatID - 1}
+			service, picks, _ := newService(t, nil, []domain.User{ivan, anna, oleg, petr}, nil)
+			if stale {
+				// Выбор Петра сделан, пока он был в чате: в чате он больше не виден
+				if err := picks.Set(petr.ID, chatID, domain.DefaultNominationID, time.Now()); err != nil {
+					t.Fatalf("Ошибка сохранения выбора: %v", err)
+				}
+			}
+			before := picked(t, picks)
+
+			const draws = 20
+			if stale {
+				// Все выборы доходят до замены устаревшего выбора прежде, чем кто-то из них его заменит
+				var arrived sync.WaitGroup
+				arrived.Add(draws)
+				picks.beforeReplace = func() {
+					arrived.Done()
+					arrived.Wait()
+				}
+			}
+			results := make([]core.DrawResult, draws)
+			var wg sync.WaitGroup
+			for i := range draws {
+				wg.Go(func() {
+					result, err := service.Draw(random)
+					if err != nil {
+						t.Errorf("Ошибка выбора: %v", err)
+					}
+					results[i] = result
+				})
+			}
+			wg.Wait()
+
+			selected := 0
+			for _, result := range results {
+				if !result.AlreadySelected {
+					selected++
+				}
+				if result.User.ID != results[0].User.ID || result.User.ID == petr.ID {
+					t.Errorf("Объявлены разные участники: %s и %s", result.User.FirstName, results[0].User.FirstName)
+				}
+			}
+			if selected != 1 {
+				t.Errorf("Новый выбор должен объявить ровно один вызов, объявили %d", selected)
+			}
+			if saved := picked(t, picks) - before; !stale && saved != 1 || stale && saved != 0 {
+				t.Errorf("Неверное число сохраненных выборов: %d", saved)
+			}
+			if today, err := picks.GetByDate(chatID, random.ID, time.Now()); err != nil || today == nil || today.ID != results[0].User.ID {
+				t.Errorf("Сохранен не объявленный участник: %+v, %v", today, err)
+			}
+		})
+	}
+
+	t.Run("ошибка хранилища", func(t *testing.T) {
+		service, picks, _ := newService(t, nil, []domain.User{ivan}, nil)
+		picks.err = errStorage
+
+		if _, err := service.Draw(random); !errors.Is(err, errStorage) {
+			t.Errorf("Draw: ожидалась ошибка хранилища, получено %v", err)
+		}
+		if _, err := service.Stats(random); !errors.Is(err, errStorage) {
+			t.Errorf("Stats: ожидалась ошибка хранилища, получено %v", err)
+		}
+		if _, err := service.Info(random); !errors.Is(err, errStorage) {
+			t.Errorf("Info: ожидалась ошибка хранилища, получено %v", err)
+		}
+	})
+
+	t.Run("stats и info", func(t *testing.T) {
+		service, picks, _ := newService(t, nil, []domain.User{ivan, anna, oleg}, map[int64]int{anna.ID: 3, oleg.ID: 1})
+
+		stats, err := service.Stats(random)
+		if err != nil {
+			t.Fatalf("Ошибка статистики: %v", err)
+		}
+		var order []string
+		for _, stat := range stats {
+			order = append(order, fmt.Sprintf("%s:%d", stat.User.FirstName, stat.Count))
+		}
+		if strings.Join(order, " ") != "Анна:3 Олег:1 Иван:0" {
+			t.Errorf("Неверная статистика: %v", order)
+		}
+
+		info, err := service.Info(random)
+		if err != nil || info.Users != 3 || info.Participants != 3 || info.Today != nil {
+			t.Errorf("Неверная информация до выбора: %+v, %v", info, err)
+		}
+		if err := picks.Set(ivan.ID, chatID, domain.DefaultNominationID, time.Now()); err != nil {
+			t.Fatalf("Ошибка сохранения выбора: %v", err)
+		}
+		if info, err := service.Info(random); err != nil || info.Today == nil || info.Today.ID != ivan.ID {
+			t.Errorf("Ожидался сегодняшний выбор в информации: %+v, %v", info, err)
+		}
+
+		// Другая номинация считает победы отдельно
+		if stats, err := service.Stats(domain.Nomination{ID: 7, ChatID: chatID}); err != nil || stats[0].Count != 0 {
+			t.Errorf("Статистика другой номинации должна быть пустой: %+v, %v", stats, err)
+		}
+	})
+
+	// Выбор привязан к календарному дню в часовом поясе часов, а не к 24 часам
+	t.Run("смена дня", func(t *testing.T) {
+		location := func(name string) *time.Location {
+			t.Helper()
+			loc, err := time.LoadLocation(name)
+			if err != nil {
+				t.Fatalf("Ошибка загрузки часового пояса %s: %v", name, err)
+			}
+			return loc
+		}
+		moscow, newYork := location("Europe/Moscow"), location("America/New_York")
+		advance := func(d time.Duration) func(*clock.Fake) { return func(c *clock.Fake) { c.Advance(d) } }
+		move := func(loc *time.Location) func(*clock.Fake) { return func(c *clock.Fake) { c.SetLocation(loc) } }
+
+		type step struct {
+			change  func(*clock.Fake)
+			already bool
+			date    string
+		}
+		for _, tc := range []struct {
+			name  string
+			start time.Time
+			steps []step
+		}{
+			{name: "полночь", start: time.Date(2024, 3, 15, 23, 59, 59, 0, moscow), steps: []step{
+				{date: "2024-03-15"},
+				{change: advance(time.Second), date: "2024-03-16"},
+				{change: advance(24*time.Hour - time.Second), already: true, date: "2024-03-16"},
+			}},
+			{name: "смена часового пояса", start: time.Date(2024, 3, 15, 1, 0, 0, 0, moscow), steps: []step{
+				{date: "2024-03-15"},
+				// В UTC этот момент еще 14 марта
+				{change: move(time.UTC), date: "2024-03-14"},
+				{change: move(moscow), already: true, date: "2024-03-15"},
+			}},
+			{name: "переход на летнее время", start: time.Date(2024, 3, 10, 0, 30, 0, 0, newYork), steps: []step{
+				{date: "2024-03-10"},
+				// 10 марта длится 23 часа: через 23 часа уже следующий день
+				{change: advance(23 * time.Hour), date: "2024-03-11"},
+			}},
+			{name: "переход на зимнее время", start: time.Date(2024, 11, 3, 0, 30, 0, 0, newYork), steps: []step{
+				{date: "2024-11-03"},
+				// 3 ноября длится 25 часов: через 24 часа тот же день
+				{change: advance(24 * time.Hour), already: true, date: "2024-11-03"},
+				{change: advance(time.Hour), date: "2024-11-04"},
+			}},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				fake := clock.NewFake(tc.start)
+				service, picks, _ := newService(t, fake, []domain.User{ivan, anna}, nil)
+
+				for i, step := range tc.steps {
+					if step.change != nil {
+						step.change(fake)
+					}
+					result, err := service.Draw(random)
+					if err != nil {
+						t.Fatalf("Шаг %d: ошибка выбора: %v", i, err)
+					}
+					if result.AlreadySelected != step.already {
+						t.Errorf("Шаг %d (%s): AlreadySelected = %v, ожидалось %v", i, fake.Now(), result.AlreadySelected, step.already)
+					}
+
+					info, err := service.Info(random)
+					if err != nil || info.Today == nil || info.Today.ID != result.User.ID {
+						t.Errorf("Шаг %d: информация должна показать сегодняшний выбор: %+v, %v", i, info, err)
+					}
+
+					history, err := picks.GetHistory(chatID)
+					if err != nil {
+						t.Fatalf("Ошибка получения истории: %v", err)
+					}
+					dates := make([]string, 0, len(history))
+					for _, record := range history {
+						dates = append(dates, record.Date.Format("2006-01-02"))
+					}
+					if !slices.Contains(dates, step.date) {
+						t.Errorf("Шаг %d: ожидался выбор на %s, сохранены даты %v", i, step.date, dates)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("settings", func(t *testing.T) {
+		service, _, settings := newService(t, nil, nil, nil)
+
+		for _, tc := range []struct {
+			code string
+			want templates.Locale
+			err  error
+		}{
+			{code: "en", want: templates.LocaleEn},
+			{code: "uk", want: templates.LocaleUk},
+			{code: "de", err: core.ErrInvalidLanguage},
+		} {
+			locale, err := service.SetLanguage(chatID, tc.code)
+			if !errors.Is(err, tc.err) || locale != tc.want {
+				t.Errorf("SetLanguage(%q) = %q, %v; ожидалось %q, %v", tc.code, locale, err, tc.want, tc.err)
+			}
+		}
+		if stored := storedSettings(t, settings); stored.Language != "uk" {
+			t.Errorf("Ожидался сохраненный язык uk, получено %+v", stored)
+		}
+
+		for _, tc := range []struct {
+			payload     string
+			title       string
+			emoji       string
+			err         error
+			storedTitle string
+		}{
+			{payload: "🦸 Герой дня", title: "Герой дня", emoji: "🦸", storedTitle: "Герой дня"},
+			{payload: "Кофевар 2 уровня", title: "Кофевар 2 уровня", storedTitle: "Кофевар 2 уровня"},
+			{payload: "🦸 " + strings.Repeat("я", templates.MaxTitleLength+1), err: core.ErrInvalidTitle, storedTitle: "Кофевар 2 уровня"},
+			// Одно слово без букв считается названием, а не эмодзи
+			{payload: "🦸", title: "🦸", storedTitle: "🦸"},
+			{payload: " reset ", storedTitle: ""},
+		} {
+			title, emoji, err := service.SetTitle(chatID, tc.payload)
+			if !errors.Is(err, tc.err) || title != tc.title || emoji != tc.emoji {
+				t.Errorf("SetTitle(%q) = %q, %q, %v", tc.payload, title, emoji, err)
+			}
+			if stored := storedSettings(t, settings).Title; stored != tc.storedTitle {
+				t.Errorf("SetTitle(%q): сохранено название %q, ожидалось %q", tc.payload, stored, tc.storedTitle)
+			}
+		}
+	})
+}